@@ -1,14 +1,24 @@
 package app
 
 import (
+	"context"
 	"go.uber.org/fx"
 	"my_go/config"
 	"my_go/controller"
+	"my_go/gateway"
+	"my_go/gateway/canada"
+	"my_go/gateway/czech"
+	"my_go/gateway/ecb"
+	"my_go/gateway/hungary"
+	"my_go/gateway/poland"
 	"my_go/gateway/russia"
+	"my_go/gateway/sdmx"
 	"my_go/gateway/thailand"
 	"my_go/handler"
 	"my_go/logger"
 	"my_go/repository"
+	"my_go/scheduler"
+	"net"
 	"net/http"
 )
 
@@ -16,17 +26,51 @@ var Module = fx.Options(
 	handler.Module,
 	config.Module,
 	repository.Module,
+	scheduler.Module,
 	controller.Module,
 	logger.Module,
-	fx.Provide(russia.New),
-	fx.Provide(thailand.New),
+	gateway.Module,
+	russia.Module,
+	thailand.Module,
+	ecb.Module,
+	czech.Module,
+	poland.Module,
+	hungary.Module,
+	canada.Module,
+	sdmx.Module,
 	fx.Invoke(StartAndListen),
 )
 
-func StartAndListen(h handler.Handler) {
+// StartAndListen registers the http server in the application lifecycle,
+// so it is started along with the other background subsystems (e.g. scheduler) and stopped gracefully.
+func StartAndListen(lc fx.Lifecycle, h handler.Handler) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/get_exchange_rates", h.GetCBRates)
 	mux.HandleFunc("/convert", h.ConvertCurrency)
+	mux.HandleFunc("/convert/batch", h.ConvertBatch)
+	mux.HandleFunc("/convert/multi", h.ConvertMulti)
+	mux.HandleFunc("/convert/compare", h.ConvertCompare)
+	mux.HandleFunc("/rates/timeseries", h.GetTimeSeries)
+	mux.HandleFunc("/rates/matrix", h.GetRateMatrix)
+	mux.HandleFunc("/banks", h.ListBanks)
+	mux.HandleFunc("/currencies", h.ListCurrencies)
+	mux.HandleFunc("/metals", h.GetMetals)
 	mux.HandleFunc("/hello", h.Hello)
-	http.ListenAndServe(":8000", mux)
+	server := &http.Server{
+		Addr:    ":8000",
+		Handler: mux,
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			go server.Serve(ln)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
 }
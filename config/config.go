@@ -2,6 +2,7 @@ package config
 
 import (
 	"go.uber.org/config"
+	"time"
 )
 
 func New() (config.Provider, error) {
@@ -14,15 +15,111 @@ func New() (config.Provider, error) {
 }
 
 type RussiaCBConfig struct {
+	APIURL           string `yaml:"api_url,omitempty"`
+	DynamicAPIURL    string `yaml:"dynamic_api_url,omitempty"`
+	MetalsAPIURL     string `yaml:"metals_api_url,omitempty"`
+	CurrenciesAPIURL string `yaml:"currencies_api_url,omitempty"`
+	Timezone         string `yaml:"timezone,omitempty"`
+}
+
+type ThailandCBConfig struct {
+	APIURL           string `yaml:"api_url,omitempty"`
+	HistoricalAPIURL string `yaml:"historical_api_url,omitempty"`
+	APIKey           string `yaml:"api_key,omitempty"`
+	Timezone         string `yaml:"timezone,omitempty"`
+}
+
+// ECBConfig configures the gateway of European Central Bank, APIURL is the eurofxref daily feed
+type ECBConfig struct {
 	APIURL   string `yaml:"api_url,omitempty"`
 	Timezone string `yaml:"timezone,omitempty"`
 }
 
-type ThailandCBConfig struct {
+// CzechCBConfig configures the gateway of Czech National Bank, APIURL is the daily text feed (denni_kurz.txt)
+type CzechCBConfig struct {
+	APIURL   string `yaml:"api_url,omitempty"`
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// PolandCBConfig configures the gateway of National Bank of Poland, APIURL is the JSON tables API of table A
+type PolandCBConfig struct {
+	APIURL   string `yaml:"api_url,omitempty"`
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// HungaryCBConfig configures the gateway of Magyar Nemzeti Bank, APIURL is the SOAP web service (arfolyamok.asmx)
+type HungaryCBConfig struct {
+	APIURL   string `yaml:"api_url,omitempty"`
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// CanadaCBConfig configures the gateway of Bank of Canada, APIURL is the Valet observations of FX_RATES_DAILY group
+type CanadaCBConfig struct {
 	APIURL   string `yaml:"api_url,omitempty"`
 	Timezone string `yaml:"timezone,omitempty"`
 }
 
+// SDMXBankConfig configures a central bank publishing the rates via SDMX. Such banks are served by the
+// generic SDMX gateway, so a bank is onboarded by adding the config block without writing the code.
+// Format is either sdmx-json or sdmx-ml (structure-specific data). KeyDimensions is the order of the
+// dimensions the series keys are built of, it is required for sdmx-ml as the message doesn't define it.
+// Series maps the series key (values of the dimensions joined by dot, e.g. "B.USD.NOK.SP") to the currency.
+// Inverse is set when the bank quotes the amount of the currency for units of the base currency.
+type SDMXBankConfig struct {
+	Country       string                      `yaml:"country,omitempty"`
+	Name          string                      `yaml:"name,omitempty"`
+	BaseCurrency  string                      `yaml:"base_currency,omitempty"`
+	Website       string                      `yaml:"website,omitempty"`
+	APIURL        string                      `yaml:"api_url,omitempty"`
+	Format        string                      `yaml:"format,omitempty"`
+	KeyDimensions []string                    `yaml:"key_dimensions,omitempty"`
+	Series        map[string]SDMXSeriesConfig `yaml:"series,omitempty"`
+	Inverse       bool                        `yaml:"inverse,omitempty"`
+	Timezone      string                      `yaml:"timezone,omitempty"`
+}
+
+// SDMXSeriesConfig is the currency the series is quoted for, Nominal is the amount of the currency
+// the observation value is set for (1 if omitted).
+type SDMXSeriesConfig struct {
+	Currency string `yaml:"currency,omitempty"`
+	Nominal  int    `yaml:"nominal,omitempty"`
+}
+
+// Defaults configures the fallbacks of the external API requests. RateTypes maps the country to the rate type
+// used for the conversion with the central bank of the country if the request doesn't specify one.
 type Defaults struct {
-	DefaultCB string `yaml:"default_cb"`
+	DefaultCB string            `yaml:"default_cb"`
+	RateTypes map[string]string `yaml:"rate_types,omitempty"`
+}
+
+// CompareConfig configures the comparison of the conversion across all the central banks.
+// Timeout is the time every central bank is given to convert the pair, Timeouts override it by country.
+type CompareConfig struct {
+	Timeout  time.Duration            `yaml:"timeout,omitempty"`
+	Timeouts map[string]time.Duration `yaml:"timeouts,omitempty"`
+}
+
+type StorageConfig struct {
+	Backend string `yaml:"backend,omitempty"`
+	Path    string `yaml:"path,omitempty"`
+}
+
+// StaleRatesConfig is a policy of serving the last known rates when central bank is unreachable.
+// MaxStaleness maps country to the max time passed since the rates stopped being actual
+// that is still acceptable, countries without max staleness are never served with stale rates.
+type StaleRatesConfig struct {
+	MaxStaleness        map[string]time.Duration `yaml:"max_staleness,omitempty"`
+	RetryInitialBackoff time.Duration            `yaml:"retry_initial_backoff,omitempty"`
+	RetryMaxBackoff     time.Duration            `yaml:"retry_max_backoff,omitempty"`
+}
+
+// SchedulerConfig defines the background refresh of the central bank rates.
+// Schedules maps country to the cron expression (CRON_TZ prefix is supported) of the refresh times.
+// Every refresh is delayed by random jitter up to Jitter and retried RetryAttempts times with backoff.
+type SchedulerConfig struct {
+	Enabled       bool              `yaml:"enabled,omitempty"`
+	Jitter        time.Duration     `yaml:"jitter,omitempty"`
+	RetryAttempts int               `yaml:"retry_attempts,omitempty"`
+	RetryBackoff  time.Duration     `yaml:"retry_backoff,omitempty"`
+	Schedules     map[string]string `yaml:"schedules,omitempty"`
 }
@@ -4,30 +4,47 @@ import (
 	"context"
 	"go.uber.org/fx"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/mapper"
 	"my_go/repository"
+	"my_go/repository/directory"
+	"sync"
 )
 
 type Controller interface {
 	GetCBRates(ctx context.Context, req *entity.GetExchangeRatesRequest) (*entity.GetExchangeRatesResponse, error)
 	GetExchangeRate(ctx context.Context, req *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error)
+	GetExchangeRateBatch(ctx context.Context, reqs []*entity.GetExchangeRateRequest) []entity.GetExchangeRateResult
+	GetCBRatesTimeSeries(
+		ctx context.Context,
+		req *entity.GetCBRatesTimeSeriesRequest,
+	) (*entity.GetCBRatesTimeSeriesResponse, error)
+	ListBanks(ctx context.Context) (*entity.ListBanksResponse, error)
+	GetMetals(ctx context.Context, req *entity.GetMetalsRequest) (*entity.GetMetalsResponse, error)
+	ListCurrencies(ctx context.Context) (*entity.ListCurrenciesResponse, error)
 }
 
 var _ Controller = (*controller)(nil)
 
 type controller struct {
 	repository repository.CBR
+	registry   *gateway.Registry
+	directory  directory.Directory
 }
 
 type Params struct {
 	fx.In
 
 	Repository repository.CBR
+	Registry   *gateway.Registry
+	Directory  directory.Directory
 }
 
 func New(p Params) (Controller, error) {
 	return &controller{
 		repository: p.Repository,
+		registry:   p.Registry,
+		directory:  p.Directory,
 	}, nil
 }
 
@@ -47,6 +64,15 @@ func (c *controller) GetCBRates(
 	if err != nil {
 		return nil, err
 	}
+	if r.Base != "" || len(r.Currencies) > 0 {
+		resp.Rates, err = mapper.RebaseRates(resp.Rates, r.Base, r.Currencies)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.IncludeNames {
+		resp.Currencies = mapper.RatesToCurrencies(resp.Rates, c.directory.Currencies(ctx))
+	}
 	return resp, nil
 }
 
@@ -56,3 +82,77 @@ func (c *controller) GetExchangeRate(
 ) (*entity.GetExchangeRateResponse, error) {
 	return c.repository.GetExchangeRate(ctx, req)
 }
+
+func (c *controller) GetExchangeRateBatch(
+	ctx context.Context,
+	reqs []*entity.GetExchangeRateRequest,
+) []entity.GetExchangeRateResult {
+	return c.repository.GetExchangeRateBatch(ctx, reqs)
+}
+
+func (c *controller) GetCBRatesTimeSeries(
+	ctx context.Context,
+	req *entity.GetCBRatesTimeSeriesRequest,
+) (*entity.GetCBRatesTimeSeriesResponse, error) {
+	return c.repository.GetCBRatesTimeSeries(ctx, req)
+}
+
+// ListBanks returns all the central banks registered in the gateway registry
+func (c *controller) ListBanks(_ context.Context) (*entity.ListBanksResponse, error) {
+	return mapper.RegistrationsToListBanksResponse(c.registry.Registrations()), nil
+}
+
+// GetMetals returns precious metals prices set by the central bank of the country provided in the request
+func (c *controller) GetMetals(
+	ctx context.Context,
+	r *entity.GetMetalsRequest,
+) (*entity.GetMetalsResponse, error) {
+	req, err := mapper.GetMetalsRequestToGetCBMetalsRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.repository.GetCBMetals(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := mapper.GetCBMetalsResponseToGetMetalsResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListCurrencies returns the reference data of all the known currencies along with the central banks quoting them.
+// The latest rates of all the central banks are loaded concurrently, banks which rates couldn't be loaded
+// are listed as unavailable instead of failing the request.
+func (c *controller) ListCurrencies(ctx context.Context) (*entity.ListCurrenciesResponse, error) {
+	countries := c.registry.Countries()
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		quotes = make(map[string]*entity.ExchangeRates, len(countries))
+	)
+	for _, country := range countries {
+		wg.Add(1)
+		go func(country string) {
+			defer wg.Done()
+			data, err := c.repository.GetCBRates(ctx, &entity.GetCBRatesRequest{Country: country})
+			if err != nil || data == nil || data.Rates == nil {
+				return
+			}
+			mu.Lock()
+			quotes[country] = data.Rates
+			mu.Unlock()
+		}(country)
+	}
+	currencies := c.directory.Currencies(ctx)
+	wg.Wait()
+
+	var unavailable []string
+	for _, country := range countries {
+		if _, ok := quotes[country]; !ok {
+			unavailable = append(unavailable, country)
+		}
+	}
+	return mapper.CurrenciesToListCurrenciesResponse(currencies, countries, quotes, unavailable), nil
+}
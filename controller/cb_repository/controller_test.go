@@ -4,10 +4,16 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/mapper"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
 	repositorymock "my_go/mocks/repository"
+	directorymock "my_go/mocks/repository/directory"
+	"my_go/utils"
 	"testing"
 	"time"
 )
@@ -30,6 +36,7 @@ func Test_controller_GetCBRates(t *testing.T) {
 		name           string
 		args           args
 		mockRepository *mockRepository
+		mockDirectory  map[string]entity.Currency
 		want           *entity.GetExchangeRatesResponse
 		assertion      assert.ErrorAssertionFunc
 	}{
@@ -49,13 +56,13 @@ func Test_controller_GetCBRates(t *testing.T) {
 								Nominal:          100,
 								BaseCurrency:     "RUB",
 								TargetCurrency:   "USD",
-								RateTargetToBase: 123.56,
+								RateTargetToBase: decimal.RequireFromString("123.56"),
 							},
 							"JPY": {
 								Nominal:          10,
 								BaseCurrency:     "RUB",
 								TargetCurrency:   "JPY",
-								RateTargetToBase: 987.65,
+								RateTargetToBase: decimal.RequireFromString("987.65"),
 							},
 						},
 					},
@@ -63,23 +70,139 @@ func Test_controller_GetCBRates(t *testing.T) {
 				err: nil,
 			},
 			want: &entity.GetExchangeRatesResponse{
+				Date: "2023-01-01",
 				Rates: map[string]entity.Rate{
 					"USD": {
 						Nominal:          100,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "USD",
-						RateTargetToBase: 123.56,
+						RateTargetToBase: decimal.RequireFromString("123.56"),
 					},
 					"JPY": {
 						Nominal:          10,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "JPY",
-						RateTargetToBase: 987.65,
+						RateTargetToBase: decimal.RequireFromString("987.65"),
 					},
 				},
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "Happy path, names included",
+			args: args{
+				r: &entity.GetExchangeRatesRequest{
+					Country:      "russia",
+					IncludeNames: true,
+				},
+			},
+			mockRepository: &mockRepository{
+				res: &entity.GetCBRatesResponse{
+					Rates: &entity.ExchangeRates{
+						Country:    "russia",
+						DateLoaded: "2023-01-01",
+						TimeZone:   ruTZ,
+						Rates: map[string]entity.Rate{
+							"USD": {
+								Nominal:          1,
+								BaseCurrency:     "RUB",
+								TargetCurrency:   "USD",
+								RateTargetToBase: decimal.RequireFromString("81.55"),
+							},
+						},
+					},
+				},
+				err: nil,
+			},
+			mockDirectory: map[string]entity.Currency{
+				"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2},
+				"EUR": {Code: "EUR", NumericCode: "978", Name: "Euro", MinorUnits: 2},
+			},
+			want: &entity.GetExchangeRatesResponse{
+				Date: "2023-01-01",
+				Rates: map[string]entity.Rate{
+					"USD": {
+						Nominal:          1,
+						BaseCurrency:     "RUB",
+						TargetCurrency:   "USD",
+						RateTargetToBase: decimal.RequireFromString("81.55"),
+					},
+				},
+				Currencies: map[string]entity.Currency{
+					"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, rebased",
+			args: args{
+				r: &entity.GetExchangeRatesRequest{
+					Country:    "russia",
+					Base:       "USD",
+					Currencies: []string{"RUB"},
+				},
+			},
+			mockRepository: &mockRepository{
+				res: &entity.GetCBRatesResponse{
+					Rates: &entity.ExchangeRates{
+						Country:    "russia",
+						DateLoaded: "2023-01-01",
+						TimeZone:   ruTZ,
+						Rates: map[string]entity.Rate{
+							"RUB": {
+								Nominal:          1,
+								BaseCurrency:     "RUB",
+								TargetCurrency:   "RUB",
+								RateTargetToBase: decimal.RequireFromString("1"),
+							},
+							"USD": {
+								Nominal:          100,
+								BaseCurrency:     "RUB",
+								TargetCurrency:   "USD",
+								RateTargetToBase: decimal.RequireFromString("8155.56"),
+							},
+						},
+					},
+				},
+				err: nil,
+			},
+			want: &entity.GetExchangeRatesResponse{
+				Date: "2023-01-01",
+				Rates: map[string]entity.Rate{
+					"RUB": {
+						Nominal:          1,
+						BaseCurrency:     "USD",
+						TargetCurrency:   "RUB",
+						RateTargetToBase: decimal.RequireFromString("81.5556"),
+						Inverse:          true,
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "base currency not supported",
+			args: args{
+				r: &entity.GetExchangeRatesRequest{
+					Country: "russia",
+					Base:    "GBP",
+				},
+			},
+			mockRepository: &mockRepository{
+				res: &entity.GetCBRatesResponse{
+					Rates: &entity.ExchangeRates{
+						Country:    "russia",
+						DateLoaded: "2023-01-01",
+						TimeZone:   ruTZ,
+						Rates:      map[string]entity.Rate{},
+					},
+				},
+				err: nil,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 		{
 			name: "nil request",
 			args: args{
@@ -125,8 +248,13 @@ func Test_controller_GetCBRates(t *testing.T) {
 						Return(tt.mockRepository.res, tt.mockRepository.err)
 				}
 			}
+			mockdirectory := directorymock.NewMockDirectory(ctrl)
+			if tt.mockDirectory != nil {
+				mockdirectory.EXPECT().Currencies(ctx).Return(tt.mockDirectory)
+			}
 			c := &controller{
 				repository: mockrepository,
+				directory:  mockdirectory,
 			}
 			got, err := c.GetCBRates(ctx, tt.args.r)
 			tt.assertion(t, err)
@@ -169,7 +297,7 @@ func Test_controller_GetExchangeRate(t *testing.T) {
 					Country:          "russia",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
 				},
 			},
 			mockRepository: &mockRepository{
@@ -178,7 +306,7 @@ func Test_controller_GetExchangeRate(t *testing.T) {
 						Nominal:          10,
 						BaseCurrency:     "GBP",
 						TargetCurrency:   "USD",
-						RateTargetToBase: 12.402,
+						RateTargetToBase: decimal.RequireFromString("12.402"),
 					},
 				},
 				err: nil,
@@ -188,7 +316,7 @@ func Test_controller_GetExchangeRate(t *testing.T) {
 					Nominal:          10,
 					BaseCurrency:     "GBP",
 					TargetCurrency:   "USD",
-					RateTargetToBase: 12.402,
+					RateTargetToBase: decimal.RequireFromString("12.402"),
 				},
 			},
 			assertion: assert.NoError,
@@ -200,7 +328,7 @@ func Test_controller_GetExchangeRate(t *testing.T) {
 					Country:          "russia",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
 				},
 			},
 			mockRepository: &mockRepository{
@@ -230,3 +358,276 @@ func Test_controller_GetExchangeRate(t *testing.T) {
 		})
 	}
 }
+
+func Test_controller_GetCBRatesTimeSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	req := &entity.GetCBRatesTimeSeriesRequest{
+		Country:    "russia",
+		Currencies: []string{"USD", "EUR"},
+		StartDate:  "2023-04-14",
+		EndDate:    "2023-04-18",
+	}
+	type mockRepository struct {
+		res *entity.GetCBRatesTimeSeriesResponse
+		err error
+	}
+	tests := []struct {
+		name           string
+		mockRepository *mockRepository
+		want           *entity.GetCBRatesTimeSeriesResponse
+		assertion      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			mockRepository: &mockRepository{
+				res: &entity.GetCBRatesTimeSeriesResponse{
+					Rates: []entity.ExchangeRates{
+						{
+							Country:    "russia",
+							DateLoaded: "2023-04-14",
+						},
+					},
+				},
+			},
+			want: &entity.GetCBRatesTimeSeriesResponse{
+				Rates: []entity.ExchangeRates{
+					{
+						Country:    "russia",
+						DateLoaded: "2023-04-14",
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "repository fails",
+			mockRepository: &mockRepository{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockrepository := repositorymock.NewMockCBR(ctrl)
+			mockrepository.
+				EXPECT().
+				GetCBRatesTimeSeries(ctx, req).
+				Return(tt.mockRepository.res, tt.mockRepository.err)
+			c := &controller{
+				repository: mockrepository,
+			}
+			got, err := c.GetCBRatesTimeSeries(ctx, req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_GetExchangeRateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	reqs := []*entity.GetExchangeRateRequest{
+		{Country: "russia", BaseCurrencyID: "USD", TargetCurrencyID: "RUB", Amount: decimal.RequireFromString("10")},
+		{Country: "russia", BaseCurrencyID: "ABC", TargetCurrencyID: "RUB", Amount: decimal.RequireFromString("10")},
+	}
+	want := []entity.GetExchangeRateResult{
+		{Response: &entity.GetExchangeRateResponse{Amount: decimal.RequireFromString("800")}},
+		{Err: errors.New("currency ABC not supported")},
+	}
+	mockrepository := repositorymock.NewMockCBR(ctrl)
+	mockrepository.EXPECT().GetExchangeRateBatch(ctx, reqs).Return(want)
+	c := &controller{
+		repository: mockrepository,
+	}
+	assert.Equal(t, want, c.GetExchangeRateBatch(ctx, reqs))
+}
+
+func Test_controller_ListBanks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	registry, err := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{
+				Bank: gateway.Bank{
+					Country:      "thailand",
+					Name:         "Bank of Thailand",
+					BaseCurrency: "THB",
+				},
+				Gateway: thailandgatewaymock.NewMockGateway(ctrl),
+			},
+			{
+				Bank: gateway.Bank{
+					Country:      "russia",
+					Name:         "Central Bank of the Russian Federation",
+					BaseCurrency: "RUB",
+					TimeZone:     ruTZ,
+					Website:      "https://www.cbr.ru",
+				},
+				Gateway: russiagatewaymock.NewMockGateway(ctrl),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	c := &controller{
+		repository: repositorymock.NewMockCBR(ctrl),
+		registry:   registry,
+	}
+	got, err := c.ListBanks(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.ListBanksResponse{
+		Banks: []entity.Bank{
+			{
+				Country:      "russia",
+				Name:         "Central Bank of the Russian Federation",
+				BaseCurrency: "RUB",
+				TimeZone:     "Europe/Moscow",
+				Website:      "https://www.cbr.ru",
+				Historical:   true,
+				TimeSeries:   true,
+			},
+			{
+				Country:      "thailand",
+				Name:         "Bank of Thailand",
+				BaseCurrency: "THB",
+				Historical:   true,
+				TimeSeries:   true,
+			},
+		},
+	}, got)
+}
+
+func Test_controller_GetMetals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	prices := map[string]entity.MetalPrice{
+		"XAU": {
+			Metal:    "XAU",
+			Name:     "gold",
+			Currency: "RUB",
+			Buy:      decimal.RequireFromString("5061.8"),
+			Sell:     decimal.RequireFromString("5061.8"),
+		},
+	}
+	type mockRepository struct {
+		res *entity.GetCBMetalsResponse
+		err error
+	}
+	tests := []struct {
+		name           string
+		req            *entity.GetMetalsRequest
+		mockRepository *mockRepository
+		want           *entity.GetMetalsResponse
+		assertion      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			req:  &entity.GetMetalsRequest{Country: "russia"},
+			mockRepository: &mockRepository{
+				res: &entity.GetCBMetalsResponse{
+					Prices: &entity.MetalPrices{
+						Country:       "russia",
+						DateLoaded:    "2023-04-20",
+						EffectiveDate: "2023-04-19",
+						Prices:        prices,
+					},
+				},
+			},
+			want: &entity.GetMetalsResponse{
+				Date:   "2023-04-19",
+				Prices: prices,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			req:       &entity.GetMetalsRequest{Country: "russia", Date: utils.ToPointer("19.04.2023")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "repository fails",
+			req:  &entity.GetMetalsRequest{Country: "thailand"},
+			mockRepository: &mockRepository{
+				err: errors.New("metal prices are not supported for country thailand"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "repository returns nil and no error",
+			req:  &entity.GetMetalsRequest{Country: "russia"},
+			mockRepository: &mockRepository{
+				res: nil,
+				err: nil,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockrepository := repositorymock.NewMockCBR(ctrl)
+			if tt.mockRepository != nil {
+				req, _ := mapper.GetMetalsRequestToGetCBMetalsRequest(tt.req)
+				mockrepository.
+					EXPECT().
+					GetCBMetals(ctx, req).
+					Return(tt.mockRepository.res, tt.mockRepository.err)
+			}
+			c := &controller{
+				repository: mockrepository,
+			}
+			got, err := c.GetMetals(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_ListCurrencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	registry, err := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{Bank: gateway.Bank{Country: "thailand"}, Gateway: thailandgatewaymock.NewMockGateway(ctrl)},
+			{Bank: gateway.Bank{Country: "russia"}, Gateway: russiagatewaymock.NewMockGateway(ctrl)},
+		},
+	})
+	assert.NoError(t, err)
+	mockrepository := repositorymock.NewMockCBR(ctrl)
+	mockrepository.EXPECT().GetCBRates(ctx, &entity.GetCBRatesRequest{Country: "russia"}).Return(&entity.GetCBRatesResponse{
+		Rates: &entity.ExchangeRates{
+			Country: "russia",
+			Rates:   map[string]entity.Rate{"RUB": {}, "USD": {}},
+		},
+	}, nil)
+	mockrepository.EXPECT().GetCBRates(ctx, &entity.GetCBRatesRequest{Country: "thailand"}).
+		Return(nil, errors.New("timeout"))
+	mockdirectory := directorymock.NewMockDirectory(ctrl)
+	mockdirectory.EXPECT().Currencies(ctx).Return(map[string]entity.Currency{
+		"RUB": {Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2},
+		"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2},
+	})
+	c := &controller{
+		repository: mockrepository,
+		registry:   registry,
+		directory:  mockdirectory,
+	}
+	got, err := c.ListCurrencies(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.ListCurrenciesResponse{
+		Currencies: []entity.Currency{
+			{Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2, Banks: []string{"russia"}},
+			{Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2, Banks: []string{"russia"}},
+		},
+		UnavailableBanks: []string{"thailand"},
+	}, got)
+}
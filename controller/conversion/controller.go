@@ -2,19 +2,36 @@ package conversion
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go.uber.org/config"
 	"go.uber.org/fx"
 	internalconfig "my_go/config"
 	repositorycontroller "my_go/controller/cb_repository"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/mapper"
+	"sync"
+	"time"
 )
 
-const defaults = "defaults"
+const (
+	defaults = "defaults"
+	compare  = "compare"
+)
+
+// defaultCompareTimeout is the time every central bank is given to convert the pair of the compare request
+// if no timeout is configured
+const defaultCompareTimeout = 5 * time.Second
 
 // Controller is a interface to provide currency conversion data
 type Controller interface {
 	Convert(ctx context.Context, req *entity.ConvertCurrencyRequest) (*entity.ConvertCurrencyResponse, error)
+	ConvertBatch(ctx context.Context, req *entity.ConvertBatchRequest) (*entity.ConvertBatchResponse, error)
+	ConvertMulti(ctx context.Context, req *entity.ConvertMultiRequest) (*entity.ConvertMultiResponse, error)
+	GetRateMatrix(ctx context.Context, req *entity.GetRateMatrixRequest) (*entity.GetRateMatrixResponse, error)
+	GetTimeSeries(ctx context.Context, req *entity.GetTimeSeriesRequest) (*entity.GetTimeSeriesResponse, error)
+	Compare(ctx context.Context, req *entity.ConvertCompareRequest) (*entity.ConvertCompareResponse, error)
 }
 
 // Compile time check that controller implements Controller interface
@@ -22,6 +39,8 @@ var _ Controller = (*controller)(nil)
 
 type controller struct {
 	config               internalconfig.Defaults
+	compare              internalconfig.CompareConfig
+	countries            []string // countries of the central banks the compare request is converted with
 	repositoryController repositorycontroller.Controller
 }
 
@@ -31,23 +50,41 @@ type Params struct {
 
 	Config               config.Provider
 	RepositoryController repositorycontroller.Controller
+	Registry             *gateway.Registry
 }
 
-// New is a constructor for the Controller interface
+// New is a constructor for the Controller interface. Default central bank must be registered,
+// default rate types must be supported.
 func New(p Params) (Controller, error) {
 	var d internalconfig.Defaults
 	err := p.Config.Get(defaults).Populate(&d)
 	if err != nil {
 		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
 	}
+	if _, ok := p.Registry.Gateway(d.DefaultCB); d.DefaultCB != "" && !ok {
+		return nil, fmt.Errorf("default central bank %s is not supported", d.DefaultCB)
+	}
+	for country, rateType := range d.RateTypes {
+		if err := mapper.CheckRateType(rateType); err != nil {
+			return nil, fmt.Errorf("bad default rate type of %s: %s", country, err)
+		}
+	}
+	var cc internalconfig.CompareConfig
+	err = p.Config.Get(compare).Populate(&cc)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
 	return &controller{
 		config:               d,
+		compare:              cc,
+		countries:            p.Registry.Countries(),
 		repositoryController: p.RepositoryController,
 	}, nil
 }
 
 // Convert loads the conversion rate of provided currencies and amount for the country central bank specified.
-// If no country is specified it falls back to default one set in the config
+// If no country is specified it falls back to default one set in the config, the same applies to the rate type
+// which falls back to the default rate type of the country if set.
 func (c *controller) Convert(
 	ctx context.Context,
 	req *entity.ConvertCurrencyRequest,
@@ -56,6 +93,9 @@ func (c *controller) Convert(
 	if err != nil {
 		return nil, err
 	}
+	if r.RateType == "" {
+		r.RateType = c.config.RateTypes[r.Country]
+	}
 	got, err := c.repositoryController.GetExchangeRate(ctx, r)
 	if err != nil {
 		return nil, err
@@ -66,3 +106,172 @@ func (c *controller) Convert(
 	}
 	return res, nil
 }
+
+// ConvertBatch converts every item of the batch the same way Convert does. Rates of every central bank are loaded
+// once per batch, so the items converted by the same central bank share the same rates.
+// Invalid or failed items are reported in their results and don't fail the batch.
+func (c *controller) ConvertBatch(
+	ctx context.Context,
+	req *entity.ConvertBatchRequest,
+) (*entity.ConvertBatchResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil ConvertBatchRequest")
+	}
+	results := make([]entity.ConvertBatchResult, len(req.Items))
+	reqs := make([]*entity.GetExchangeRateRequest, 0, len(req.Items))
+	indexes := make([]int, 0, len(req.Items))
+	for i := range req.Items {
+		item := &req.Items[i]
+		err := mapper.CheckConvertCurrencyRequest(item)
+		if err != nil {
+			results[i] = mapper.ConvertBatchError(i, err)
+			continue
+		}
+		r, err := mapper.ConvertCurrencyRequestToGetExchangeRateRequest(item, c.config.DefaultCB)
+		if err != nil {
+			results[i] = mapper.ConvertBatchError(i, err)
+			continue
+		}
+		reqs = append(reqs, r)
+		indexes = append(indexes, i)
+	}
+	c.applyDefaultRateType(reqs)
+	if len(reqs) > 0 {
+		got := c.repositoryController.GetExchangeRateBatch(ctx, reqs)
+		if len(got) != len(reqs) {
+			return nil, fmt.Errorf("%d results returned for %d items", len(got), len(reqs))
+		}
+		for j, i := range indexes {
+			results[i] = mapper.GetExchangeRateResultToConvertBatchResult(i, got[j])
+		}
+	}
+	return &entity.ConvertBatchResponse{
+		Results: results,
+	}, nil
+}
+
+// ConvertMulti converts the amount of the source currency to every target currency. All the target currencies
+// are converted with the same snapshot of the central bank rates, so the amounts share the same effective date.
+// Country and rate type fall back to the defaults the same way as in Convert.
+func (c *controller) ConvertMulti(
+	ctx context.Context,
+	req *entity.ConvertMultiRequest,
+) (*entity.ConvertMultiResponse, error) {
+	reqs, err := mapper.ConvertMultiRequestToGetExchangeRateRequests(req, c.config.DefaultCB)
+	if err != nil {
+		return nil, err
+	}
+	c.applyDefaultRateType(reqs)
+	got := c.repositoryController.GetExchangeRateBatch(ctx, reqs)
+	if len(got) != len(reqs) {
+		return nil, fmt.Errorf("%d results returned for %d target currencies", len(got), len(reqs))
+	}
+	return mapper.GetExchangeRateResultsToConvertMultiResponse(got)
+}
+
+// GetRateMatrix calculates the cross rates of every pair of the requested currencies with the same snapshot
+// of the central bank rates, so all the cross rates share the same effective date.
+// Country and rate type fall back to the defaults the same way as in Convert.
+func (c *controller) GetRateMatrix(
+	ctx context.Context,
+	req *entity.GetRateMatrixRequest,
+) (*entity.GetRateMatrixResponse, error) {
+	reqs, err := mapper.GetRateMatrixRequestToGetExchangeRateRequests(req, c.config.DefaultCB)
+	if err != nil {
+		return nil, err
+	}
+	c.applyDefaultRateType(reqs)
+	got := c.repositoryController.GetExchangeRateBatch(ctx, reqs)
+	return mapper.GetExchangeRateResultsToGetRateMatrixResponse(req.Currencies, got)
+}
+
+// applyDefaultRateType sets the default rate type of the country to the requests with no rate type
+func (c *controller) applyDefaultRateType(reqs []*entity.GetExchangeRateRequest) {
+	for _, r := range reqs {
+		if r.RateType == "" {
+			r.RateType = c.config.RateTypes[r.Country]
+		}
+	}
+}
+
+// Compare converts the amount with the rates of every registered central bank concurrently, the rate type falls
+// back to the default rate type of every country. Every central bank is given its timeout, the banks that fail
+// to convert the pair within the timeout are listed as unavailable instead of failing the request.
+func (c *controller) Compare(
+	ctx context.Context,
+	req *entity.ConvertCompareRequest,
+) (*entity.ConvertCompareResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil ConvertCompareRequest")
+	}
+	results := make([]entity.GetExchangeRateResult, len(c.countries))
+	var wg sync.WaitGroup
+	for i, country := range c.countries {
+		wg.Add(1)
+		go func(i int, country string) {
+			defer wg.Done()
+			results[i] = c.convertWithTimeout(ctx, req, country)
+		}(i, country)
+	}
+	wg.Wait()
+	return mapper.GetExchangeRateResultsToConvertCompareResponse(c.countries, results)
+}
+
+// convertWithTimeout converts the compare request with the rates of the central bank of the country.
+// The result is awaited no longer than the timeout of the country even if the repository ignores the context.
+func (c *controller) convertWithTimeout(
+	ctx context.Context,
+	req *entity.ConvertCompareRequest,
+	country string,
+) entity.GetExchangeRateResult {
+	r, err := mapper.ConvertCompareRequestToGetExchangeRateRequest(req, country)
+	if err != nil {
+		return entity.GetExchangeRateResult{Err: err}
+	}
+	c.applyDefaultRateType([]*entity.GetExchangeRateRequest{r})
+	ctx, cancel := context.WithTimeout(ctx, c.compareTimeout(country))
+	defer cancel()
+	done := make(chan entity.GetExchangeRateResult, 1)
+	go func() {
+		resp, err := c.repositoryController.GetExchangeRate(ctx, r)
+		done <- entity.GetExchangeRateResult{Response: resp, Err: err}
+	}()
+	select {
+	case res := <-done:
+		return res
+	case <-ctx.Done():
+		return entity.GetExchangeRateResult{Err: fmt.Errorf("CB %s: %s", country, ctx.Err())}
+	}
+}
+
+// compareTimeout returns the time the central bank of the country is given to convert the pair
+func (c *controller) compareTimeout(country string) time.Duration {
+	if timeout, ok := c.compare.Timeouts[country]; ok && timeout > 0 {
+		return timeout
+	}
+	if c.compare.Timeout > 0 {
+		return c.compare.Timeout
+	}
+	return defaultCompareTimeout
+}
+
+// GetTimeSeries loads the rates of provided currency pair for every business day of the requested range
+// for the country central bank specified. If no country is specified it falls back to default one set in the config
+func (c *controller) GetTimeSeries(
+	ctx context.Context,
+	req *entity.GetTimeSeriesRequest,
+) (*entity.GetTimeSeriesResponse, error) {
+	r, err := mapper.GetTimeSeriesRequestToGetCBRatesTimeSeriesRequest(req, c.config.DefaultCB)
+	if err != nil {
+		return nil, err
+	}
+	got, err := c.repositoryController.GetCBRatesTimeSeries(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	res, err := mapper.GetCBRatesTimeSeriesResponseToGetTimeSeriesResponse(got, req, r.Country)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
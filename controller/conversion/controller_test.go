@@ -4,31 +4,82 @@ import (
 	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/config"
 	internalconfig "my_go/config"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/mapper"
 	controllermock "my_go/mocks/controller/cb_repository"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	"my_go/money"
 	"my_go/utils"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	src := config.Source(
-		strings.NewReader(`{}`),
-	)
-	providerGood, _ := config.NewYAML(src)
-	mock := controllermock.NewMockController(ctrl)
-	got, err := New(Params{
-		Config:               providerGood,
-		RepositoryController: mock,
+	registry, _ := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{Bank: gateway.Bank{Country: "russia"}, Gateway: russiagatewaymock.NewMockGateway(ctrl)},
+		},
 	})
-	assert.NoError(t, err)
-	assert.NotNil(t, got)
+	tests := []struct {
+		name      string
+		yaml      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			yaml:      `{"defaults":{"default_cb":"russia"}}`,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, no default",
+			yaml:      `{}`,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "default central bank not registered",
+			yaml:      `{"defaults":{"default_cb":"atlantis"}}`,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Happy path, default rate type",
+			yaml:      `{"defaults":{"default_cb":"russia","rate_types":{"thailand":"buying_transfer"}}}`,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, compare timeouts",
+			yaml:      `{"defaults":{"default_cb":"russia"},"compare":{"timeout":"5s","timeouts":{"russia":"10s"}}}`,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "unsupported default rate type",
+			yaml:      `{"defaults":{"default_cb":"russia","rate_types":{"thailand":"bid"}}}`,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := config.NewYAML(config.Source(strings.NewReader(tt.yaml)))
+			got, err := New(Params{
+				Config:               provider,
+				RepositoryController: controllermock.NewMockController(ctrl),
+				Registry:             registry,
+			})
+			tt.assertion(t, err)
+			if err != nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.NotNil(t, got)
+		})
+	}
 }
 
 func Test_controller_Convert(t *testing.T) {
@@ -39,13 +90,13 @@ func Test_controller_Convert(t *testing.T) {
 		Country:        utils.ToPointer("thailand"),
 		SourceCurrency: "JPY",
 		TargetCurrency: "USD",
-		Amount:         12,
+		Amount:         utils.ToPointer(decimal.RequireFromString("12")),
 	}
 	requestWithoutCountry := &entity.ConvertCurrencyRequest{
 		Country:        nil,
 		SourceCurrency: "JPY",
 		TargetCurrency: "USD",
-		Amount:         12,
+		Amount:         utils.ToPointer(decimal.RequireFromString("12")),
 	}
 
 	type fields struct {
@@ -63,6 +114,7 @@ func Test_controller_Convert(t *testing.T) {
 		fields                   fields
 		args                     args
 		mockRepositoryController *mockRepositoryController
+		wantRateType             string
 		want                     *entity.ConvertCurrencyResponse
 		assertion                assert.ErrorAssertionFunc
 	}{
@@ -79,16 +131,17 @@ func Test_controller_Convert(t *testing.T) {
 			mockRepositoryController: &mockRepositoryController{
 				res: &entity.GetExchangeRateResponse{
 					Rate: entity.Rate{
-						Nominal:          12,
+						Nominal:          1,
 						BaseCurrency:     "JPY",
 						TargetCurrency:   "USD",
-						RateTargetToBase: 1.2345,
+						RateTargetToBase: decimal.RequireFromString("0.0074"),
 					},
+					Amount: decimal.RequireFromString("0.09"),
 				},
 				err: nil,
 			},
 			want: &entity.ConvertCurrencyResponse{
-				Amount: 1.2345,
+				Amount: decimal.RequireFromString("0.09"),
 			},
 			assertion: assert.NoError,
 		},
@@ -105,16 +158,69 @@ func Test_controller_Convert(t *testing.T) {
 			mockRepositoryController: &mockRepositoryController{
 				res: &entity.GetExchangeRateResponse{
 					Rate: entity.Rate{
-						Nominal:          12,
+						Nominal:          1,
 						BaseCurrency:     "JPY",
 						TargetCurrency:   "USD",
-						RateTargetToBase: 1.2345,
+						RateTargetToBase: decimal.RequireFromString("0.0074"),
 					},
+					Amount: decimal.RequireFromString("0.09"),
 				},
 				err: nil,
 			},
 			want: &entity.ConvertCurrencyResponse{
-				Amount: 1.2345,
+				Amount: decimal.RequireFromString("0.09"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, default rate type of the country",
+			fields: fields{
+				config: internalconfig.Defaults{
+					DefaultCB: "russia",
+					RateTypes: map[string]string{"thailand": "buying_transfer"},
+				},
+			},
+			args: args{
+				req: requestWithCountry,
+			},
+			mockRepositoryController: &mockRepositoryController{
+				res: &entity.GetExchangeRateResponse{
+					Amount: decimal.RequireFromString("0.09"),
+				},
+				err: nil,
+			},
+			wantRateType: "buying_transfer",
+			want: &entity.ConvertCurrencyResponse{
+				Amount: decimal.RequireFromString("0.09"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, rate type provided overrides the default",
+			fields: fields{
+				config: internalconfig.Defaults{
+					DefaultCB: "russia",
+					RateTypes: map[string]string{"thailand": "buying_transfer"},
+				},
+			},
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					Country:        utils.ToPointer("thailand"),
+					SourceCurrency: "JPY",
+					TargetCurrency: "USD",
+					Amount:         utils.ToPointer(decimal.RequireFromString("12")),
+					RateType:       "sell",
+				},
+			},
+			mockRepositoryController: &mockRepositoryController{
+				res: &entity.GetExchangeRateResponse{
+					Amount: decimal.RequireFromString("0.09"),
+				},
+				err: nil,
+			},
+			wantRateType: "sell",
+			want: &entity.ConvertCurrencyResponse{
+				Amount: decimal.RequireFromString("0.09"),
 			},
 			assertion: assert.NoError,
 		},
@@ -168,6 +274,7 @@ func Test_controller_Convert(t *testing.T) {
 			if req, err := mapper.ConvertCurrencyRequestToGetExchangeRateRequest(
 				tt.args.req, tt.fields.config.DefaultCB,
 			); err == nil {
+				req.RateType = tt.wantRateType
 				mockRepositoryCtrl.
 					EXPECT().
 					GetExchangeRate(ctx, req).
@@ -183,3 +290,507 @@ func Test_controller_Convert(t *testing.T) {
 		})
 	}
 }
+
+func Test_controller_ConvertBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cfg := internalconfig.Defaults{
+		DefaultCB: "russia",
+		RateTypes: map[string]string{"thailand": "mid"},
+	}
+	req := &entity.ConvertBatchRequest{
+		Items: []entity.ConvertCurrencyRequest{
+			{SourceCurrency: "USD", TargetCurrency: "RUB", Amount: utils.ToPointer(decimal.RequireFromString("10"))},
+			{SourceCurrency: "JPY", TargetCurrency: "RUB", Amount: utils.ToPointer(decimal.RequireFromString("1.5"))},
+			{Country: utils.ToPointer("thailand"), SourceCurrency: "USD", TargetCurrency: "THB", Amount: utils.ToPointer(decimal.RequireFromString("1"))},
+			{SourceCurrency: "ABC", TargetCurrency: "RUB", Amount: utils.ToPointer(decimal.RequireFromString("1"))},
+			{SourceCurrency: "USD", TargetCurrency: "RUB", Amount: utils.ToPointer(decimal.RequireFromString("1")), Date: utils.ToPointer("01.03.2023")},
+			{SourceCurrency: "USD", TargetCurrency: "RUB"},
+		},
+	}
+	wantReqs := []*entity.GetExchangeRateRequest{
+		{
+			Country:          "russia",
+			BaseCurrencyID:   "USD",
+			TargetCurrencyID: "RUB",
+			Amount:           decimal.RequireFromString("10"),
+			Places:           2,
+			Rounding:         money.HalfEven,
+		},
+		{
+			Country:          "thailand",
+			BaseCurrencyID:   "USD",
+			TargetCurrencyID: "THB",
+			Amount:           decimal.RequireFromString("1"),
+			Places:           2,
+			Rounding:         money.HalfEven,
+			RateType:         "mid",
+		},
+		{
+			Country:          "russia",
+			BaseCurrencyID:   "ABC",
+			TargetCurrencyID: "RUB",
+			Amount:           decimal.RequireFromString("1"),
+			Places:           2,
+			Rounding:         money.HalfEven,
+		},
+	}
+	tests := []struct {
+		name      string
+		req       *entity.ConvertBatchRequest
+		mockRes   []entity.GetExchangeRateResult
+		want      *entity.ConvertBatchResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, invalid and failed items reported",
+			req:  req,
+			mockRes: []entity.GetExchangeRateResult{
+				{Response: &entity.GetExchangeRateResponse{Amount: decimal.RequireFromString("800")}},
+				{Response: &entity.GetExchangeRateResponse{Amount: decimal.RequireFromString("34.28"), Stale: true}},
+				{Err: errors.New("currency ABC not supported")},
+			},
+			want: &entity.ConvertBatchResponse{
+				Results: []entity.ConvertBatchResult{
+					{Index: 0, Amount: utils.ToPointer(decimal.RequireFromString("800"))},
+					{Index: 1, Error: "amount 1.5 has more than 0 decimal places allowed for JPY"},
+					{Index: 2, Amount: utils.ToPointer(decimal.RequireFromString("34.28")), Stale: true},
+					{Index: 3, Error: "currency ABC not supported"},
+					{Index: 4, Error: "bad date 01.03.2023, expected format 2006-01-02"},
+					{Index: 5, Error: "amount is required"},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "results don't match the items",
+			req:       req,
+			mockRes:   []entity.GetExchangeRateResult{},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepositoryCtrl := controllermock.NewMockController(ctrl)
+			if tt.mockRes != nil {
+				mockRepositoryCtrl.EXPECT().GetExchangeRateBatch(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, reqs []*entity.GetExchangeRateRequest) []entity.GetExchangeRateResult {
+						assert.Equal(t, wantReqs, reqs)
+						return tt.mockRes
+					},
+				)
+			}
+			c := &controller{
+				config:               cfg,
+				repositoryController: mockRepositoryCtrl,
+			}
+			got, err := c.ConvertBatch(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_ConvertMulti(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cfg := internalconfig.Defaults{
+		DefaultCB: "thailand",
+		RateTypes: map[string]string{"thailand": "mid"},
+	}
+	req := &entity.ConvertMultiRequest{
+		SourceCurrency:   "USD",
+		TargetCurrencies: []string{"THB", "JPY"},
+		Amount:           utils.ToPointer(decimal.RequireFromString("100")),
+	}
+	wantReqs := []*entity.GetExchangeRateRequest{
+		{
+			Country:          "thailand",
+			BaseCurrencyID:   "USD",
+			TargetCurrencyID: "THB",
+			Amount:           decimal.RequireFromString("100"),
+			Places:           2,
+			Rounding:         money.HalfEven,
+			RateType:         "mid",
+		},
+		{
+			Country:          "thailand",
+			BaseCurrencyID:   "USD",
+			TargetCurrencyID: "JPY",
+			Amount:           decimal.RequireFromString("100"),
+			Places:           0,
+			Rounding:         money.HalfEven,
+			RateType:         "mid",
+		},
+	}
+	result := func(target string, rate string, amount string) entity.GetExchangeRateResult {
+		return entity.GetExchangeRateResult{
+			Response: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "USD",
+					TargetCurrency:   target,
+					RateTargetToBase: decimal.RequireFromString(rate),
+				},
+				Amount:        decimal.RequireFromString(amount),
+				EffectiveDate: "2023-04-19",
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		req       *entity.ConvertMultiRequest
+		mockRes   []entity.GetExchangeRateResult
+		want      *entity.ConvertMultiResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "Happy path, default country and rate type",
+			req:     req,
+			mockRes: []entity.GetExchangeRateResult{result("THB", "34.2824", "3428.24"), result("JPY", "134.4261", "13443")},
+			want: &entity.ConvertMultiResponse{
+				Date: "2023-04-19",
+				Amounts: map[string]decimal.Decimal{
+					"THB": decimal.RequireFromString("3428.24"),
+					"JPY": decimal.RequireFromString("13443"),
+				},
+				Rates: map[string]decimal.Decimal{
+					"THB": decimal.RequireFromString("34.2824"),
+					"JPY": decimal.RequireFromString("134.4261"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "target currency fails",
+			req:       req,
+			mockRes:   []entity.GetExchangeRateResult{result("THB", "34.2824", "3428.24"), {Err: errors.New("not supported")}},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "results don't match the target currencies",
+			req:       req,
+			mockRes:   []entity.GetExchangeRateResult{},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepositoryCtrl := controllermock.NewMockController(ctrl)
+			if tt.mockRes != nil {
+				mockRepositoryCtrl.EXPECT().GetExchangeRateBatch(ctx, wantReqs).Return(tt.mockRes)
+			}
+			c := &controller{
+				config:               cfg,
+				repositoryController: mockRepositoryCtrl,
+			}
+			got, err := c.ConvertMulti(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_GetRateMatrix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cfg := internalconfig.Defaults{
+		DefaultCB: "russia",
+	}
+	result := func(base string, target string, rate string) entity.GetExchangeRateResult {
+		return entity.GetExchangeRateResult{
+			Response: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     base,
+					TargetCurrency:   target,
+					RateTargetToBase: decimal.RequireFromString(rate),
+				},
+				Amount:        decimal.RequireFromString(rate),
+				EffectiveDate: "2023-04-19",
+				Stale:         true,
+			},
+		}
+	}
+	pair := func(base string, target string) *entity.GetExchangeRateRequest {
+		return &entity.GetExchangeRateRequest{
+			Country:          "russia",
+			BaseCurrencyID:   base,
+			TargetCurrencyID: target,
+			Amount:           decimal.NewFromInt(1),
+			Places:           money.RatePrecision,
+			Rounding:         money.HalfEven,
+		}
+	}
+	tests := []struct {
+		name      string
+		req       *entity.GetRateMatrixRequest
+		mockRes   []entity.GetExchangeRateResult
+		want      *entity.GetRateMatrixResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			req:  &entity.GetRateMatrixRequest{Currencies: []string{"USD", "EUR"}},
+			mockRes: []entity.GetExchangeRateResult{
+				result("USD", "USD", "1"),
+				result("USD", "EUR", "0.9126"),
+				result("EUR", "USD", "1.0958"),
+				result("EUR", "EUR", "1"),
+			},
+			want: &entity.GetRateMatrixResponse{
+				Date:       "2023-04-19",
+				Currencies: []string{"USD", "EUR"},
+				Rates: map[string]map[string]decimal.Decimal{
+					"USD": {"USD": decimal.RequireFromString("1"), "EUR": decimal.RequireFromString("0.9126")},
+					"EUR": {"USD": decimal.RequireFromString("1.0958"), "EUR": decimal.RequireFromString("1")},
+				},
+				Stale: true,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepositoryCtrl := controllermock.NewMockController(ctrl)
+			if tt.mockRes != nil {
+				mockRepositoryCtrl.EXPECT().GetExchangeRateBatch(ctx, []*entity.GetExchangeRateRequest{
+					pair("USD", "USD"), pair("USD", "EUR"), pair("EUR", "USD"), pair("EUR", "EUR"),
+				}).Return(tt.mockRes)
+			}
+			c := &controller{
+				config:               cfg,
+				repositoryController: mockRepositoryCtrl,
+			}
+			got, err := c.GetRateMatrix(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_GetTimeSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := &entity.GetTimeSeriesRequest{
+		Country:        utils.ToPointer("russia"),
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		StartDate:      "2023-04-18",
+		EndDate:        "2023-04-19",
+	}
+	timeSeries := &entity.GetCBRatesTimeSeriesResponse{
+		Rates: []entity.ExchangeRates{
+			{
+				Country:    "russia",
+				DateLoaded: "2023-04-18",
+				Rates: map[string]entity.Rate{
+					"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.6101")},
+					"EUR": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("89.5541")},
+				},
+			},
+		},
+	}
+
+	type mockRepositoryController struct {
+		res *entity.GetCBRatesTimeSeriesResponse
+		err error
+	}
+	tests := []struct {
+		name                     string
+		req                      *entity.GetTimeSeriesRequest
+		mockRepositoryController *mockRepositoryController
+		want                     *entity.GetTimeSeriesResponse
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			req:  request,
+			mockRepositoryController: &mockRepositoryController{
+				res: timeSeries,
+			},
+			want: &entity.GetTimeSeriesResponse{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Rates: []entity.TimeSeriesPoint{
+					{Date: "2023-04-18", Rate: decimal.RequireFromString("0.911294")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "controller fails",
+			req:  request,
+			mockRepositoryController: &mockRepositoryController{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "controller returns nil and no error",
+			req:  request,
+			mockRepositoryController: &mockRepositoryController{
+				res: nil,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepositoryCtrl := controllermock.NewMockController(ctrl)
+			if req, err := mapper.GetTimeSeriesRequestToGetCBRatesTimeSeriesRequest(tt.req, "russia"); err == nil {
+				mockRepositoryCtrl.
+					EXPECT().
+					GetCBRatesTimeSeries(ctx, req).
+					Return(tt.mockRepositoryController.res, tt.mockRepositoryController.err)
+			}
+			c := &controller{
+				config: internalconfig.Defaults{
+					DefaultCB: "russia",
+				},
+				repositoryController: mockRepositoryCtrl,
+			}
+			got, err := c.GetTimeSeries(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_controller_Compare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	req := &entity.ConvertCompareRequest{
+		SourceCurrency: "USD",
+		TargetCurrency: "EUR",
+		Amount:         utils.ToPointer(decimal.RequireFromString("100")),
+	}
+	getRequest := func(country string, rateType string) *entity.GetExchangeRateRequest {
+		return &entity.GetExchangeRateRequest{
+			Country:          country,
+			BaseCurrencyID:   "USD",
+			TargetCurrencyID: "EUR",
+			Amount:           decimal.RequireFromString("100"),
+			Places:           2,
+			Rounding:         money.HalfEven,
+			RateType:         rateType,
+		}
+	}
+	response := func(rate string, amount string) *entity.GetExchangeRateResponse {
+		return &entity.GetExchangeRateResponse{
+			Rate: entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     "USD",
+				TargetCurrency:   "EUR",
+				RateTargetToBase: decimal.RequireFromString(rate),
+			},
+			Amount:        decimal.RequireFromString(amount),
+			EffectiveDate: "2023-04-19",
+		}
+	}
+	unblock := make(chan struct{})
+	defer close(unblock)
+	mockRepositoryController := controllermock.NewMockController(ctrl)
+	mockRepositoryController.EXPECT().
+		GetExchangeRate(gomock.Any(), getRequest("russia", "")).
+		Return(response("0.9126", "91.26"), nil)
+	mockRepositoryController.EXPECT().
+		GetExchangeRate(gomock.Any(), getRequest("thailand", "mid")).
+		Return(response("0.9131", "91.31"), nil)
+	mockRepositoryController.EXPECT().
+		GetExchangeRate(gomock.Any(), getRequest("eurozone", "")).
+		DoAndReturn(func(ctx context.Context, r *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error) {
+			<-unblock // the context is ignored, so the timeout of the bank is enforced by the controller
+			return nil, errors.New("timeout")
+		})
+	mockRepositoryController.EXPECT().
+		GetExchangeRate(gomock.Any(), getRequest("canada", "")).
+		Return(nil, errors.New("currency EUR not supported"))
+	c := &controller{
+		config: internalconfig.Defaults{
+			DefaultCB: "russia",
+			RateTypes: map[string]string{"thailand": "mid"},
+		},
+		compare: internalconfig.CompareConfig{
+			Timeout:  time.Minute,
+			Timeouts: map[string]time.Duration{"eurozone": 10 * time.Millisecond},
+		},
+		countries:            []string{"russia", "thailand", "eurozone", "canada"},
+		repositoryController: mockRepositoryController,
+	}
+
+	got, err := c.Compare(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.ConvertCompareResponse{
+		Results: []entity.ConvertCompareResult{
+			{
+				Country: "russia",
+				Date:    "2023-04-19",
+				Amount:  decimal.RequireFromString("91.26"),
+				Rate:    decimal.RequireFromString("0.9126"),
+			},
+			{
+				Country: "thailand",
+				Date:    "2023-04-19",
+				Amount:  decimal.RequireFromString("91.31"),
+				Rate:    decimal.RequireFromString("0.9131"),
+			},
+		},
+		Spread: &entity.ConversionSpread{
+			Min:    decimal.RequireFromString("91.26"),
+			Max:    decimal.RequireFromString("91.31"),
+			Median: decimal.RequireFromString("91.285"),
+		},
+		UnavailableBanks: []string{"eurozone", "canada"},
+	}, got)
+
+	got, err = c.Compare(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func Test_controller_compareTimeout(t *testing.T) {
+	c := &controller{
+		compare: internalconfig.CompareConfig{
+			Timeout:  3 * time.Second,
+			Timeouts: map[string]time.Duration{"canada": 10 * time.Second},
+		},
+	}
+	assert.Equal(t, 10*time.Second, c.compareTimeout("canada"))
+	assert.Equal(t, 3*time.Second, c.compareTimeout("russia"))
+	assert.Equal(t, defaultCompareTimeout, (&controller{}).compareTimeout("russia"))
+}
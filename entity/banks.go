@@ -0,0 +1,19 @@
+package entity
+
+// ListBanksResponse contains all the central banks supported by the service ordered by country
+type ListBanksResponse struct {
+	Banks []Bank `json:"banks"`
+}
+
+// Bank describes the supported central bank.
+// Country is the key the bank is requested by, rates of the bank are set against BaseCurrency in TimeZone.
+// Historical and TimeSeries show whether the rates for a past date and for a date range are supported.
+type Bank struct {
+	Country      string `json:"country"`
+	Name         string `json:"name,omitempty"`
+	BaseCurrency string `json:"base_currency,omitempty"`
+	TimeZone     string `json:"time_zone,omitempty"`
+	Website      string `json:"website,omitempty"`
+	Historical   bool   `json:"historical"`
+	TimeSeries   bool   `json:"time_series"`
+}
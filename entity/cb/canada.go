@@ -0,0 +1,21 @@
+package cb
+
+import "encoding/json"
+
+// CanadaCBData represents the response of Bank of Canada Valet API for the group of series
+// (observations/group/FX_RATES_DAILY/json). Every currency is a separate series (e.g. FXUSDCAD),
+// every observation contains the date under "d" key and the values of the series published for that date.
+type CanadaCBData struct {
+	SeriesDetail map[string]CanadaCBSeriesDetail `json:"seriesDetail"`
+	Observations []map[string]json.RawMessage    `json:"observations"`
+}
+
+type CanadaCBSeriesDetail struct {
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// CanadaCBValue is the value of the series for the date, CAD for 1 unit of the currency
+type CanadaCBValue struct {
+	V string `json:"v"`
+}
@@ -0,0 +1,20 @@
+package cb
+
+// CzechCBData represents the daily text feed of Czech National Bank (denni_kurz.txt).
+// The first line contains the date and the number of the fixing (e.g. "19.04.2023 #76"),
+// the second one is the header of the pipe-separated table of the rates.
+type CzechCBData struct {
+	Date   string
+	Number string
+	Rates  []CzechCBRate
+}
+
+// CzechCBRate is a single line of the table: Rate CZK for Amount units of Code currency.
+// Rate uses decimal comma.
+type CzechCBRate struct {
+	Country  string
+	Currency string
+	Amount   int
+	Code     string
+	Rate     string
+}
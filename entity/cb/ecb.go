@@ -0,0 +1,21 @@
+package cb
+
+import "encoding/xml"
+
+// ECBData represents the eurofxref feed of European Central Bank (gesmes:Envelope).
+// Every day of the feed is a Cube with time attribute containing a Cube per currency.
+type ECBData struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Days    []ECBDay `xml:"Cube>Cube"`
+}
+
+type ECBDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ECBRate `xml:"Cube"`
+}
+
+// ECBRate is the amount of Currency for 1 EUR
+type ECBRate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
@@ -0,0 +1,36 @@
+package cb
+
+import "encoding/xml"
+
+// HungaryCBNamespace is the namespace of the web service of Magyar Nemzeti Bank (arfolyamok.asmx)
+const HungaryCBNamespace = "http://www.mnb.hu/webservices/"
+
+// HungaryCBGetCurrentExchangeRatesRequest is the request of GetCurrentExchangeRates SOAP operation
+type HungaryCBGetCurrentExchangeRatesRequest struct {
+	XMLName xml.Name `xml:"http://www.mnb.hu/webservices/ GetCurrentExchangeRates"`
+}
+
+// HungaryCBGetCurrentExchangeRatesResponse is the response of GetCurrentExchangeRates SOAP operation.
+// Result is the escaped xml document with the rates (MNBCurrentExchangeRates).
+type HungaryCBGetCurrentExchangeRatesResponse struct {
+	XMLName xml.Name `xml:"http://www.mnb.hu/webservices/ GetCurrentExchangeRatesResponse"`
+	Result  string   `xml:"http://www.mnb.hu/webservices/ GetCurrentExchangeRatesResult"`
+}
+
+// HungaryCBData represents MNBCurrentExchangeRates document, the rates of the day are set for the Day date.
+type HungaryCBData struct {
+	XMLName xml.Name     `xml:"MNBCurrentExchangeRates"`
+	Day     HungaryCBDay `xml:"Day"`
+}
+
+type HungaryCBDay struct {
+	Date  string          `xml:"date,attr"`
+	Rates []HungaryCBRate `xml:"Rate"`
+}
+
+// HungaryCBRate is the rate HUF for Unit units of Currency, Value uses decimal comma
+type HungaryCBRate struct {
+	Unit     int    `xml:"unit,attr"`
+	Currency string `xml:"curr,attr"`
+	Value    string `xml:",chardata"`
+}
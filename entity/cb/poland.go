@@ -0,0 +1,20 @@
+package cb
+
+import "encoding/json"
+
+// PolandCBTable is a single table of the JSON tables API of National Bank of Poland
+// (/api/exchangerates/tables/A). The API responds with the array of tables, No is the table
+// number (e.g. "075/A/NBP/2023") and EffectiveDate is the date the table is in force (format 2006-01-02).
+type PolandCBTable struct {
+	Table         string         `json:"table"`
+	No            string         `json:"no"`
+	EffectiveDate string         `json:"effectiveDate"`
+	Rates         []PolandCBRate `json:"rates"`
+}
+
+// PolandCBRate is a single mid rate of table A: Mid PLN for 1 unit of Code currency
+type PolandCBRate struct {
+	Currency string      `json:"currency"`
+	Code     string      `json:"code"`
+	Mid      json.Number `json:"mid"`
+}
@@ -4,11 +4,59 @@ import "encoding/xml"
 
 type RussiaCBRData struct {
 	XMLName xml.Name       `xml:"ValCurs"`
+	Date    string         `xml:"Date,attr"`
 	Rates   []RussiaCBRate `xml:"Valute"`
 }
 
 type RussiaCBRate struct {
+	ID         string `xml:"ID,attr"`
 	CurrencyID string `xml:"CharCode"`
 	Nominal    int    `xml:"Nominal"`
 	Value      string `xml:"Value"`
 }
+
+// RussiaCBRDynamicData represents the response of Central Bank of Russia dynamic API
+// that contains the rates of a single currency for a date range.
+type RussiaCBRDynamicData struct {
+	XMLName xml.Name              `xml:"ValCurs"`
+	ID      string                `xml:"ID,attr"`
+	Records []RussiaCBDynamicRate `xml:"Record"`
+}
+
+type RussiaCBDynamicRate struct {
+	Date    string `xml:"Date,attr"`
+	Nominal int    `xml:"Nominal"`
+	Value   string `xml:"Value"`
+}
+
+// RussiaCBRMetalsData represents the response of Central Bank of Russia precious metals API
+// that contains the prices of the metals for a date range.
+type RussiaCBRMetalsData struct {
+	XMLName xml.Name              `xml:"Metall"`
+	Records []RussiaCBMetalRecord `xml:"Record"`
+}
+
+// RussiaCBMetalRecord is the price of a gram of the metal identified by Code for a single date
+type RussiaCBMetalRecord struct {
+	Date string `xml:"Date,attr"`
+	Code string `xml:"Code,attr"`
+	Buy  string `xml:"Buy"`
+	Sell string `xml:"Sell"`
+}
+
+// RussiaCBRCurrenciesData represents the response of Central Bank of Russia currencies reference (XML_valFull.asp)
+type RussiaCBRCurrenciesData struct {
+	XMLName    xml.Name                 `xml:"Valuta"`
+	Currencies []RussiaCBCurrencyRecord `xml:"Item"`
+}
+
+// RussiaCBCurrencyRecord is the reference data of a single currency, ISO codes are empty
+// for the currencies that are no longer in use.
+type RussiaCBCurrencyRecord struct {
+	ID       string `xml:"ID,attr"`
+	Name     string `xml:"Name"`
+	EngName  string `xml:"EngName"`
+	Nominal  int    `xml:"Nominal"`
+	NumCode  string `xml:"ISO_Num_Code"`
+	CharCode string `xml:"ISO_Char_Code"`
+}
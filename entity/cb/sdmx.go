@@ -0,0 +1,57 @@
+package cb
+
+import "encoding/json"
+
+// SDMXJSONData represents SDMX-JSON data message. Version 1.0 wraps the data sets and the structure
+// into the data object, older publications have them at the top level.
+type SDMXJSONData struct {
+	Data      *SDMXJSONData     `json:"data,omitempty"`
+	DataSets  []SDMXJSONDataSet `json:"dataSets"`
+	Structure SDMXJSONStructure `json:"structure"`
+}
+
+// SDMXJSONDataSet contains the series by the key made of the indexes of the series dimension values
+// joined by colon (e.g. "0:2:0:0")
+type SDMXJSONDataSet struct {
+	Series map[string]SDMXJSONSeries `json:"series"`
+}
+
+// SDMXJSONSeries contains the observations by the index of the observation dimension value,
+// the first element of the observation is the value, either string or number.
+type SDMXJSONSeries struct {
+	Observations map[string][]json.RawMessage `json:"observations"`
+}
+
+type SDMXJSONStructure struct {
+	Dimensions SDMXJSONDimensions `json:"dimensions"`
+}
+
+// SDMXJSONDimensions lists the dimensions in the order the series and observation keys are built of
+type SDMXJSONDimensions struct {
+	Series      []SDMXJSONDimension `json:"series"`
+	Observation []SDMXJSONDimension `json:"observation"`
+}
+
+type SDMXJSONDimension struct {
+	ID     string                   `json:"id"`
+	Values []SDMXJSONDimensionValue `json:"values"`
+}
+
+type SDMXJSONDimensionValue struct {
+	ID string `json:"id"`
+}
+
+// SDMXObservation is a single observation of SDMX data message regardless of the format.
+// SeriesKey is the values of the series dimensions joined by dot (e.g. "B.USD.NOK.SP").
+type SDMXObservation struct {
+	SeriesKey  string
+	TimePeriod string
+	Value      string
+}
+
+// SDMXSeries describes the currency the series is quoted for, Nominal is the amount of the currency
+// the observation value is set for.
+type SDMXSeries struct {
+	Currency string
+	Nominal  int
+}
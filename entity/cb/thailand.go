@@ -4,12 +4,35 @@ import "encoding/xml"
 
 type ThailandCBRData struct {
 	XMLName xml.Name         `xml:"RDF"`
+	Date    string           `xml:"channel>date"`
 	Rates   []ThailandCBRate `xml:"item"`
 }
 
 type ThailandCBRate struct {
-	Title          string  `xml:"title"`
-	Description    string  `xml:"description"`
-	TargetCurrency string  `xml:"targetCurrency"`
-	Value          float64 `xml:"value"`
+	Date           string `xml:"date"`
+	Title          string `xml:"title"`
+	Description    string `xml:"description"`
+	TargetCurrency string `xml:"targetCurrency"`
+	Value          string `xml:"value"`
+}
+
+// ThailandCBRHistoricalData represents the response of Bank of Thailand
+// Daily Weighted-average Interbank Exchange Rate API that serves rates for an arbitrary period.
+type ThailandCBRHistoricalData struct {
+	Result struct {
+		Data struct {
+			DataDetail []ThailandCBHistoricalRate `json:"data_detail"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// ThailandCBHistoricalRate is a single currency rate for a single period (date) of the historical API.
+type ThailandCBHistoricalRate struct {
+	Period          string `json:"period"`
+	CurrencyID      string `json:"currency_id"`
+	CurrencyNameEng string `json:"currency_name_eng"`
+	BuyingSight     string `json:"buying_sight"`
+	BuyingTransfer  string `json:"buying_transfer"`
+	Selling         string `json:"selling"`
+	MidRate         string `json:"mid_rate"`
 }
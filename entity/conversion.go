@@ -1,16 +1,153 @@
 package entity
 
+import "github.com/shopspring/decimal"
+
 // ConvertCurrencyRequest is a container to store the currency conversion request
 // Country represents the central bank that is expected as source of exchange rates (default will be used if omitted)
 // The request represents the following question: How much in TargetCurrency will be the Amount of SourceCurrency
+// Date is optional and allows to convert with the rates set by central bank for a past date (format 2006-01-02)
+// Amount is required and accepted both as decimal string and json number with arbitrary precision, it can't be
+// negative or have more decimal places than ISO 4217 minor units of SourceCurrency.
+// RoundingMode is optional and defines how the result is rounded to the minor units of TargetCurrency:
+// half_even (default), half_up or down.
+// RateType is optional and selects the quote of the central bank rates used for conversion: buy, sell, mid
+// or any rate type of the quotes catalogue (e.g. buying_transfer). If omitted the default rate type of the country
+// is used if configured, otherwise the reference rate of the central bank.
+// Triangulate is optional, if set the pair missing from the rates of the central bank is converted through
+// USD or EUR with the rates of other central banks.
 type ConvertCurrencyRequest struct {
-	Country        *string `json:"country,omitempty"`
-	SourceCurrency string  `json:"source_currency,omitempty"`
-	TargetCurrency string  `json:"target_currency,omitempty"`
-	Amount         int     `json:"amount,omitempty"`
+	Country        *string          `json:"country,omitempty"`
+	Date           *string          `json:"date,omitempty"`
+	SourceCurrency string           `json:"source_currency,omitempty"`
+	TargetCurrency string           `json:"target_currency,omitempty"`
+	Amount         *decimal.Decimal `json:"amount"`
+	RoundingMode   string           `json:"rounding_mode,omitempty"`
+	RateType       string           `json:"rate_type,omitempty"`
+	Triangulate    bool             `json:"triangulate,omitempty"`
 }
 
 // ConvertCurrencyResponse represents the resulted Amount of SourceCurrency
+// Stale is set when the conversion is made with the last known rates as central bank is unreachable
+// Path is returned for triangulation requests and contains every conversion the Amount is calculated with.
 type ConvertCurrencyResponse struct {
-	Amount float64 `json:"amount"`
+	Amount decimal.Decimal `json:"amount"`
+	Stale  bool            `json:"stale,omitempty"`
+	Path   []ConversionLeg `json:"path,omitempty"`
+}
+
+// ConversionLeg is a single conversion of the path made with the rates of the central bank of Country
+// set for Date. Rate is the amount of TargetCurrency for 1 unit of SourceCurrency.
+type ConversionLeg struct {
+	Country        string          `json:"country"`
+	Date           string          `json:"date,omitempty"`
+	SourceCurrency string          `json:"source_currency"`
+	TargetCurrency string          `json:"target_currency"`
+	Rate           decimal.Decimal `json:"rate"`
+	Stale          bool            `json:"stale,omitempty"`
+}
+
+// ConvertCompareRequest is a container to store the request to convert the Amount of SourceCurrency
+// to TargetCurrency with the rates of every supported central bank.
+// Date, RoundingMode and RateType are the same as in ConvertCurrencyRequest, the default rate type
+// is applied by the country of every central bank.
+type ConvertCompareRequest struct {
+	Date           *string          `json:"date,omitempty"`
+	SourceCurrency string           `json:"source_currency,omitempty"`
+	TargetCurrency string           `json:"target_currency,omitempty"`
+	Amount         *decimal.Decimal `json:"amount"`
+	RoundingMode   string           `json:"rounding_mode,omitempty"`
+	RateType       string           `json:"rate_type,omitempty"`
+}
+
+// ConvertCompareResponse contains the results of the central banks that converted the pair in the order
+// the banks are listed by /banks, the Spread of the converted amounts and the banks that couldn't convert the pair.
+type ConvertCompareResponse struct {
+	Results          []ConvertCompareResult `json:"results"`
+	Spread           *ConversionSpread      `json:"spread,omitempty"`
+	UnavailableBanks []string               `json:"unavailable_banks,omitempty"`
+}
+
+// ConvertCompareResult is the conversion made with the rates of the central bank of Country set for Date.
+// Rate is the amount of target currency for 1 unit of source currency.
+// Stale is set when the conversion is made with the last known rates as central bank is unreachable
+type ConvertCompareResult struct {
+	Country string          `json:"country"`
+	Date    string          `json:"date,omitempty"`
+	Amount  decimal.Decimal `json:"amount"`
+	Rate    decimal.Decimal `json:"rate"`
+	Stale   bool            `json:"stale,omitempty"`
+}
+
+// ConversionSpread contains the minimum, the maximum and the median of the amounts converted by several
+// central banks. Median of even number of amounts is the exact average of the middle two.
+type ConversionSpread struct {
+	Min    decimal.Decimal `json:"min"`
+	Max    decimal.Decimal `json:"max"`
+	Median decimal.Decimal `json:"median"`
+}
+
+// ConvertBatchRequest is a container to store a batch of currency conversion requests.
+// Items are converted independently and may use different central banks, a failure of an item
+// doesn't fail the batch.
+type ConvertBatchRequest struct {
+	Items []ConvertCurrencyRequest `json:"items"`
+}
+
+// ConvertBatchResponse contains the result of every item of the batch in the order of the request
+type ConvertBatchResponse struct {
+	Results []ConvertBatchResult `json:"results"`
+}
+
+// ConvertBatchResult is the result of a single item of the batch, Index is the position of the item in the request.
+// Either Amount or Error is set.
+type ConvertBatchResult struct {
+	Index  int              `json:"index"`
+	Amount *decimal.Decimal `json:"amount,omitempty"`
+	Stale  bool             `json:"stale,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// ConvertMultiRequest is a container to store the request to convert the Amount of SourceCurrency
+// to every currency of TargetCurrencies with the same rates of the central bank.
+// Country, Date, RoundingMode and RateType are the same as in ConvertCurrencyRequest.
+type ConvertMultiRequest struct {
+	Country          *string          `json:"country,omitempty"`
+	Date             *string          `json:"date,omitempty"`
+	SourceCurrency   string           `json:"source_currency,omitempty"`
+	TargetCurrencies []string         `json:"target_currencies,omitempty"`
+	Amount           *decimal.Decimal `json:"amount"`
+	RoundingMode     string           `json:"rounding_mode,omitempty"`
+	RateType         string           `json:"rate_type,omitempty"`
+}
+
+// ConvertMultiResponse contains the Amount of SourceCurrency converted to every target currency and the rates,
+// amounts of target currency for 1 unit of source currency, the conversion is made with.
+// Date is the official date of the rates, all the amounts are converted with the rates of that date.
+// Stale is set when the conversion is made with the last known rates as central bank is unreachable
+type ConvertMultiResponse struct {
+	Date    string                     `json:"date,omitempty"`
+	Amounts map[string]decimal.Decimal `json:"amounts"`
+	Rates   map[string]decimal.Decimal `json:"rates"`
+	Stale   bool                       `json:"stale,omitempty"`
+}
+
+// GetRateMatrixRequest is a container to store the request of cross rates of every pair of Currencies
+// calculated with the same rates of the central bank. Country, Date and RateType are the same as
+// in ConvertCurrencyRequest.
+type GetRateMatrixRequest struct {
+	Country    *string  `json:"country,omitempty"`
+	Date       *string  `json:"date,omitempty"`
+	Currencies []string `json:"currencies,omitempty"`
+	RateType   string   `json:"rate_type,omitempty"`
+}
+
+// GetRateMatrixResponse contains the cross rates of the requested currencies, Rates[base][target] is the amount
+// of target currency for 1 unit of base currency. Currencies are in the order of the request.
+// Date is the official date of the rates, all the cross rates are calculated with the rates of that date.
+// Stale is set when the cross rates are calculated with the last known rates as central bank is unreachable
+type GetRateMatrixResponse struct {
+	Date       string                                `json:"date,omitempty"`
+	Currencies []string                              `json:"currencies"`
+	Rates      map[string]map[string]decimal.Decimal `json:"rates"`
+	Stale      bool                                  `json:"stale,omitempty"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+// Currency is the reference data of a currency.
+// Code and NumericCode are ISO 4217 alphabetic and numeric (3 digits) codes, Name is the English name
+// and NameRu is the Russian name of the currency. Nominal is the amount of the currency the bank of Russia
+// sets the rate for, MinorUnits is the number of decimal places the amounts of the currency are accounted to.
+// Banks are the countries of the configured central banks quoting the currency.
+type Currency struct {
+	Code        string   `json:"code"`
+	NumericCode string   `json:"numeric_code,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	NameRu      string   `json:"name_ru,omitempty"`
+	Nominal     int      `json:"nominal,omitempty"`
+	MinorUnits  int32    `json:"minor_units"`
+	Banks       []string `json:"banks,omitempty"`
+}
+
+// ListCurrenciesResponse contains the reference data of all the known currencies ordered by code.
+// UnavailableBanks are the countries of the central banks which rates couldn't be loaded, so the currencies
+// quoted by them might miss the bank in Banks.
+type ListCurrenciesResponse struct {
+	Currencies       []Currency `json:"currencies"`
+	UnavailableBanks []string   `json:"unavailable_banks,omitempty"`
+}
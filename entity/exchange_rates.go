@@ -1,25 +1,55 @@
 package entity
 
 import (
+	"github.com/shopspring/decimal"
 	"time"
 )
 
 // GetExchangeRatesRequest is a request to get exchange rates from a central bank of provided country
+// Date is optional, if provided the rates set by central bank for that date are returned (format 2006-01-02)
+// IncludeNames is optional, if set the reference data of the currencies of the rates is returned as well.
+// Base and Currencies are optional, if any of them is provided the rates of the Currencies (all the currencies
+// quoted by the central bank if empty) are normalized to 1 unit of Base (the currency the central bank sets
+// the rates against if empty).
 type GetExchangeRatesRequest struct {
-	Country string `json:"country,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	Date         *string  `json:"date,omitempty"`
+	IncludeNames bool     `json:"include_names,omitempty"`
+	Base         string   `json:"base,omitempty"`
+	Currencies   []string `json:"currencies,omitempty"`
 }
 
 // GetExchangeRatesResponse is a container with exchange rates for the external API request
+// Date is the official date the rates are set for by the central bank,
+// PublishedAt is the publication timestamp of the rates (RFC3339) if provided by the central bank.
+// Reference is the identifier of the publication the rates come from (e.g. NBP table number) if provided.
+// Stale is set when the last known rates are returned as central bank is unreachable
+// Currencies contains the reference data of the currencies of the rates if requested.
 type GetExchangeRatesResponse struct {
-	Rates map[string]Rate `json:"rates"`
+	Date        string              `json:"date,omitempty"`
+	PublishedAt string              `json:"published_at,omitempty"`
+	Reference   string              `json:"reference,omitempty"`
+	Stale       bool                `json:"stale,omitempty"`
+	Rates       map[string]Rate     `json:"rates"`
+	Currencies  map[string]Currency `json:"currencies,omitempty"`
 }
 
 // ExchangeRates is a container to store internal exchange rates data for a single central bank
+// DateLoaded is the date in the central bank timezone the rates were loaded for.
+// EffectiveDate is the official date the rates are set for as provided by the central bank, it might differ
+// from DateLoaded (e.g. on Sunday the rates set for Saturday are in force, in the afternoon bank of Russia
+// already serves the rates set for the next day). Empty if the central bank doesn't provide it.
+// PublishedAt is the publication timestamp provided by the central bank, zero if not provided.
+// Reference is the provenance identifier of the publication as provided by the central bank
+// (e.g. "075/A/NBP/2023" table number of National Bank of Poland), empty if not provided.
 type ExchangeRates struct {
-	Country    string
-	DateLoaded string
-	TimeZone   *time.Location
-	Rates      map[string]Rate
+	Country       string
+	DateLoaded    string
+	EffectiveDate string
+	PublishedAt   time.Time
+	Reference     string
+	TimeZone      *time.Location
+	Rates         map[string]Rate
 }
 
 // Rate is a container for a single exchange rate
@@ -27,10 +57,46 @@ type ExchangeRates struct {
 // Nominal = 100,
 // BaseCurrency = THB,
 // TargetCurrency = JPY,
-// RateTargetToBase = 25159600
+// RateTargetToBase = 25.1596
+// Inverse is set when the central bank quotes the rate in the opposite direction, i.e. RateTargetToBase
+// is the amount of target currency for Nominal units of base currency.
+// E.g. ECB 1 EUR = 1.0958 USD means
+// Nominal = 1,
+// BaseCurrency = EUR,
+// TargetCurrency = USD,
+// RateTargetToBase = 1.0958,
+// Inverse = true
+// Bid, Ask and Mid are optional quotes of the same direction and Nominal as RateTargetToBase published by
+// the central banks that set the buying (Bid) and selling (Ask) rates along with the middle one (Mid).
+// RateTargetToBase is the reference rate of the bank, for such banks it equals to Mid.
+// Quotes is the catalogue of all the rates of the same direction and Nominal the central bank publishes
+// for the currency, keys are rate types (e.g. RateTypeBuyingTransfer).
 type Rate struct {
-	Nominal          int     `json:"nominal,omitempty"` // amount of target currency to be used for ratio
-	BaseCurrency     string  `json:"base_currency,omitempty"`
-	TargetCurrency   string  `json:"target_currency,omitempty"`
-	RateTargetToBase float64 `json:"rate_target_to_base,omitempty"` // contains 6 digits decimal ratio in bigint.
+	Nominal          int                        `json:"nominal,omitempty"` // amount of target currency to be used for ratio
+	BaseCurrency     string                     `json:"base_currency,omitempty"`
+	TargetCurrency   string                     `json:"target_currency,omitempty"`
+	RateTargetToBase decimal.Decimal            `json:"rate_target_to_base"` // exact decimal, marshalled as decimal string
+	Inverse          bool                       `json:"inverse,omitempty"`
+	Bid              *decimal.Decimal           `json:"bid,omitempty"`
+	Ask              *decimal.Decimal           `json:"ask,omitempty"`
+	Mid              *decimal.Decimal           `json:"mid,omitempty"`
+	Quotes           map[string]decimal.Decimal `json:"quotes,omitempty"`
 }
+
+// Rate types select the quote of Rate used for the conversion
+const (
+	RateTypeBuy  = "buy"  // Bid, the rate the bank buys the currency at
+	RateTypeSell = "sell" // Ask, the rate the bank sells the currency at
+	RateTypeMid  = "mid"  // Mid if published, RateTargetToBase otherwise
+)
+
+// Rate types of the quotes catalogue, the quote of such type is taken from Rate.Quotes
+const (
+	RateTypeBuyingSight    = "buying_sight"    // the rate the bank buys sight bills at
+	RateTypeBuyingTransfer = "buying_transfer" // the rate the bank buys telegraphic transfers (TT) at
+	RateTypeSelling        = "selling"         // the rate the bank sells the currency at
+	RateTypeMidRate        = "mid_rate"        // the middle rate as published by the bank
+)
+
+// QuoteRateTypes is the catalogue of rate types that can be published in Rate.Quotes
+var QuoteRateTypes = []string{RateTypeBuyingSight, RateTypeBuyingTransfer, RateTypeSelling, RateTypeMidRate}
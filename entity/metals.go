@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// ISO 4217 codes of precious metals, the unit of such code is one troy ounce of the metal
+const (
+	Gold      = "XAU"
+	Silver    = "XAG"
+	Platinum  = "XPT"
+	Palladium = "XPD"
+)
+
+// Metals maps ISO 4217 codes of precious metals to their names
+var Metals = map[string]string{
+	Gold:      "gold",
+	Silver:    "silver",
+	Platinum:  "platinum",
+	Palladium: "palladium",
+}
+
+// GramsPerTroyOunce is the mass of one troy ounce in grams
+var GramsPerTroyOunce = decimal.RequireFromString("31.1034768")
+
+// GetMetalsRequest is a request to get precious metals prices from a central bank of provided country
+// Date is optional, if provided the prices set by central bank for that date are returned (format 2006-01-02)
+type GetMetalsRequest struct {
+	Country string  `json:"country,omitempty"`
+	Date    *string `json:"date,omitempty"`
+}
+
+// GetMetalsResponse is a container with precious metals prices for the external API request
+// Date is the official date the prices are set for by the central bank.
+type GetMetalsResponse struct {
+	Date   string                `json:"date,omitempty"`
+	Prices map[string]MetalPrice `json:"prices"`
+}
+
+// MetalPrices is a container to store internal precious metals prices of a single central bank
+// DateLoaded and EffectiveDate have the same meaning as for ExchangeRates.
+type MetalPrices struct {
+	Country       string
+	DateLoaded    string
+	EffectiveDate string
+	TimeZone      *time.Location
+	Prices        map[string]MetalPrice
+}
+
+// MetalPrice is the price of a single gram of precious metal in the currency of the central bank
+// E.g. gold 5050.63 RUB per gram means
+// Metal = XAU,
+// Name = gold,
+// Currency = RUB,
+// Buy = Sell = 5050.63
+type MetalPrice struct {
+	Metal    string          `json:"metal"`
+	Name     string          `json:"name,omitempty"`
+	Currency string          `json:"currency"`
+	Buy      decimal.Decimal `json:"buy"`
+	Sell     decimal.Decimal `json:"sell"`
+}
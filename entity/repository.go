@@ -1,20 +1,96 @@
 package entity
 
+import (
+	"github.com/shopspring/decimal"
+	"my_go/money"
+)
+
+// GetCBRatesRequest is a request to load central bank rates. Empty Date means the current rates.
 type GetCBRatesRequest struct {
 	Country string
+	Date    string
 }
 
+// GetCBRatesResponse contains central bank rates.
+// Stale is set when the actual rates couldn't be loaded and the last known rates are returned instead.
 type GetCBRatesResponse struct {
 	Rates *ExchangeRates
+	Stale bool
 }
 
+// GetExchangeRateRequest is a request to calculate exchange rate. Empty Date means the current rates.
+// Amount of base currency is converted to target currency and rounded to Places decimal places
+// with Rounding mode (empty means half to even).
+// RateType selects the quote of the rates used for the calculation (empty means the reference rate).
+// Triangulate allows to calculate the rate of the pair missing from the rates of the central bank
+// through one of TriangulationCurrencies with the rates of other central banks.
 type GetExchangeRateRequest struct {
 	Country          string
+	Date             string
 	BaseCurrencyID   string
 	TargetCurrencyID string
-	Amount           int
+	Amount           decimal.Decimal
+	Places           int32
+	Rounding         money.RoundingMode
+	RateType         string
+	Triangulate      bool
 }
 
+// GetExchangeRateResponse contains the calculated exchange rate for 1 unit of base currency
+// and the requested Amount converted to target currency.
+// Stale is set when the rate is calculated from the last known rates of central bank.
+// EffectiveDate is the official date of the rates the calculation is made with, empty if the legs
+// of the path are calculated with the rates of different dates.
+// Path is set for triangulation requests and contains every conversion the rate is calculated with.
 type GetExchangeRateResponse struct {
-	Rate Rate
+	Rate          Rate
+	Amount        decimal.Decimal
+	Stale         bool
+	EffectiveDate string
+	Path          []ExchangeRateLeg
+}
+
+// TriangulationCurrencies are the common currencies the pair missing from the rates of the central bank
+// is converted through, in the order of preference
+var TriangulationCurrencies = []string{"USD", "EUR"}
+
+// ExchangeRateLeg is a single conversion of the path, Rate is calculated for 1 unit of base currency
+// with the rates of the central bank of Country set for EffectiveDate.
+type ExchangeRateLeg struct {
+	Country       string
+	EffectiveDate string
+	Stale         bool
+	Rate          Rate
+}
+
+// GetExchangeRateResult is the result of a single GetExchangeRateRequest of the batch,
+// either Response or Err is set.
+type GetExchangeRateResult struct {
+	Response *GetExchangeRateResponse
+	Err      error
+}
+
+// GetCBRatesTimeSeriesRequest is a request to load central bank rates for every business day of the range.
+// Currencies limits the set of currencies that are required in the response.
+type GetCBRatesTimeSeriesRequest struct {
+	Country    string
+	Currencies []string
+	StartDate  string
+	EndDate    string
+}
+
+// GetCBRatesTimeSeriesResponse contains central bank rates ordered by date
+type GetCBRatesTimeSeriesResponse struct {
+	Rates []ExchangeRates
+}
+
+// GetCBMetalsRequest is a request to load precious metals prices of central bank. Empty Date means the current prices.
+type GetCBMetalsRequest struct {
+	Country string
+	Date    string
+}
+
+// GetCBMetalsResponse contains precious metals prices of central bank
+type GetCBMetalsResponse struct {
+	Prices *MetalPrices
 }
@@ -0,0 +1,28 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// GetTimeSeriesRequest is a request to get the exchange rate of a currency pair over a date range
+// Country represents the central bank that is expected as source of exchange rates (default will be used if omitted)
+// StartDate and EndDate are inclusive and expected in format 2006-01-02
+type GetTimeSeriesRequest struct {
+	Country        *string `json:"country,omitempty"`
+	BaseCurrency   string  `json:"base_currency,omitempty"`
+	TargetCurrency string  `json:"target_currency,omitempty"`
+	StartDate      string  `json:"start_date,omitempty"`
+	EndDate        string  `json:"end_date,omitempty"`
+}
+
+// GetTimeSeriesResponse contains the rate of the currency pair for every business day of the requested range
+type GetTimeSeriesResponse struct {
+	BaseCurrency   string            `json:"base_currency"`
+	TargetCurrency string            `json:"target_currency"`
+	Rates          []TimeSeriesPoint `json:"rates"`
+}
+
+// TimeSeriesPoint is a rate of the currency pair for a single date.
+// Rate represents the amount of target currency for 1 unit of base currency.
+type TimeSeriesPoint struct {
+	Date string          `json:"date"`
+	Rate decimal.Decimal `json:"rate"`
+}
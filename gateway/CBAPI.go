@@ -8,3 +8,34 @@ import (
 type CBGateway interface {
 	GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error)
 }
+
+// HistoricalCBGateway is implemented by gateways that are able to load the rates
+// published by the central bank for a past date. Date is expected in entity.DateLayout format.
+type HistoricalCBGateway interface {
+	GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error)
+}
+
+// TimeSeriesCBGateway is implemented by gateways that are able to load the rates published by the
+// central bank for a date range at once. Only the provided currencies are required to be present in the result.
+// Dates are expected in entity.DateLayout format, the range is inclusive.
+type TimeSeriesCBGateway interface {
+	GetCBRRatesTimeSeries(
+		ctx context.Context,
+		currencies []string,
+		startDate string,
+		endDate string,
+	) ([]entity.ExchangeRates, error)
+}
+
+// CurrenciesCBGateway is implemented by gateways of central banks that publish the reference data of currencies.
+// Keys of the result are ISO 4217 alphabetic codes.
+type CurrenciesCBGateway interface {
+	GetCBRCurrencies(ctx context.Context) (map[string]entity.Currency, error)
+}
+
+// MetalsCBGateway is implemented by gateways of central banks that set the prices of precious metals.
+// Date is expected in entity.DateLayout format.
+type MetalsCBGateway interface {
+	GetCBRMetals(ctx context.Context) (*entity.MetalPrices, error)
+	GetCBRMetalsForDate(ctx context.Context, date string) (*entity.MetalPrices, error)
+}
@@ -0,0 +1,224 @@
+package canada
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/gateway"
+	mapper "my_go/mapper/cb"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const (
+	configKey = "canada_cb_config"
+	// historicalLookbackDays is the period before the requested date the latest published rates are looked for
+	// as Valet has no observations for weekends and holidays
+	historicalLookbackDays = 7
+)
+
+// Gateway is an interface that implements shared interface of CBGateway for Bank of Canada
+type Gateway interface {
+	gateway.CBGateway
+	gateway.HistoricalCBGateway
+	gateway.TimeSeriesCBGateway
+}
+
+// Compile time check that canadaCBGateway implements Gateway interface
+var _ Gateway = (*canadaCBGateway)(nil)
+
+type canadaCBGateway struct {
+	TimeNow func() time.Time
+	Config  internalconfig.CanadaCBConfig
+}
+
+// New is a constructor for Gateway interface
+func New(c config.Provider) (Gateway, error) {
+	var cfg internalconfig.CanadaCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	return &canadaCBGateway{
+		TimeNow: time.Now,
+		Config:  cfg,
+	}, nil
+}
+
+// Country is the key the rates of Bank of Canada are requested by
+const Country = "canada"
+
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.CanadaCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      Country,
+				Name:         "Bank of Canada",
+				BaseCurrency: "CAD",
+				TimeZone:     tz,
+				Website:      "https://www.bankofcanada.ca",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
+// GetCBRRates returns the latest exchange rates of Bank of Canada
+func (g *canadaCBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	body, err := g.get(ctx, map[string]string{"recent": "1"})
+	if err != nil {
+		return nil, err
+	}
+	return g.toExchangeRates(body, "")
+}
+
+// GetCBRRatesForDate returns exchange rates of Bank of Canada in force for the provided date.
+// Valet has no observations for weekends and holidays, so the latest rates published within
+// the week before the date are returned with their date as the effective date.
+func (g *canadaCBGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	d, err := time.Parse(entity.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %s provided, err %s", date, err)
+	}
+	body, err := g.get(ctx, map[string]string{
+		"start_date": d.AddDate(0, 0, -historicalLookbackDays).Format(entity.DateLayout),
+		"end_date":   date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g.toExchangeRates(body, date)
+}
+
+// GetCBRRatesTimeSeries returns exchange rates of Bank of Canada for every date of the range the rates
+// are published for. All currencies of the group are returned in a single response, so currencies are not
+// used for filtering. Long range is loaded in chunks of gateway.MaxRangeDays, so every response stays
+// within gateway.MaxResponseSize.
+func (g *canadaCBGateway) GetCBRRatesTimeSeries(
+	ctx context.Context,
+	currencies []string,
+	startDate string,
+	endDate string,
+) ([]entity.ExchangeRates, error) {
+	ranges, err := gateway.SplitDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	byDate := map[string]map[string]entity.Rate{}
+	for _, r := range ranges {
+		body, err := g.get(ctx, map[string]string{
+			"start_date": r.StartDate,
+			"end_date":   r.EndDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := mapper.CanadaCBResponseToRatesByDate(body)
+		if err != nil {
+			return nil, err
+		}
+		for date, m := range chunk {
+			byDate[date] = m
+		}
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	res := make([]entity.ExchangeRates, 0, len(dates))
+	for _, date := range dates {
+		res = append(res, entity.ExchangeRates{
+			Country:       Country,
+			TimeZone:      tz,
+			DateLoaded:    date,
+			EffectiveDate: date,
+			Rates:         byDate[date],
+		})
+	}
+	return res, nil
+}
+
+// toExchangeRates maps the rates of the latest date in the response. If date is provided the rates are
+// considered to be loaded for that date, otherwise for the current date in the bank timezone.
+func (g *canadaCBGateway) toExchangeRates(body []byte, date string) (*entity.ExchangeRates, error) {
+	m, err := mapper.CanadaCBResponseToRates(body)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := mapper.CanadaCBResponseToEffectiveDate(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	if date == "" {
+		date = g.TimeNow().In(tz).Format(entity.DateLayout)
+	}
+	return &entity.ExchangeRates{
+		Country:       Country,
+		TimeZone:      tz,
+		DateLoaded:    date,
+		EffectiveDate: effectiveDate,
+		Rates:         m,
+	}, nil
+}
+
+// get loads the observations of the group with the provided query params
+func (g *canadaCBGateway) get(ctx context.Context, params map[string]string) ([]byte, error) {
+	u, err := url.Parse(g.Config.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.APIURL, err)
+	}
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, g.Config.APIURL)
+	}
+	return body, nil
+}
@@ -0,0 +1,362 @@
+package canada
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var correctJSON = []byte(`{"seriesDetail":{"FXUSDCAD":{"label":"USD/CAD"},"FXEURCAD":{"label":"EUR/CAD"}},` +
+	`"observations":[` +
+	`{"d":"2023-04-14","FXUSDCAD":{"v":"1.3332"},"FXEURCAD":{"v":"1.4716"}},` +
+	`{"d":"2023-04-17","FXUSDCAD":{"v":"1.3383"},"FXEURCAD":{"v":"1.4681"}}]}`)
+
+var cad = entity.Rate{
+	Nominal:          1,
+	BaseCurrency:     "CAD",
+	TargetCurrency:   "CAD",
+	RateTargetToBase: decimal.RequireFromString("1"),
+}
+
+func rate(currency string, value string) entity.Rate {
+	return entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "CAD",
+		TargetCurrency:   currency,
+		RateTargetToBase: decimal.RequireFromString(value),
+	}
+}
+
+func TestNew(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	got, err := New(provider)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"canada_cb_config":{"timezone":"America/Toronto"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"canada_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, Country, got.Registration.Country)
+	assert.Equal(t, "CAD", got.Registration.BaseCurrency)
+	assert.Equal(t, "America/Toronto", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
+func Test_canadaCBGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 17, 22, 0, 0, 0, time.UTC)
+	}
+	caTZ, _ := time.LoadLocation("America/Toronto")
+	tests := []struct {
+		name                     string
+		apiURL                   string
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path, latest observation used",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			timeZone:           "America/Toronto",
+			want: &entity.ExchangeRates{
+				Country:       "canada",
+				DateLoaded:    "2023-04-17",
+				EffectiveDate: "2023-04-17",
+				TimeZone:      caTZ,
+				Rates: map[string]entity.Rate{
+					"CAD": cad,
+					"USD": rate("USD", "1.3383"),
+					"EUR": rate("EUR", "1.4681"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "network timeout",
+			isTimedOut: true,
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 404,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected json arrived from server",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`{"message":"Series not found"}`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:      "bad api url in gateway config",
+			apiURL:    "http://[::1]:namedport",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "1", r.URL.Query().Get("recent"))
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			apiURL := testServer.URL
+			if tt.isTimedOut {
+				apiURL = timedOutMock.URL
+			}
+			if tt.apiURL != "" {
+				apiURL = tt.apiURL
+			}
+			g := &canadaCBGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.CanadaCBConfig{
+					APIURL:   apiURL,
+					Timezone: tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_canadaCBGateway_GetCBRRatesForDate(t *testing.T) {
+	caTZ, _ := time.LoadLocation("America/Toronto")
+	var gotQuery url.Values
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Write(correctJSON)
+	}))
+	defer testServer.Close()
+	tests := []struct {
+		name          string
+		date          string
+		wantStartDate string
+		want          *entity.ExchangeRates
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Happy path, latest rates within the week before the date",
+			date:          "2023-04-18",
+			wantStartDate: "2023-04-11",
+			want: &entity.ExchangeRates{
+				Country:       "canada",
+				DateLoaded:    "2023-04-18",
+				EffectiveDate: "2023-04-17",
+				TimeZone:      caTZ,
+				Rates: map[string]entity.Rate{
+					"CAD": cad,
+					"USD": rate("USD", "1.3383"),
+					"EUR": rate("EUR", "1.4681"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			date:      "18.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = nil
+			g := &canadaCBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.CanadaCBConfig{
+					APIURL:   testServer.URL,
+					Timezone: "America/Toronto",
+				},
+			}
+			got, err := g.GetCBRRatesForDate(context.Background(), tt.date)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.wantStartDate != "" {
+				assert.Equal(t, tt.wantStartDate, gotQuery.Get("start_date"))
+				assert.Equal(t, tt.date, gotQuery.Get("end_date"))
+			}
+		})
+	}
+}
+
+func Test_canadaCBGateway_GetCBRRatesTimeSeries(t *testing.T) {
+	caTZ, _ := time.LoadLocation("America/Toronto")
+	tests := []struct {
+		name               string
+		startDate          string
+		endDate            string
+		timezone           string
+		httpRespStatusCode int
+		httpRespBody       []byte
+		want               []entity.ExchangeRates
+		assertion          assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			startDate:          "2023-04-14",
+			endDate:            "2023-04-17",
+			timezone:           "America/Toronto",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want: []entity.ExchangeRates{
+				{
+					Country:       "canada",
+					DateLoaded:    "2023-04-14",
+					EffectiveDate: "2023-04-14",
+					TimeZone:      caTZ,
+					Rates: map[string]entity.Rate{
+						"CAD": cad,
+						"USD": rate("USD", "1.3332"),
+						"EUR": rate("EUR", "1.4716"),
+					},
+				},
+				{
+					Country:       "canada",
+					DateLoaded:    "2023-04-17",
+					EffectiveDate: "2023-04-17",
+					TimeZone:      caTZ,
+					Rates: map[string]entity.Rate{
+						"CAD": cad,
+						"USD": rate("USD", "1.3383"),
+						"EUR": rate("EUR", "1.4681"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad start date",
+			startDate: "14.04.2023",
+			endDate:   "2023-04-17",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad end date",
+			startDate: "2023-04-14",
+			endDate:   "17.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			startDate:          "2023-04-14",
+			endDate:            "2023-04-17",
+			httpRespStatusCode: 500,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad json from server",
+			startDate:          "2023-04-14",
+			endDate:            "2023-04-17",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`{"observations"`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			startDate:          "2023-04-14",
+			endDate:            "2023-04-17",
+			timezone:           "1234",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.startDate, r.URL.Query().Get("start_date"))
+				assert.Equal(t, tt.endDate, r.URL.Query().Get("end_date"))
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			g := &canadaCBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.CanadaCBConfig{
+					APIURL:   testServer.URL,
+					Timezone: tt.timezone,
+				},
+			}
+			got, err := g.GetCBRRatesTimeSeries(context.Background(), []string{"USD"}, tt.startDate, tt.endDate)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package canada
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(New),
+	fx.Provide(Register),
+)
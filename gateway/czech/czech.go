@@ -0,0 +1,147 @@
+package czech
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/gateway"
+	mapper "my_go/mapper/cb"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	configKey      = "czech_cb_config"
+	dateReqLayout  = "02.01.2006"
+	dateQueryParam = "date"
+)
+
+// Gateway is an interface that implements shared interface of CBGateway for Czech National Bank
+type Gateway interface {
+	gateway.CBGateway
+	gateway.HistoricalCBGateway
+}
+
+// Compile time check that czechCBGateway implements Gateway interface
+var _ Gateway = (*czechCBGateway)(nil)
+
+type czechCBGateway struct {
+	TimeNow func() time.Time
+	Config  internalconfig.CzechCBConfig
+}
+
+// New is a constructor for Gateway interface
+func New(c config.Provider) (Gateway, error) {
+	var cfg internalconfig.CzechCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	return &czechCBGateway{
+		TimeNow: time.Now,
+		Config:  cfg,
+	}, nil
+}
+
+// Country is the key the rates of Czech National Bank are requested by
+const Country = "czech"
+
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.CzechCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      Country,
+				Name:         "Czech National Bank",
+				BaseCurrency: "CZK",
+				TimeZone:     tz,
+				Website:      "https://www.cnb.cz",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
+// GetCBRRates returns exchange rates of Czech National Bank
+func (g *czechCBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	return g.getRates(ctx, g.Config.APIURL, "")
+}
+
+// GetCBRRatesForDate returns exchange rates set by Czech National Bank for the provided date.
+// The daily feed accepts the date in the date query parameter.
+func (g *czechCBGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	d, err := time.Parse(entity.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %s provided, err %s", date, err)
+	}
+	u, err := url.Parse(g.Config.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.APIURL, err)
+	}
+	q := u.Query()
+	q.Set(dateQueryParam, d.Format(dateReqLayout))
+	u.RawQuery = q.Encode()
+	return g.getRates(ctx, u.String(), date)
+}
+
+// getRates loads the text feed from the provided url. If date is provided the rates are considered
+// to be loaded for that date, otherwise for the current date in the bank timezone.
+func (g *czechCBGateway) getRates(ctx context.Context, apiURL string, date string) (*entity.ExchangeRates, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, apiURL)
+	}
+
+	m, err := mapper.CzechCBResponseToRates(body)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := mapper.CzechCBResponseToEffectiveDate(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	if date == "" {
+		date = g.TimeNow().In(tz).Format(entity.DateLayout)
+	}
+	return &entity.ExchangeRates{
+		Country:       Country,
+		TimeZone:      tz,
+		DateLoaded:    date,
+		EffectiveDate: effectiveDate,
+		Rates:         m,
+	}, nil
+}
@@ -0,0 +1,261 @@
+package czech
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var correctText = []byte("19.04.2023 #76\n" +
+	"země|měna|množství|kód|kurz\n" +
+	"EMU|euro|1|EUR|23,425\n" +
+	"Japonsko|jen|100|JPY|15,930\n")
+
+func TestNew(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	got, err := New(provider)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"czech_cb_config":{"timezone":"Europe/Prague"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"czech_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, Country, got.Registration.Country)
+	assert.Equal(t, "CZK", got.Registration.BaseCurrency)
+	assert.Equal(t, "Europe/Prague", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
+func Test_czechCBGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 13, 0, 0, 0, time.UTC)
+	}
+	czTZ, _ := time.LoadLocation("Europe/Prague")
+	tests := []struct {
+		name                     string
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctText,
+			timeZone:           "Europe/Prague",
+			want: &entity.ExchangeRates{
+				Country:       "czech",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      czTZ,
+				Rates: map[string]entity.Rate{
+					"CZK": {
+						Nominal:          1,
+						BaseCurrency:     "CZK",
+						TargetCurrency:   "CZK",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "CZK",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("23.425"),
+					},
+					"JPY": {
+						Nominal:          100,
+						BaseCurrency:     "CZK",
+						TargetCurrency:   "JPY",
+						RateTargetToBase: decimal.RequireFromString("15.93"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "network timeout",
+			isTimedOut: true,
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 503,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected text arrived from server",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<html>maintenance</html>`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad date in the header",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte("19/04/2023 #76\nEMU|euro|1|EUR|23,425\n"),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctText,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctText,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			url := testServer.URL
+			if tt.isTimedOut {
+				url = timedOutMock.URL
+			}
+			g := &czechCBGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.CzechCBConfig{
+					APIURL:   url,
+					Timezone: tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_czechCBGateway_GetCBRRatesForDate(t *testing.T) {
+	czTZ, _ := time.LoadLocation("Europe/Prague")
+	var gotQuery string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte("17.04.2023 #74\nzemě|měna|množství|kód|kurz\nEMU|euro|1|EUR|23,355\n"))
+	}))
+	defer testServer.Close()
+	tests := []struct {
+		name          string
+		date          string
+		apiURL        string
+		expectedQuery string
+		want          *entity.ExchangeRates
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Happy path",
+			date:          "2023-04-17",
+			apiURL:        testServer.URL,
+			expectedQuery: "date=17.04.2023",
+			want: &entity.ExchangeRates{
+				Country:       "czech",
+				DateLoaded:    "2023-04-17",
+				EffectiveDate: "2023-04-17",
+				TimeZone:      czTZ,
+				Rates: map[string]entity.Rate{
+					"CZK": {
+						Nominal:          1,
+						BaseCurrency:     "CZK",
+						TargetCurrency:   "CZK",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "CZK",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("23.355"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			date:      "17.04.2023",
+			apiURL:    testServer.URL,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad api url",
+			date:      "2023-04-17",
+			apiURL:    "http://[::1",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = ""
+			g := &czechCBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.CzechCBConfig{
+					APIURL:   tt.apiURL,
+					Timezone: "Europe/Prague",
+				},
+			}
+			got, err := g.GetCBRRatesForDate(context.Background(), tt.date)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.expectedQuery, gotQuery)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package ecb
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/gateway"
+	mapper "my_go/mapper/cb"
+	"net/http"
+	"time"
+)
+
+const configKey = "ecb_cb_config"
+
+// Gateway is an interface that implements shared interface of CBGateway for European Central Bank
+type Gateway interface {
+	gateway.CBGateway
+}
+
+// Compile time check that ecbGateway implements Gateway interface
+var _ Gateway = (*ecbGateway)(nil)
+
+type ecbGateway struct {
+	TimeNow func() time.Time
+	Config  internalconfig.ECBConfig
+}
+
+// New is a constructor for Gateway interface
+func New(c config.Provider) (Gateway, error) {
+	var cfg internalconfig.ECBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	return &ecbGateway{
+		TimeNow: time.Now,
+		Config:  cfg,
+	}, nil
+}
+
+// Country is the key the rates of European Central Bank are requested by
+const Country = "eurozone"
+
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.ECBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      Country,
+				Name:         "European Central Bank",
+				BaseCurrency: "EUR",
+				TimeZone:     tz,
+				Website:      "https://www.ecb.europa.eu",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
+// GetCBRRates returns euro foreign exchange reference rates of European Central Bank
+func (g *ecbGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", g.Config.APIURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, g.Config.APIURL)
+	}
+
+	m, err := mapper.ECBResponseToRates(body)
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := mapper.ECBResponseToEffectiveDate(body)
+	if err != nil {
+		return nil, err // unreachable in tests, cause the rates are parsed from the same body
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	return &entity.ExchangeRates{
+		Country:       Country,
+		TimeZone:      tz,
+		DateLoaded:    g.TimeNow().In(tz).Format(entity.DateLayout),
+		EffectiveDate: effectiveDate,
+		Rates:         m,
+	}, nil
+}
@@ -0,0 +1,188 @@
+package ecb
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	got, err := New(provider)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"ecb_cb_config":{"timezone":"Europe/Berlin"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"ecb_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, Country, got.Registration.Country)
+	assert.Equal(t, "EUR", got.Registration.BaseCurrency)
+	assert.Equal(t, "Europe/Berlin", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
+func Test_ecbGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 17, 0, 0, 0, time.UTC)
+	}
+	deTZ, _ := time.LoadLocation("Europe/Berlin")
+	correctXML := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2023-04-19'>
+			<Cube currency='USD' rate='1.0958'/>
+			<Cube currency='JPY' rate='147.45'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+	tests := []struct {
+		name                     string
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctXML,
+			timeZone:           "Europe/Berlin",
+			want: &entity.ExchangeRates{
+				Country:       "eurozone",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      deTZ,
+				Rates: map[string]entity.Rate{
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "EUR",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"USD": {
+						Nominal:          1,
+						BaseCurrency:     "EUR",
+						TargetCurrency:   "USD",
+						RateTargetToBase: decimal.RequireFromString("1.0958"),
+						Inverse:          true,
+					},
+					"JPY": {
+						Nominal:          1,
+						BaseCurrency:     "EUR",
+						TargetCurrency:   "JPY",
+						RateTargetToBase: decimal.RequireFromString("147.45"),
+						Inverse:          true,
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "network timeout",
+			isTimedOut: true,
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 503,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "incorrect xml arrived from server",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<this is for sure not xml`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctXML,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctXML,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			url := testServer.URL
+			if tt.isTimedOut {
+				url = timedOutMock.URL
+			}
+			g := &ecbGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.ECBConfig{
+					APIURL:   url,
+					Timezone: tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package hungary
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/gateway"
+	"my_go/gateway/soap"
+	mapper "my_go/mapper/cb"
+	"time"
+)
+
+const (
+	configKey                     = "hungary_cb_config"
+	getCurrentExchangeRatesAction = cb_entity.HungaryCBNamespace + "MNBArfolyamServiceSoap/GetCurrentExchangeRates"
+)
+
+// Gateway is an interface that implements shared interface of CBGateway for Magyar Nemzeti Bank
+type Gateway interface {
+	gateway.CBGateway
+}
+
+// Compile time check that hungaryCBGateway implements Gateway interface
+var _ Gateway = (*hungaryCBGateway)(nil)
+
+type hungaryCBGateway struct {
+	TimeNow func() time.Time
+	Config  internalconfig.HungaryCBConfig
+	Client  *soap.Client
+}
+
+// New is a constructor for Gateway interface
+func New(c config.Provider) (Gateway, error) {
+	var cfg internalconfig.HungaryCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	return &hungaryCBGateway{
+		TimeNow: time.Now,
+		Config:  cfg,
+		Client:  soap.NewClient(cfg.APIURL),
+	}, nil
+}
+
+// Country is the key the rates of Magyar Nemzeti Bank are requested by
+const Country = "hungary"
+
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.HungaryCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      Country,
+				Name:         "Magyar Nemzeti Bank",
+				BaseCurrency: "HUF",
+				TimeZone:     tz,
+				Website:      "https://www.mnb.hu",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
+// GetCBRRates returns exchange rates of Magyar Nemzeti Bank loaded with GetCurrentExchangeRates SOAP operation
+func (g *hungaryCBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	resp := cb_entity.HungaryCBGetCurrentExchangeRatesResponse{}
+	err := g.Client.Call(ctx, getCurrentExchangeRatesAction, cb_entity.HungaryCBGetCurrentExchangeRatesRequest{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := mapper.HungaryCBResponseToRates([]byte(resp.Result))
+	if err != nil {
+		return nil, err
+	}
+	effectiveDate, err := mapper.HungaryCBResponseToEffectiveDate([]byte(resp.Result))
+	if err != nil {
+		return nil, err
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	return &entity.ExchangeRates{
+		Country:       Country,
+		TimeZone:      tz,
+		DateLoaded:    g.TimeNow().In(tz).Format(entity.DateLayout),
+		EffectiveDate: effectiveDate,
+		Rates:         m,
+	}, nil
+}
@@ -0,0 +1,183 @@
+package hungary
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	"io"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/gateway/soap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// soapResponse wraps the escaped MNBCurrentExchangeRates document into GetCurrentExchangeRates response
+func soapResponse(result string) []byte {
+	return []byte(`<?xml version="1.0" encoding="utf-8"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+		`<GetCurrentExchangeRatesResponse xmlns="http://www.mnb.hu/webservices/" xmlns:i="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<GetCurrentExchangeRatesResult>` + result + `</GetCurrentExchangeRatesResult>` +
+		`</GetCurrentExchangeRatesResponse></s:Body></s:Envelope>`)
+}
+
+var correctResponse = soapResponse(`&lt;MNBCurrentExchangeRates&gt;&lt;Day date="2023-04-19"&gt;` +
+	`&lt;Rate unit="1" curr="EUR"&gt;375,53&lt;/Rate&gt;` +
+	`&lt;Rate unit="100" curr="JPY"&gt;254,12&lt;/Rate&gt;` +
+	`&lt;/Day&gt;&lt;/MNBCurrentExchangeRates&gt;`)
+
+func TestNew(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	got, err := New(provider)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"hungary_cb_config":{"timezone":"Europe/Budapest"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"hungary_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, Country, got.Registration.Country)
+	assert.Equal(t, "HUF", got.Registration.BaseCurrency)
+	assert.Equal(t, "Europe/Budapest", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
+func Test_hungaryCBGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 13, 0, 0, 0, time.UTC)
+	}
+	huTZ, _ := time.LoadLocation("Europe/Budapest")
+	tests := []struct {
+		name                     string
+		httpRequestCreationFails bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctResponse,
+			timeZone:           "Europe/Budapest",
+			want: &entity.ExchangeRates{
+				Country:       "hungary",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      huTZ,
+				Rates: map[string]entity.Rate{
+					"HUF": {
+						Nominal:          1,
+						BaseCurrency:     "HUF",
+						TargetCurrency:   "HUF",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "HUF",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("375.53"),
+					},
+					"JPY": {
+						Nominal:          100,
+						BaseCurrency:     "HUF",
+						TargetCurrency:   "JPY",
+						RateTargetToBase: decimal.RequireFromString("254.12"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "soap fault",
+			httpRespStatusCode: 500,
+			httpRespBody: []byte(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>` +
+				`<faultcode>s:Server</faultcode><faultstring>Service unavailable</faultstring></s:Fault></s:Body></s:Envelope>`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 503,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected result document",
+			httpRespStatusCode: 200,
+			httpRespBody:       soapResponse(`&lt;html&gt;maintenance&lt;/html&gt;`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad date of the day",
+			httpRespStatusCode: 200,
+			httpRespBody: soapResponse(`&lt;MNBCurrentExchangeRates&gt;&lt;Day date="19.04.2023"&gt;` +
+				`&lt;Rate unit="1" curr="EUR"&gt;375,53&lt;/Rate&gt;&lt;/Day&gt;&lt;/MNBCurrentExchangeRates&gt;`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctResponse,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAction string
+			var gotRequest []byte
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAction = r.Header.Get("SOAPAction")
+				gotRequest, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			g := &hungaryCBGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.HungaryCBConfig{
+					APIURL:   testServer.URL,
+					Timezone: tt.timeZone,
+				},
+				Client: soap.NewClient(testServer.URL),
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			if !tt.httpRequestCreationFails {
+				assert.Equal(t, `"http://www.mnb.hu/webservices/MNBArfolyamServiceSoap/GetCurrentExchangeRates"`, gotAction)
+				assert.Contains(t, string(gotRequest), `<GetCurrentExchangeRates xmlns="http://www.mnb.hu/webservices/"></GetCurrentExchangeRates>`)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package gateway
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(NewRegistry),
+)
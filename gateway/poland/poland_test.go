@@ -0,0 +1,185 @@
+package poland
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var correctJSON = []byte(`[{"table":"A","no":"075/A/NBP/2023","effectiveDate":"2023-04-19","rates":[` +
+	`{"currency":"euro","code":"EUR","mid":4.6059},` +
+	`{"currency":"jen (Japonia)","code":"JPY","mid":0.031288}]}]`)
+
+func TestNew(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	got, err := New(provider)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"poland_cb_config":{"timezone":"Europe/Warsaw"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"poland_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, Country, got.Registration.Country)
+	assert.Equal(t, "PLN", got.Registration.BaseCurrency)
+	assert.Equal(t, "Europe/Warsaw", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
+func Test_polandCBGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 13, 0, 0, 0, time.UTC)
+	}
+	plTZ, _ := time.LoadLocation("Europe/Warsaw")
+	tests := []struct {
+		name                     string
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			timeZone:           "Europe/Warsaw",
+			want: &entity.ExchangeRates{
+				Country:       "poland",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				Reference:     "075/A/NBP/2023",
+				TimeZone:      plTZ,
+				Rates: map[string]entity.Rate{
+					"PLN": {
+						Nominal:          1,
+						BaseCurrency:     "PLN",
+						TargetCurrency:   "PLN",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "PLN",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("4.6059"),
+					},
+					"JPY": {
+						Nominal:          1,
+						BaseCurrency:     "PLN",
+						TargetCurrency:   "JPY",
+						RateTargetToBase: decimal.RequireFromString("0.031288"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "network timeout",
+			isTimedOut: true,
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 503,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected json arrived from server",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<html>maintenance</html>`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad effective date of the table",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`[{"table":"A","no":"075/A/NBP/2023","effectiveDate":"19.04.2023","rates":[{"code":"EUR","mid":4.6059}]}]`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			url := testServer.URL
+			if tt.isTimedOut {
+				url = timedOutMock.URL
+			}
+			g := &polandCBGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.PolandCBConfig{
+					APIURL:   url,
+					Timezone: tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/fx"
+	"sort"
+	"time"
+)
+
+// Bank contains the metadata of a central bank
+type Bank struct {
+	Country      string         // key the bank is requested by
+	Name         string         // official name of the central bank
+	BaseCurrency string         // currency the rates are set against
+	TimeZone     *time.Location // time zone the rates are set in
+	Website      string
+}
+
+// Registration binds the gateway to the central bank it loads the rates from
+type Registration struct {
+	Bank
+	Gateway CBGateway
+}
+
+// Result is returned by the gateway constructors to self-register the gateway in the Registry
+type Result struct {
+	fx.Out
+
+	Registration Registration `group:"cb_gateways"`
+}
+
+// Results is returned by the constructors registering several gateways at once (e.g. configurable gateways)
+type Results struct {
+	fx.Out
+
+	Registrations []Registration `group:"cb_gateways,flatten"`
+}
+
+// RegistryParams is a container for all Registry dependencies
+type RegistryParams struct {
+	fx.In
+
+	Registrations []Registration `group:"cb_gateways"`
+}
+
+// Registry contains all the registered gateways by country. Repository, validation and external API
+// discover the supported central banks from the Registry, so adding a bank requires only its gateway package.
+type Registry struct {
+	registrations map[string]Registration
+	countries     []string
+}
+
+// NewRegistry is a constructor for the Registry. Every registration must have the unique country and the gateway.
+func NewRegistry(p RegistryParams) (*Registry, error) {
+	r := &Registry{
+		registrations: make(map[string]Registration, len(p.Registrations)),
+		countries:     make([]string, 0, len(p.Registrations)),
+	}
+	for _, reg := range p.Registrations {
+		if reg.Country == "" {
+			return nil, errors.New("gateway registered without country")
+		}
+		if reg.Gateway == nil {
+			return nil, fmt.Errorf("nil gateway registered for %s", reg.Country)
+		}
+		if _, ok := r.registrations[reg.Country]; ok {
+			return nil, fmt.Errorf("gateway for %s registered twice", reg.Country)
+		}
+		r.registrations[reg.Country] = reg
+		r.countries = append(r.countries, reg.Country)
+	}
+	sort.Strings(r.countries)
+	return r, nil
+}
+
+// Gateway returns the gateway of the country central bank
+func (r *Registry) Gateway(country string) (CBGateway, bool) {
+	reg, ok := r.registrations[country]
+	return reg.Gateway, ok
+}
+
+// Bank returns the metadata of the country central bank
+func (r *Registry) Bank(country string) (Bank, bool) {
+	reg, ok := r.registrations[country]
+	return reg.Bank, ok
+}
+
+// Countries returns the countries of all the registered central banks sorted alphabetically
+func (r *Registry) Countries() []string {
+	return append([]string(nil), r.countries...)
+}
+
+// Registrations returns all the registrations sorted by country
+func (r *Registry) Registrations() []Registration {
+	res := make([]Registration, 0, len(r.countries))
+	for _, country := range r.countries {
+		res = append(res, r.registrations[country])
+	}
+	return res
+}
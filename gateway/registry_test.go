@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"testing"
+	"time"
+)
+
+func TestNewRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruGateway := russiagatewaymock.NewMockGateway(ctrl)
+	thGateway := thailandgatewaymock.NewMockGateway(ctrl)
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	russia := Registration{
+		Bank: Bank{
+			Country:      "russia",
+			Name:         "Central Bank of the Russian Federation",
+			BaseCurrency: "RUB",
+			TimeZone:     ruTZ,
+		},
+		Gateway: ruGateway,
+	}
+	thailand := Registration{
+		Bank: Bank{
+			Country:      "thailand",
+			BaseCurrency: "THB",
+		},
+		Gateway: thGateway,
+	}
+	tests := []struct {
+		name              string
+		registrations     []Registration
+		expectedCountries []string
+		assertion         assert.ErrorAssertionFunc
+	}{
+		{
+			name:              "Happy path, sorted by country",
+			registrations:     []Registration{thailand, russia},
+			expectedCountries: []string{"russia", "thailand"},
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "Happy path, no gateways",
+			registrations:     nil,
+			expectedCountries: nil,
+			assertion:         assert.NoError,
+		},
+		{
+			name:          "registered twice",
+			registrations: []Registration{russia, thailand, russia},
+			assertion:     assert.Error,
+		},
+		{
+			name:          "no country",
+			registrations: []Registration{{Gateway: ruGateway}},
+			assertion:     assert.Error,
+		},
+		{
+			name:          "no gateway",
+			registrations: []Registration{{Bank: Bank{Country: "russia"}}},
+			assertion:     assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRegistry(RegistryParams{Registrations: tt.registrations})
+			tt.assertion(t, err)
+			if err != nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.expectedCountries, got.Countries())
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruGateway := russiagatewaymock.NewMockGateway(ctrl)
+	thGateway := thailandgatewaymock.NewMockGateway(ctrl)
+	russia := Registration{Bank: Bank{Country: "russia", BaseCurrency: "RUB"}, Gateway: ruGateway}
+	thailand := Registration{Bank: Bank{Country: "thailand", BaseCurrency: "THB"}, Gateway: thGateway}
+	r, err := NewRegistry(RegistryParams{Registrations: []Registration{thailand, russia}})
+	assert.NoError(t, err)
+
+	gw, ok := r.Gateway("thailand")
+	assert.True(t, ok)
+	assert.Equal(t, thGateway, gw)
+	_, ok = r.Gateway("atlantis")
+	assert.False(t, ok)
+
+	bank, ok := r.Bank("russia")
+	assert.True(t, ok)
+	assert.Equal(t, russia.Bank, bank)
+	_, ok = r.Bank("atlantis")
+	assert.False(t, ok)
+
+	assert.Equal(t, []Registration{russia, thailand}, r.Registrations())
+}
+
+func TestRegistry_fxGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruGateway := russiagatewaymock.NewMockGateway(ctrl)
+	thGateway := thailandgatewaymock.NewMockGateway(ctrl)
+	var r *Registry
+	app := fx.New(
+		fx.Provide(func() Result {
+			return Result{Registration: Registration{Bank: Bank{Country: "russia"}, Gateway: ruGateway}}
+		}),
+		fx.Provide(func() Results {
+			return Results{Registrations: []Registration{
+				{Bank: Bank{Country: "thailand"}, Gateway: thGateway},
+				{Bank: Bank{Country: "norway"}, Gateway: thGateway},
+			}}
+		}),
+		Module,
+		fx.Populate(&r),
+		fx.NopLogger,
+	)
+	assert.NoError(t, app.Err())
+	assert.Equal(t, []string{"norway", "russia", "thailand"}, r.Countries())
+}
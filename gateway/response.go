@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"my_go/entity"
+	"time"
+)
+
+const (
+	// MaxResponseSize is the maximum size of the response body of the central bank API
+	MaxResponseSize = 1 << 20
+	// MaxRangeDays is the maximum number of days of the range loaded from the central bank API with a single request,
+	// so the response of a long time series range never exceeds MaxResponseSize
+	MaxRangeDays = 31
+)
+
+// ReadBody reads the response body of the central bank API. The body exceeding MaxResponseSize fails explicitly
+// instead of being cut, as the truncated response is either malformed or contains partial data.
+func ReadBody(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, MaxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > MaxResponseSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", MaxResponseSize)
+	}
+	return body, nil
+}
+
+// DateRange is an inclusive range of dates in entity.DateLayout format
+type DateRange struct {
+	StartDate string
+	EndDate   string
+}
+
+// SplitDateRange splits the inclusive range into consecutive ranges of at most MaxRangeDays days each.
+// Dates are expected in entity.DateLayout format.
+func SplitDateRange(startDate string, endDate string) ([]DateRange, error) {
+	start, err := time.Parse(entity.DateLayout, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad start date %s provided, err %s", startDate, err)
+	}
+	end, err := time.Parse(entity.DateLayout, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad end date %s provided, err %s", endDate, err)
+	}
+	var res []DateRange
+	for d := start; !d.After(end); d = d.AddDate(0, 0, MaxRangeDays) {
+		last := d.AddDate(0, 0, MaxRangeDays-1)
+		if last.After(end) {
+			last = end
+		}
+		res = append(res, DateRange{
+			StartDate: d.Format(entity.DateLayout),
+			EndDate:   last.Format(entity.DateLayout),
+		})
+	}
+	return res, nil
+}
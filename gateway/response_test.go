@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      []byte
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			body:      `{"rates":[]}`,
+			want:      []byte(`{"rates":[]}`),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, body of the max size",
+			body:      strings.Repeat("a", MaxResponseSize),
+			want:      []byte(strings.Repeat("a", MaxResponseSize)),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "body exceeds the max size",
+			body:      strings.Repeat("a", MaxResponseSize+1),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadBody(strings.NewReader(tt.body))
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitDateRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		startDate string
+		endDate   string
+		want      []DateRange
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, single date",
+			startDate: "2023-04-17",
+			endDate:   "2023-04-17",
+			want:      []DateRange{{StartDate: "2023-04-17", EndDate: "2023-04-17"}},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, range split",
+			startDate: "2023-01-01",
+			endDate:   "2023-03-05",
+			want: []DateRange{
+				{StartDate: "2023-01-01", EndDate: "2023-01-31"},
+				{StartDate: "2023-02-01", EndDate: "2023-03-03"},
+				{StartDate: "2023-03-04", EndDate: "2023-03-05"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, empty range",
+			startDate: "2023-04-18",
+			endDate:   "2023-04-17",
+			want:      nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad start date",
+			startDate: "17.04.2023",
+			endDate:   "2023-04-17",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad end date",
+			startDate: "2023-04-17",
+			endDate:   "17.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitDateRange(tt.startDate, tt.endDate)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,21 +3,33 @@ package russia
 import (
 	"context"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"go.uber.org/config"
-	"io"
 	internalconfig "my_go/config"
 	"my_go/entity"
 	"my_go/gateway"
 	mapper "my_go/mapper/cb"
 	"net/http"
+	"net/url"
+	"sort"
 	"time"
 )
 
-const configKey = "russia_cb_config"
+const (
+	configKey     = "russia_cb_config"
+	dateReqLayout = "02/01/2006"
+	// metalsLookbackDays is the period before the requested date the latest metal prices are looked for
+	// as the prices are not set for weekends and holidays
+	metalsLookbackDays = 7
+)
 
 // Gateway is an interface that implements shared interface of CBGateway for Thailand
 type Gateway interface {
 	gateway.CBGateway
+	gateway.HistoricalCBGateway
+	gateway.TimeSeriesCBGateway
+	gateway.MetalsCBGateway
+	gateway.CurrenciesCBGateway
 }
 
 // Compile time check that russiaCRBGateway implements Gateway interface
@@ -41,50 +53,274 @@ func New(c config.Provider) (Gateway, error) {
 	}, nil
 }
 
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.RussiaCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      entity.Russia,
+				Name:         "Central Bank of the Russian Federation",
+				BaseCurrency: "RUB",
+				TimeZone:     tz,
+				Website:      "https://www.cbr.ru",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
 // GetCBRRates returns exchange rates for the bank of Russia
 func (g *russiaCRBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
-	client := http.Client{
-		Timeout: time.Second * 10,
+	return g.getRates(ctx, g.Config.APIURL, "")
+}
+
+// GetCBRRatesForDate returns exchange rates set by the bank of Russia for the provided date.
+// Bank of Russia API accepts the date in the date_req query parameter.
+func (g *russiaCRBGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	d, err := time.Parse(entity.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %s provided, err %s", date, err)
+	}
+	u, err := url.Parse(g.Config.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.APIURL, err)
+	}
+	q := u.Query()
+	q.Set("date_req", d.Format(dateReqLayout))
+	u.RawQuery = q.Encode()
+	return g.getRates(ctx, u.String(), date)
+}
+
+// GetCBRRatesTimeSeries returns exchange rates set by the bank of Russia for every date of the range
+// the rates were set for. Dynamic API of the bank of Russia returns the rates of a single currency
+// identified by the internal bank ID, so IDs are resolved from the currencies reference first
+// (it lists the currencies no longer quoted in the daily rates too) and then a single dynamic request
+// per currency is made.
+func (g *russiaCRBGateway) GetCBRRatesTimeSeries(
+	ctx context.Context,
+	currencies []string,
+	startDate string,
+	endDate string,
+) ([]entity.ExchangeRates, error) {
+	start, err := time.Parse(entity.DateLayout, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad start date %s provided, err %s", startDate, err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", g.Config.APIURL, nil)
+	end, err := time.Parse(entity.DateLayout, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad end date %s provided, err %s", endDate, err)
+	}
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad tz %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	u, err := url.Parse(g.Config.DynamicAPIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.DynamicAPIURL, err)
+	}
+
+	body, err := g.get(ctx, g.Config.CurrenciesAPIURL)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := mapper.RussiaCBRCurrenciesResponseToCurrencyIDs(body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header = g.getHeaders()
 
-	res, err := client.Do(req)
+	byDate := map[string]map[string]entity.Rate{}
+	for _, currency := range currencies {
+		if currency == "RUB" {
+			continue
+		}
+		id, ok := ids[currency]
+		if !ok {
+			return nil, fmt.Errorf("currency %s not supported by CB %s", currency, entity.Russia)
+		}
+		q := u.Query()
+		q.Set("date_req1", start.Format(dateReqLayout))
+		q.Set("date_req2", end.Format(dateReqLayout))
+		q.Set("VAL_NM_RQ", id)
+		u.RawQuery = q.Encode()
+		body, err := g.get(ctx, u.String())
+		if err != nil {
+			return nil, err
+		}
+		rates, err := mapper.RussiaCBRDynamicResponseToRates(body, currency)
+		if err != nil {
+			return nil, err
+		}
+		for date, rate := range rates {
+			m, ok := byDate[date]
+			if !ok {
+				m = map[string]entity.Rate{
+					"RUB": {
+						Nominal:          1,
+						BaseCurrency:     "RUB",
+						TargetCurrency:   "RUB",
+						RateTargetToBase: decimal.NewFromInt(1),
+					},
+				}
+				byDate[date] = m
+			}
+			m[currency] = rate
+		}
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	res := make([]entity.ExchangeRates, 0, len(dates))
+	for _, date := range dates {
+		res = append(res, entity.ExchangeRates{
+			Country:       entity.Russia,
+			TimeZone:      tz,
+			DateLoaded:    date,
+			EffectiveDate: date,
+			Rates:         byDate[date],
+		})
+	}
+	return res, nil
+}
+
+// GetCBRMetals returns the latest precious metals prices set by the bank of Russia
+func (g *russiaCRBGateway) GetCBRMetals(ctx context.Context) (*entity.MetalPrices, error) {
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad tz %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	now := g.TimeNow().In(tz)
+	return g.getMetals(ctx, tz, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// GetCBRMetalsForDate returns precious metals prices of the bank of Russia in force for the provided date.
+// Prices are not set for weekends and holidays, so the latest prices set within the week before the date
+// are returned with their date as the effective date.
+func (g *russiaCRBGateway) GetCBRMetalsForDate(ctx context.Context, date string) (*entity.MetalPrices, error) {
+	d, err := time.Parse(entity.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %s provided, err %s", date, err)
+	}
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad tz %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	return g.getMetals(ctx, tz, d)
+}
+
+// GetCBRCurrencies returns the reference data of the currencies the bank of Russia sets the rates for
+func (g *russiaCRBGateway) GetCBRCurrencies(ctx context.Context) (map[string]entity.Currency, error) {
+	body, err := g.get(ctx, g.Config.CurrenciesAPIURL)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return mapper.RussiaCBRCurrenciesResponseToCurrencies(body)
+}
+
+// getMetals loads the prices set within the week before the provided date and returns the latest of them.
+// The prices are considered to be loaded for the provided date.
+func (g *russiaCRBGateway) getMetals(ctx context.Context, tz *time.Location, date time.Time) (*entity.MetalPrices, error) {
+	u, err := url.Parse(g.Config.MetalsAPIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.MetalsAPIURL, err)
+	}
+	q := u.Query()
+	q.Set("date_req1", date.AddDate(0, 0, -metalsLookbackDays).Format(dateReqLayout))
+	q.Set("date_req2", date.Format(dateReqLayout))
+	u.RawQuery = q.Encode()
 
-	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	body, err := g.get(ctx, u.String())
 	if err != nil {
 		return nil, err
 	}
-	if c := res.StatusCode; c != 200 {
-		return nil, fmt.Errorf("unexpected status code %d received from %s", c, g.Config.APIURL)
+	prices, effectiveDate, err := mapper.RussiaCBRMetalsResponseToPrices(body)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MetalPrices{
+		Country:       entity.Russia,
+		TimeZone:      tz,
+		DateLoaded:    date.Format(entity.DateLayout),
+		EffectiveDate: effectiveDate,
+		Prices:        prices,
+	}, nil
+}
+
+// getRates loads the rates from the provided url. If date is empty the rates are considered
+// to be loaded for the current date in the timezone of the central bank.
+// Official date the rates are set for is taken from the response.
+func (g *russiaCRBGateway) getRates(ctx context.Context, apiURL string, date string) (*entity.ExchangeRates, error) {
+	body, err := g.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
 	}
 
 	m, err := mapper.RussiaCBRResponseToRates(body)
 	if err != nil {
 		return nil, err
 	}
+	effectiveDate, publishedAt, err := mapper.RussiaCBRResponseToPublicationDate(body)
+	if err != nil {
+		return nil, err
+	}
 
 	tz, err := time.LoadLocation(g.Config.Timezone)
 	if err != nil {
 		return nil, fmt.Errorf("bad tz %s provided in the config, err %s", g.Config.Timezone, err)
 	}
 
-	dateStr := g.TimeNow().In(tz).Format(entity.DateLayout)
+	dateStr := date
+	if dateStr == "" {
+		dateStr = g.TimeNow().In(tz).Format(entity.DateLayout)
+	}
 	return &entity.ExchangeRates{
-		Country:    entity.Russia,
-		TimeZone:   tz,
-		DateLoaded: dateStr,
-		Rates:      m,
+		Country:       entity.Russia,
+		TimeZone:      tz,
+		DateLoaded:    dateStr,
+		EffectiveDate: effectiveDate,
+		PublishedAt:   publishedAt,
+		Rates:         m,
 	}, nil
 }
 
+// get makes GET request to the provided url and returns the body of the response
+func (g *russiaCRBGateway) get(ctx context.Context, apiURL string) ([]byte, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = g.getHeaders()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, apiURL)
+	}
+	return body, nil
+}
+
 func (g *russiaCRBGateway) getHeaders() map[string][]string {
 	return map[string][]string{
 		"User-Agent":   {"Paw/3.3.5 (Macintosh; OS X/13.3.1) GCDHTTPRequest"},
@@ -2,6 +2,7 @@ package russia
 
 import (
 	"context"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/config"
 	internalconfig "my_go/config"
@@ -44,6 +45,22 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"russia_cb_config":{"timezone":"Europe/Moscow"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"russia_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Russia, got.Registration.Country)
+	assert.Equal(t, "RUB", got.Registration.BaseCurrency)
+	assert.Equal(t, "Europe/Moscow", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
 func Test_russiaCRBGateway_GetCBRRates(t *testing.T) {
 	var nilContext context.Context
 	timeNow := func() time.Time {
@@ -90,27 +107,28 @@ func Test_russiaCRBGateway_GetCBRRates(t *testing.T) {
 				TimeZone: "Europe/Moscow",
 			},
 			want: &entity.ExchangeRates{
-				Country:    "russia",
-				DateLoaded: timeNow().Format("2006-01-02"),
-				TimeZone:   ruTZ,
+				Country:       "russia",
+				DateLoaded:    timeNow().Format("2006-01-02"),
+				EffectiveDate: "2023-04-18",
+				TimeZone:      ruTZ,
 				Rates: map[string]entity.Rate{
 					"AUD": {
 						Nominal:          1,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "AUD",
-						RateTargetToBase: 54.8131,
+						RateTargetToBase: decimal.RequireFromString("54.8131"),
 					},
 					"AZN": {
 						Nominal:          10,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "AZN",
-						RateTargetToBase: 48.0164,
+						RateTargetToBase: decimal.RequireFromString("48.0164"),
 					},
 					"RUB": {
 						Nominal:          1,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "RUB",
-						RateTargetToBase: 1,
+						RateTargetToBase: decimal.RequireFromString("1"),
 					},
 				},
 			},
@@ -160,6 +178,17 @@ func Test_russiaCRBGateway_GetCBRRates(t *testing.T) {
 			want:      nil,
 			assertion: assert.Error,
 		},
+		{
+			name:               "bad date in the response",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<ValCurs Date="2023-04-18" name="Foreign Currency Market"></ValCurs>`),
+			fields: fields{
+				TimeNow:  timeNow,
+				TimeZone: "Europe/Moscow",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -202,3 +231,531 @@ func Test_russiaCRBGateway_GetCBRRates(t *testing.T) {
 		})
 	}
 }
+
+func Test_russiaCRBGateway_GetCBRRatesForDate(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	correctXML := []byte(`<ValCurs Date="18.04.2023" name="Foreign Currency Market">
+  <Valute ID="R01010">
+    <NumCode>036</NumCode>
+    <CharCode>AUD</CharCode>
+    <Nominal>1</Nominal>
+    <Name>РђРІСЃС‚СЂР°Р»РёР№СЃРєРёР№ РґРѕР»Р»Р°СЂ</Name>
+    <Value>54,8131</Value>
+  </Valute></ValCurs>
+`)
+	tests := []struct {
+		name               string
+		date               string
+		apiURL             *string
+		httpRespStatusCode int
+		httpRespBody       []byte
+		expectedDateReq    string
+		want               *entity.ExchangeRates
+		assertion          assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			date:               "2023-04-18",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctXML,
+			expectedDateReq:    "18/04/2023",
+			want: &entity.ExchangeRates{
+				Country:       "russia",
+				DateLoaded:    "2023-04-18",
+				EffectiveDate: "2023-04-18",
+				TimeZone:      ruTZ,
+				Rates: map[string]entity.Rate{
+					"AUD": {
+						Nominal:          1,
+						BaseCurrency:     "RUB",
+						TargetCurrency:   "AUD",
+						RateTargetToBase: decimal.RequireFromString("54.8131"),
+					},
+					"RUB": {
+						Nominal:          1,
+						BaseCurrency:     "RUB",
+						TargetCurrency:   "RUB",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			date:      "18.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad api url in config",
+			date:      "2023-04-18",
+			apiURL:    utils.ToPointer(":not a url"),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			date:               "2023-04-18",
+			httpRespStatusCode: 500,
+			expectedDateReq:    "18/04/2023",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.expectedDateReq, r.URL.Query().Get("date_req"))
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer TestServer.Close()
+
+			url := TestServer.URL
+			if tt.apiURL != nil {
+				url = *tt.apiURL
+			}
+			g := &russiaCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.RussiaCBConfig{
+					APIURL:   url,
+					Timezone: "Europe/Moscow",
+				},
+			}
+			got, err := g.GetCBRRatesForDate(context.Background(), tt.date)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_russiaCRBGateway_GetCBRRatesTimeSeries(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	currenciesXML := []byte(`<Valuta name="Foreign Currency Market Lib">
+<Item ID="R01235"><Name>Доллар США</Name><EngName>US Dollar</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01235    </ParentCode><ISO_Num_Code>840</ISO_Num_Code><ISO_Char_Code>USD</ISO_Char_Code></Item>
+</Valuta>`)
+	dynamicXML := []byte(`<ValCurs ID="R01235" DateRange1="14.04.2023" DateRange2="18.04.2023" name="Foreign Currency Market Dynamic">
+<Record Date="15.04.2023" Id="R01235"><Nominal>1</Nominal><Value>81,5280</Value></Record>
+<Record Date="14.04.2023" Id="R01235"><Nominal>1</Nominal><Value>81,7127</Value></Record>
+</ValCurs>`)
+	rub := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "RUB",
+		TargetCurrency:   "RUB",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name            string
+		currencies      []string
+		startDate       string
+		endDate         string
+		timezone        string
+		dynamicAPIURL   *string
+		referenceStatus int
+		dynamicStatus   int
+		dynamicRespBody []byte
+		want            []entity.ExchangeRates
+		assertion       assert.ErrorAssertionFunc
+	}{
+		{
+			name:            "Happy path",
+			currencies:      []string{"USD", "RUB"},
+			startDate:       "2023-04-14",
+			endDate:         "2023-04-18",
+			timezone:        "Europe/Moscow",
+			referenceStatus: 200,
+			dynamicStatus:   200,
+			dynamicRespBody: dynamicXML,
+			want: []entity.ExchangeRates{
+				{
+					Country:       "russia",
+					DateLoaded:    "2023-04-14",
+					EffectiveDate: "2023-04-14",
+					TimeZone:      ruTZ,
+					Rates: map[string]entity.Rate{
+						"RUB": rub,
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "RUB",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("81.7127"),
+						},
+					},
+				},
+				{
+					Country:       "russia",
+					DateLoaded:    "2023-04-15",
+					EffectiveDate: "2023-04-15",
+					TimeZone:      ruTZ,
+					Rates: map[string]entity.Rate{
+						"RUB": rub,
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "RUB",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("81.528"),
+						},
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:            "currency not supported",
+			currencies:      []string{"XXX"},
+			startDate:       "2023-04-14",
+			endDate:         "2023-04-18",
+			timezone:        "Europe/Moscow",
+			referenceStatus: 200,
+			want:            nil,
+			assertion:       assert.Error,
+		},
+		{
+			name:            "currencies reference request fails",
+			currencies:      []string{"USD"},
+			startDate:       "2023-04-14",
+			endDate:         "2023-04-18",
+			timezone:        "Europe/Moscow",
+			referenceStatus: 500,
+			want:            nil,
+			assertion:       assert.Error,
+		},
+		{
+			name:            "dynamic rates request fails",
+			currencies:      []string{"USD"},
+			startDate:       "2023-04-14",
+			endDate:         "2023-04-18",
+			timezone:        "Europe/Moscow",
+			referenceStatus: 200,
+			dynamicStatus:   500,
+			want:            nil,
+			assertion:       assert.Error,
+		},
+		{
+			name:            "bad dynamic xml",
+			currencies:      []string{"USD"},
+			startDate:       "2023-04-14",
+			endDate:         "2023-04-18",
+			timezone:        "Europe/Moscow",
+			referenceStatus: 200,
+			dynamicStatus:   200,
+			dynamicRespBody: []byte(`<ValCurs`),
+			want:            nil,
+			assertion:       assert.Error,
+		},
+		{
+			name:      "bad start date",
+			startDate: "14.04.2023",
+			endDate:   "2023-04-18",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad end date",
+			startDate: "2023-04-14",
+			endDate:   "18.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad timezone in gateway config",
+			startDate: "2023-04-14",
+			endDate:   "2023-04-18",
+			timezone:  "1234",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:          "bad dynamic api url in config",
+			startDate:     "2023-04-14",
+			endDate:       "2023-04-18",
+			timezone:      "Europe/Moscow",
+			dynamicAPIURL: utils.ToPointer(":not a url"),
+			want:          nil,
+			assertion:     assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/currencies" {
+					w.WriteHeader(tt.referenceStatus)
+					w.Write(currenciesXML)
+					return
+				}
+				assert.Equal(t, "R01235", r.URL.Query().Get("VAL_NM_RQ"))
+				assert.Equal(t, "14/04/2023", r.URL.Query().Get("date_req1"))
+				assert.Equal(t, "18/04/2023", r.URL.Query().Get("date_req2"))
+				w.WriteHeader(tt.dynamicStatus)
+				w.Write(tt.dynamicRespBody)
+			}))
+			defer TestServer.Close()
+
+			dynamicURL := TestServer.URL + "/dynamic"
+			if tt.dynamicAPIURL != nil {
+				dynamicURL = *tt.dynamicAPIURL
+			}
+			g := &russiaCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.RussiaCBConfig{
+					CurrenciesAPIURL: TestServer.URL + "/currencies",
+					DynamicAPIURL:    dynamicURL,
+					Timezone:         tt.timezone,
+				},
+			}
+			got, err := g.GetCBRRatesTimeSeries(context.Background(), tt.currencies, tt.startDate, tt.endDate)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+var correctMetalsXML = []byte(`<Metall FromDate="20230412" ToDate="20230419" name="Precious metals quotations">
+<Record Date="18.04.2023" Code="1"><Buy>5050,63</Buy><Sell>5050,63</Sell></Record>
+<Record Date="19.04.2023" Code="1"><Buy>5061,8</Buy><Sell>5061,8</Sell></Record>
+<Record Date="19.04.2023" Code="2"><Buy>63,67</Buy><Sell>63,67</Sell></Record>
+</Metall>`)
+
+func Test_russiaCRBGateway_GetCBRMetals(t *testing.T) {
+	var nilContext context.Context
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 18, 21, 30, 0, 0, time.UTC) // already 19th in Moscow
+	}
+	tests := []struct {
+		name                     string
+		apiURL                   *string
+		httpRequestCreationFails bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		want                     *entity.MetalPrices
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path, latest date of the week",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctMetalsXML,
+			timeZone:           "Europe/Moscow",
+			want: &entity.MetalPrices{
+				Country:       "russia",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      ruTZ,
+				Prices: map[string]entity.MetalPrice{
+					"XAU": {
+						Metal:    "XAU",
+						Name:     "gold",
+						Currency: "RUB",
+						Buy:      decimal.RequireFromString("5061.8"),
+						Sell:     decimal.RequireFromString("5061.8"),
+					},
+					"XAG": {
+						Metal:    "XAG",
+						Name:     "silver",
+						Currency: "RUB",
+						Buy:      decimal.RequireFromString("63.67"),
+						Sell:     decimal.RequireFromString("63.67"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 500,
+			timeZone:           "Europe/Moscow",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "no prices in the response",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<Metall FromDate="20230412" ToDate="20230419"></Metall>`),
+			timeZone:           "Europe/Moscow",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			timeZone:                 "Europe/Moscow",
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:      "bad api url in config",
+			apiURL:    utils.ToPointer(":not a url"),
+			timeZone:  "Europe/Moscow",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad timezone in config",
+			timeZone:  "1234",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "12/04/2023", r.URL.Query().Get("date_req1"))
+				assert.Equal(t, "19/04/2023", r.URL.Query().Get("date_req2"))
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx := context.Background()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+			url := testServer.URL
+			if tt.apiURL != nil {
+				url = *tt.apiURL
+			}
+			g := &russiaCRBGateway{
+				TimeNow: timeNow,
+				Config: internalconfig.RussiaCBConfig{
+					MetalsAPIURL: url,
+					Timezone:     tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRMetals(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_russiaCRBGateway_GetCBRMetalsForDate(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "13/04/2023", r.URL.Query().Get("date_req1"))
+		assert.Equal(t, "20/04/2023", r.URL.Query().Get("date_req2"))
+		w.Write(correctMetalsXML)
+	}))
+	defer testServer.Close()
+	tests := []struct {
+		name      string
+		date      string
+		timeZone  string
+		want      *entity.MetalPrices
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "Happy path, latest prices before the date",
+			date:     "2023-04-20",
+			timeZone: "Europe/Moscow",
+			want: &entity.MetalPrices{
+				Country:       "russia",
+				DateLoaded:    "2023-04-20",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      ruTZ,
+				Prices: map[string]entity.MetalPrice{
+					"XAU": {
+						Metal:    "XAU",
+						Name:     "gold",
+						Currency: "RUB",
+						Buy:      decimal.RequireFromString("5061.8"),
+						Sell:     decimal.RequireFromString("5061.8"),
+					},
+					"XAG": {
+						Metal:    "XAG",
+						Name:     "silver",
+						Currency: "RUB",
+						Buy:      decimal.RequireFromString("63.67"),
+						Sell:     decimal.RequireFromString("63.67"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			date:      "20.04.2023",
+			timeZone:  "Europe/Moscow",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad timezone in config",
+			date:      "2023-04-20",
+			timeZone:  "1234",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &russiaCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.RussiaCBConfig{
+					MetalsAPIURL: testServer.URL,
+					Timezone:     tt.timeZone,
+				},
+			}
+			got, err := g.GetCBRMetalsForDate(context.Background(), tt.date)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_russiaCRBGateway_GetCBRCurrencies(t *testing.T) {
+	tests := []struct {
+		name               string
+		httpRespStatusCode int
+		httpRespBody       []byte
+		want               map[string]entity.Currency
+		assertion          assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			httpRespStatusCode: 200,
+			httpRespBody: []byte(`<Valuta name="Foreign Currency Market Lib"><Item ID="R01235"><Name>Доллар США</Name>` +
+				`<EngName>US Dollar</EngName><Nominal>1</Nominal><ParentCode>R01235    </ParentCode>` +
+				`<ISO_Num_Code>840</ISO_Num_Code><ISO_Char_Code>USD</ISO_Char_Code></Item></Valuta>`),
+			want: map[string]entity.Currency{
+				"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "unexpected status code from server",
+			httpRespStatusCode: 500,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad xml",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<Valuta`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			g := &russiaCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.RussiaCBConfig{
+					CurrenciesAPIURL: testServer.URL,
+					Timezone:         "Europe/Moscow",
+				},
+			}
+			got, err := g.GetCBRCurrencies(context.Background())
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
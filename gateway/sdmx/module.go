@@ -0,0 +1,7 @@
+package sdmx
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(Register),
+)
@@ -0,0 +1,158 @@
+package sdmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/gateway"
+	mapper "my_go/mapper/cb"
+	"net/http"
+	"time"
+)
+
+const (
+	configKey  = "sdmx_banks"
+	FormatJSON = "sdmx-json"
+	FormatML   = "sdmx-ml"
+)
+
+// accept is the media type requested from SDMX REST API for the format
+var accept = map[string]string{
+	FormatJSON: "application/vnd.sdmx.data+json;version=1.0.0",
+	FormatML:   "application/vnd.sdmx.structurespecificdata+xml;version=2.1",
+}
+
+// Gateway is an interface that implements shared interface of CBGateway for a central bank publishing via SDMX
+type Gateway interface {
+	gateway.CBGateway
+}
+
+// Compile time check that sdmxGateway implements Gateway interface
+var _ Gateway = (*sdmxGateway)(nil)
+
+type sdmxGateway struct {
+	TimeNow func() time.Time
+	Config  internalconfig.SDMXBankConfig
+	Series  map[string]cb_entity.SDMXSeries
+}
+
+// New is a constructor for Gateway interface, it validates the config of the bank
+func New(cfg internalconfig.SDMXBankConfig) (Gateway, error) {
+	if cfg.Country == "" {
+		return nil, errors.New("no country provided in the sdmx bank config")
+	}
+	if cfg.BaseCurrency == "" {
+		return nil, fmt.Errorf("no base currency provided in the sdmx config of %s", cfg.Country)
+	}
+	if _, ok := accept[cfg.Format]; !ok {
+		return nil, fmt.Errorf("unsupported format %s provided in the sdmx config of %s", cfg.Format, cfg.Country)
+	}
+	if cfg.Format == FormatML && len(cfg.KeyDimensions) == 0 {
+		return nil, fmt.Errorf("no key dimensions provided in the sdmx config of %s", cfg.Country)
+	}
+	if len(cfg.Series) == 0 {
+		return nil, fmt.Errorf("no series provided in the sdmx config of %s", cfg.Country)
+	}
+	series := make(map[string]cb_entity.SDMXSeries, len(cfg.Series))
+	for key, s := range cfg.Series {
+		if s.Currency == "" || s.Nominal < 0 {
+			return nil, fmt.Errorf("bad series %s provided in the sdmx config of %s", key, cfg.Country)
+		}
+		series[key] = cb_entity.SDMXSeries{
+			Currency: s.Currency,
+			Nominal:  s.Nominal,
+		}
+	}
+	if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+		return nil, fmt.Errorf("bad timezone %s provided in the sdmx config of %s, err %s", cfg.Timezone, cfg.Country, err)
+	}
+	return &sdmxGateway{
+		TimeNow: time.Now,
+		Config:  cfg,
+		Series:  series,
+	}, nil
+}
+
+// Register self-registers the gateways of all the banks configured in sdmx_banks in the gateway.Registry
+func Register(c config.Provider) (gateway.Results, error) {
+	var cfgs []internalconfig.SDMXBankConfig
+	err := c.Get(configKey).Populate(&cfgs)
+	if err != nil {
+		return gateway.Results{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	res := gateway.Results{}
+	for _, cfg := range cfgs {
+		g, err := New(cfg)
+		if err != nil {
+			return gateway.Results{}, err
+		}
+		tz, _ := time.LoadLocation(cfg.Timezone) // validated by the constructor
+		res.Registrations = append(res.Registrations, gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      cfg.Country,
+				Name:         cfg.Name,
+				BaseCurrency: cfg.BaseCurrency,
+				TimeZone:     tz,
+				Website:      cfg.Website,
+			},
+			Gateway: g,
+		})
+	}
+	return res, nil
+}
+
+// GetCBRRates returns the latest exchange rates of the configured series
+func (g *sdmxGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", g.Config.APIURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept[g.Config.Format])
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, g.Config.APIURL)
+	}
+
+	var observations []cb_entity.SDMXObservation
+	if g.Config.Format == FormatML {
+		observations, err = mapper.SDMXMLResponseToObservations(body, g.Config.KeyDimensions)
+	} else {
+		observations, err = mapper.SDMXJSONResponseToObservations(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, effectiveDate, err := mapper.SDMXObservationsToRates(observations, g.Series, g.Config.BaseCurrency, g.Config.Inverse)
+	if err != nil {
+		return nil, err
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	return &entity.ExchangeRates{
+		Country:       g.Config.Country,
+		TimeZone:      tz,
+		DateLoaded:    g.TimeNow().In(tz).Format(entity.DateLayout),
+		EffectiveDate: effectiveDate,
+		Rates:         m,
+	}, nil
+}
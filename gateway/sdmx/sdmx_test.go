@@ -0,0 +1,364 @@
+package sdmx
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var correctJSON = []byte(`{"data":{"dataSets":[{"series":{` +
+	`"0:0:0:0":{"observations":{"0":["10.6"]}},` +
+	`"0:1:0:0":{"observations":{"0":["7.8912"]}}}}],` +
+	`"structure":{"dimensions":{"series":[` +
+	`{"id":"FREQ","values":[{"id":"B"}]},` +
+	`{"id":"BASE_CUR","values":[{"id":"USD"},{"id":"JPY"}]},` +
+	`{"id":"QUOTE_CUR","values":[{"id":"NOK"}]},` +
+	`{"id":"TENOR","values":[{"id":"SP"}]}],` +
+	`"observation":[{"id":"TIME_PERIOD","values":[{"id":"2023-04-19"}]}]}}}}`)
+
+var correctXML = []byte(`<message:StructureSpecificData xmlns:message="http://www.sdmx.org/resources/sdmxml/schemas/v2_1/message">
+	<message:DataSet>
+		<Series FREQ="D" CURRENCY="USD" CURRENCY_DENOM="EUR" EXR_TYPE="SP00" EXR_SUFFIX="A">
+			<Obs TIME_PERIOD="2023-04-19" OBS_VALUE="1.0958"/>
+		</Series>
+	</message:DataSet>
+</message:StructureSpecificData>`)
+
+var norwayConfig = internalconfig.SDMXBankConfig{
+	Country:      "norway",
+	Name:         "Norges Bank",
+	BaseCurrency: "NOK",
+	Website:      "https://www.norges-bank.no",
+	Format:       FormatJSON,
+	Series: map[string]internalconfig.SDMXSeriesConfig{
+		"B.USD.NOK.SP": {Currency: "USD"},
+		"B.JPY.NOK.SP": {Currency: "JPY", Nominal: 100},
+	},
+	Timezone: "Europe/Oslo",
+}
+
+var eurozoneConfig = internalconfig.SDMXBankConfig{
+	Country:       "eurozone_sdw",
+	BaseCurrency:  "EUR",
+	Format:        FormatML,
+	KeyDimensions: []string{"FREQ", "CURRENCY", "CURRENCY_DENOM", "EXR_TYPE", "EXR_SUFFIX"},
+	Series: map[string]internalconfig.SDMXSeriesConfig{
+		"D.USD.EUR.SP00.A": {Currency: "USD"},
+	},
+	Inverse:  true,
+	Timezone: "Europe/Berlin",
+}
+
+func TestNew(t *testing.T) {
+	modify := func(f func(c *internalconfig.SDMXBankConfig)) internalconfig.SDMXBankConfig {
+		c := norwayConfig
+		f(&c)
+		return c
+	}
+	tests := []struct {
+		name      string
+		cfg       internalconfig.SDMXBankConfig
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, sdmx-json",
+			cfg:       norwayConfig,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, sdmx-ml",
+			cfg:       eurozoneConfig,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no country",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.Country = "" }),
+			assertion: assert.Error,
+		},
+		{
+			name:      "no base currency",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.BaseCurrency = "" }),
+			assertion: assert.Error,
+		},
+		{
+			name:      "unsupported format",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.Format = "csv" }),
+			assertion: assert.Error,
+		},
+		{
+			name:      "no key dimensions for sdmx-ml",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.Format = FormatML }),
+			assertion: assert.Error,
+		},
+		{
+			name:      "no series",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.Series = nil }),
+			assertion: assert.Error,
+		},
+		{
+			name: "series without currency",
+			cfg: modify(func(c *internalconfig.SDMXBankConfig) {
+				c.Series = map[string]internalconfig.SDMXSeriesConfig{"B.USD.NOK.SP": {}}
+			}),
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad timezone",
+			cfg:       modify(func(c *internalconfig.SDMXBankConfig) { c.Timezone = "Mars/Olympus" }),
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			tt.assertion(t, err)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`
+sdmx_banks:
+  - country: "norway"
+    name: "Norges Bank"
+    base_currency: "NOK"
+    format: "sdmx-json"
+    series:
+      "B.USD.NOK.SP": {currency: "USD"}
+    timezone: "Europe/Oslo"
+  - country: "eurozone_sdw"
+    base_currency: "EUR"
+    format: "sdmx-ml"
+    key_dimensions: ["FREQ", "CURRENCY", "CURRENCY_DENOM", "EXR_TYPE", "EXR_SUFFIX"]
+    series:
+      "D.USD.EUR.SP00.A": {currency: "USD"}
+    inverse: true
+    timezone: "Europe/Berlin"
+`)))
+	emptyProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{}`)))
+	badProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"sdmx_banks":[{"country":"norway"}]}`)))
+
+	got, err := Register(provider)
+	assert.NoError(t, err)
+	assert.Len(t, got.Registrations, 2)
+	assert.Equal(t, "norway", got.Registrations[0].Country)
+	assert.Equal(t, "Norges Bank", got.Registrations[0].Name)
+	assert.Equal(t, "NOK", got.Registrations[0].BaseCurrency)
+	assert.Equal(t, "Europe/Oslo", got.Registrations[0].TimeZone.String())
+	assert.NotNil(t, got.Registrations[0].Gateway)
+	assert.Equal(t, "eurozone_sdw", got.Registrations[1].Country)
+	assert.Equal(t, []string{"FREQ", "CURRENCY", "CURRENCY_DENOM", "EXR_TYPE", "EXR_SUFFIX"},
+		got.Registrations[1].Gateway.(*sdmxGateway).Config.KeyDimensions)
+	assert.True(t, got.Registrations[1].Gateway.(*sdmxGateway).Config.Inverse)
+
+	got, err = Register(emptyProvider)
+	assert.NoError(t, err)
+	assert.Empty(t, got.Registrations)
+
+	_, err = Register(badProvider)
+	assert.Error(t, err)
+}
+
+func Test_sdmxGateway_GetCBRRates(t *testing.T) {
+	var nilContext context.Context
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 15, 0, 0, 0, time.UTC)
+	}
+	noTZ, _ := time.LoadLocation("Europe/Oslo")
+	deTZ, _ := time.LoadLocation("Europe/Berlin")
+	tests := []struct {
+		name                     string
+		cfg                      internalconfig.SDMXBankConfig
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		httpRespStatusCode       int
+		httpRespBody             []byte
+		timeZone                 string
+		wantAccept               string
+		want                     *entity.ExchangeRates
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path, sdmx-json",
+			cfg:                norwayConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			wantAccept:         "application/vnd.sdmx.data+json;version=1.0.0",
+			want: &entity.ExchangeRates{
+				Country:       "norway",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      noTZ,
+				Rates: map[string]entity.Rate{
+					"NOK": {
+						Nominal:          1,
+						BaseCurrency:     "NOK",
+						TargetCurrency:   "NOK",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"USD": {
+						Nominal:          1,
+						BaseCurrency:     "NOK",
+						TargetCurrency:   "USD",
+						RateTargetToBase: decimal.RequireFromString("10.6"),
+					},
+					"JPY": {
+						Nominal:          100,
+						BaseCurrency:     "NOK",
+						TargetCurrency:   "JPY",
+						RateTargetToBase: decimal.RequireFromString("7.8912"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "Happy path, sdmx-ml",
+			cfg:                eurozoneConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       correctXML,
+			wantAccept:         "application/vnd.sdmx.structurespecificdata+xml;version=2.1",
+			want: &entity.ExchangeRates{
+				Country:       "eurozone_sdw",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				TimeZone:      deTZ,
+				Rates: map[string]entity.Rate{
+					"EUR": {
+						Nominal:          1,
+						BaseCurrency:     "EUR",
+						TargetCurrency:   "EUR",
+						RateTargetToBase: decimal.RequireFromString("1"),
+					},
+					"USD": {
+						Nominal:          1,
+						BaseCurrency:     "EUR",
+						TargetCurrency:   "USD",
+						RateTargetToBase: decimal.RequireFromString("1.0958"),
+						Inverse:          true,
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "network timeout",
+			cfg:        norwayConfig,
+			isTimedOut: true,
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			cfg:                norwayConfig,
+			httpRespStatusCode: 404,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected json arrived from server",
+			cfg:                norwayConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<html>maintenance</html>`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "unexpected xml arrived from server",
+			cfg:                eurozoneConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<html>maintenance</html>`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "no configured series in the response",
+			cfg:                eurozoneConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`<message:StructureSpecificData><message:DataSet/></message:StructureSpecificData>`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			cfg:                norwayConfig,
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			cfg:                      norwayConfig,
+			httpRequestCreationFails: true,
+			want:                     nil,
+			assertion:                assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			cfg:                norwayConfig,
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			timeZone:           "1234",
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			var gotAccept string
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAccept = r.Header.Get("Accept")
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			cfg := tt.cfg
+			cfg.APIURL = testServer.URL
+			if tt.isTimedOut {
+				cfg.APIURL = timedOutMock.URL
+			}
+			g, err := New(cfg)
+			assert.NoError(t, err)
+			sg := g.(*sdmxGateway)
+			sg.TimeNow = timeNow
+			if tt.timeZone != "" {
+				sg.Config.Timezone = tt.timeZone
+			}
+			got, err := g.GetCBRRates(ctx)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.wantAccept != "" {
+				assert.Equal(t, tt.wantAccept, gotAccept)
+			}
+		})
+	}
+}
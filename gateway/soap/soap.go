@@ -0,0 +1,145 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"my_go/gateway"
+	"net/http"
+	"time"
+)
+
+// EnvelopeNamespace is the namespace of SOAP 1.1 envelope
+const EnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+
+const contentType = "text/xml; charset=utf-8"
+
+// Fault is SOAP 1.1 fault returned by the service instead of the response, it is returned by Client.Call as error
+type Fault struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
+	Code    string   `xml:"faultcode"`
+	String  string   `xml:"faultstring"`
+	Actor   string   `xml:"faultactor,omitempty"`
+	Detail  *Detail  `xml:"detail,omitempty"`
+}
+
+// Detail is the application specific information of the fault kept as raw xml
+type Detail struct {
+	Content string `xml:",innerxml"`
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("soap fault %s: %s", f.Code, f.String)
+}
+
+// envelope is used to marshal the request, the namespace of the request element is defined by its XMLName
+type envelope struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	Body    body     `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+}
+
+type body struct {
+	Content interface{}
+}
+
+// Client calls the operations of SOAP 1.1 service
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewClient is a constructor for the Client of the service located at the provided url
+func NewClient(url string) *Client {
+	return &Client{
+		URL: url,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+// Call wraps the request into the envelope, posts it with the action and decodes the content of the response body
+// into the response. The response is decoded with respect to the namespaces, so the XMLName of the response
+// is expected to define the namespace of the response element.
+// *Fault is returned if the service responds with the fault regardless of the status code.
+func (c *Client) Call(ctx context.Context, action string, request interface{}, response interface{}) error {
+	payload, err := xml.Marshal(envelope{Body: body{Content: request}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the request: %s", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewReader(append([]byte(xml.Header), payload...)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("SOAPAction", fmt.Sprintf("%q", action))
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return err
+	}
+	err = decode(b, response)
+	var fault *Fault
+	if errors.As(err, &fault) {
+		return fault
+	}
+	if code := res.StatusCode; code != 200 {
+		return fmt.Errorf("unexpected status code %d received from %s", code, c.URL)
+	}
+	return err
+}
+
+// decode finds the body of the envelope and decodes its first element either into the Fault or into the response
+func decode(b []byte, response interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	inEnvelope, inBody := false, false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return errors.New("no content in the response envelope")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode the response: %s", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case !inEnvelope:
+			if start.Name.Space != EnvelopeNamespace || start.Name.Local != "Envelope" {
+				return fmt.Errorf("unexpected element %s %s instead of the envelope", start.Name.Space, start.Name.Local)
+			}
+			inEnvelope = true
+		case !inBody:
+			if start.Name.Space == EnvelopeNamespace && start.Name.Local == "Body" {
+				inBody = true
+				continue
+			}
+			if err := decoder.Skip(); err != nil { // header
+				return fmt.Errorf("failed to decode the response: %s", err)
+			}
+		default:
+			if start.Name.Space == EnvelopeNamespace && start.Name.Local == "Fault" {
+				fault := &Fault{}
+				if err := decoder.DecodeElement(fault, &start); err != nil {
+					return fmt.Errorf("failed to decode the fault: %s", err)
+				}
+				return fault
+			}
+			if err := decoder.DecodeElement(response, &start); err != nil {
+				return fmt.Errorf("failed to decode the response: %s", err)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"my_go/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type echoRequest struct {
+	XMLName xml.Name `xml:"urn:test Echo"`
+	Text    string   `xml:"urn:test Text"`
+}
+
+type echoResponse struct {
+	XMLName xml.Name `xml:"urn:test EchoResponse"`
+	Text    string   `xml:"urn:test Text"`
+}
+
+const (
+	echoAction      = "urn:test/Echo"
+	correctResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:t="urn:test">
+	<soap:Header><t:Trace>1</t:Trace></soap:Header>
+	<soap:Body><t:EchoResponse><t:Text>hello</t:Text></t:EchoResponse></soap:Body>
+</soap:Envelope>`
+	faultResponse = `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>
+	<faultcode>s:Client</faultcode><faultstring>Unknown action</faultstring><detail><code>42</code></detail>
+</s:Fault></s:Body></s:Envelope>`
+)
+
+func TestClient_Call(t *testing.T) {
+	var nilContext context.Context
+	tests := []struct {
+		name                     string
+		contentLength            *string
+		httpRequestCreationFails bool
+		isTimedOut               bool
+		request                  interface{}
+		httpRespStatusCode       int
+		httpRespBody             string
+		want                     *echoResponse
+		wantFault                *Fault
+		assertion                assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path, header skipped",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctResponse,
+			want: &echoResponse{
+				XMLName: xml.Name{Space: "urn:test", Local: "EchoResponse"},
+				Text:    "hello",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "Happy path, default namespaces",
+			httpRespStatusCode: 200,
+			httpRespBody: `<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body>` +
+				`<EchoResponse xmlns="urn:test"><Text>hello</Text></EchoResponse></Body></Envelope>`,
+			want: &echoResponse{
+				XMLName: xml.Name{Space: "urn:test", Local: "EchoResponse"},
+				Text:    "hello",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "fault with status code 500",
+			httpRespStatusCode: 500,
+			httpRespBody:       faultResponse,
+			want:               &echoResponse{},
+			wantFault: &Fault{
+				XMLName: xml.Name{Space: EnvelopeNamespace, Local: "Fault"},
+				Code:    "s:Client",
+				String:  "Unknown action",
+				Detail:  &Detail{Content: "<code>42</code>"},
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:               "fault with status code 200",
+			httpRespStatusCode: 200,
+			httpRespBody:       faultResponse,
+			want:               &echoResponse{},
+			wantFault: &Fault{
+				XMLName: xml.Name{Space: EnvelopeNamespace, Local: "Fault"},
+				Code:    "s:Client",
+				String:  "Unknown action",
+				Detail:  &Detail{Content: "<code>42</code>"},
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code without fault",
+			httpRespStatusCode: 503,
+			httpRespBody:       `<html>maintenance</html>`,
+			want:               &echoResponse{},
+			assertion:          assert.Error,
+		},
+		{
+			name:               "response element in the wrong namespace",
+			httpRespStatusCode: 200,
+			httpRespBody: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+				`<EchoResponse xmlns="urn:other"><Text>hello</Text></EchoResponse></s:Body></s:Envelope>`,
+			want:      &echoResponse{},
+			assertion: assert.Error,
+		},
+		{
+			name:               "envelope in the wrong namespace",
+			httpRespStatusCode: 200,
+			httpRespBody: `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body>` +
+				`<EchoResponse xmlns="urn:test"><Text>hello</Text></EchoResponse></s:Body></s:Envelope>`,
+			want:      &echoResponse{},
+			assertion: assert.Error,
+		},
+		{
+			name:               "empty body",
+			httpRespStatusCode: 200,
+			httpRespBody:       `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body/></s:Envelope>`,
+			want:               &echoResponse{},
+			assertion:          assert.Error,
+		},
+		{
+			name:               "malformed xml",
+			httpRespStatusCode: 200,
+			httpRespBody:       `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`,
+			want:               &echoResponse{},
+			assertion:          assert.Error,
+		},
+		{
+			name:               "request can't be marshalled",
+			request:            map[string]string{},
+			httpRespStatusCode: 200,
+			httpRespBody:       correctResponse,
+			want:               &echoResponse{},
+			assertion:          assert.Error,
+		},
+		{
+			name:       "network timeout",
+			isTimedOut: true,
+			want:       &echoResponse{},
+			assertion:  assert.Error,
+		},
+		{
+			name:               "failed to read body",
+			contentLength:      utils.ToPointer("1"),
+			httpRespStatusCode: 200,
+			httpRespBody:       correctResponse,
+			want:               &echoResponse{},
+			assertion:          assert.Error,
+		},
+		{
+			name:                     "http request creation fails",
+			httpRequestCreationFails: true,
+			want:                     &echoResponse{},
+			assertion:                assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan interface{})
+			timedOutMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer timedOutMock.Close()
+			defer close(release)
+
+			var gotRequest []byte
+			var gotHeaders http.Header
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRequest, _ = io.ReadAll(r.Body)
+				gotHeaders = r.Header
+				if tt.contentLength != nil {
+					w.Header().Set("Content-Length", *tt.contentLength)
+				}
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write([]byte(tt.httpRespBody))
+			}))
+			defer testServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
+			defer cancel()
+			if tt.httpRequestCreationFails {
+				ctx = nilContext
+			}
+
+			url := testServer.URL
+			if tt.isTimedOut {
+				url = timedOutMock.URL
+			}
+			c := NewClient(url)
+			request := tt.request
+			if request == nil {
+				request = echoRequest{Text: "hello"}
+			}
+			got := &echoResponse{}
+			err := c.Call(ctx, echoAction, request, got)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.wantFault != nil {
+				assert.Equal(t, tt.wantFault, err)
+			}
+			if tt.want.Text != "" {
+				assert.Equal(t, `"urn:test/Echo"`, gotHeaders.Get("SOAPAction"))
+				assert.Equal(t, "text/xml; charset=utf-8", gotHeaders.Get("Content-Type"))
+				assert.Equal(t, xml.Header+
+					`<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body xmlns="http://schemas.xmlsoap.org/soap/envelope/">`+
+					`<Echo xmlns="urn:test"><Text xmlns="urn:test">hello</Text></Echo></Body></Envelope>`,
+					string(gotRequest))
+			}
+		})
+	}
+}
+
+func TestFault_Error(t *testing.T) {
+	f := &Fault{Code: "soap:Server", String: "Internal error"}
+	assert.Equal(t, "soap fault soap:Server: Internal error", f.Error())
+}
@@ -4,20 +4,28 @@ import (
 	"context"
 	"fmt"
 	"go.uber.org/config"
-	"io"
 	internalconfig "my_go/config"
 	"my_go/entity"
 	"my_go/gateway"
 	mapper "my_go/mapper/cb"
 	"net/http"
+	"net/url"
+	"sort"
 	"time"
 )
 
-const configKey = "thailand_cb_config"
+const (
+	configKey = "thailand_cb_config"
+	// historicalLookbackDays is the period before the requested date the latest published rates are looked for
+	// as the historical API has no rates for weekends and holidays
+	historicalLookbackDays = 7
+)
 
 // Gateway is an interface that implements shared interface of CBGateway for Thailand
 type Gateway interface {
 	gateway.CBGateway
+	gateway.HistoricalCBGateway
+	gateway.TimeSeriesCBGateway
 }
 
 // Compile time check that thailandCRBGateway implements Gateway interface
@@ -41,6 +49,31 @@ func New(c config.Provider) (Gateway, error) {
 	}, nil
 }
 
+// Register self-registers the gateway in the gateway.Registry along with the metadata of the bank
+func Register(g Gateway, c config.Provider) (gateway.Result, error) {
+	var cfg internalconfig.ThailandCBConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return gateway.Result{}, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return gateway.Result{}, fmt.Errorf("bad timezone %s provided in the config, err %s", cfg.Timezone, err)
+	}
+	return gateway.Result{
+		Registration: gateway.Registration{
+			Bank: gateway.Bank{
+				Country:      entity.Thailand,
+				Name:         "Bank of Thailand",
+				BaseCurrency: "THB",
+				TimeZone:     tz,
+				Website:      "https://www.bot.or.th",
+			},
+			Gateway: g,
+		},
+	}, nil
+}
+
 // GetCBRRates returns exchange rates for the bank of Thailand
 func (g *thailandCRBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
 	client := http.Client{
@@ -57,7 +90,7 @@ func (g *thailandCRBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeR
 	}
 	defer res.Body.Close()
 
-	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	body, err := gateway.ReadBody(res.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +102,10 @@ func (g *thailandCRBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeR
 	if err != nil {
 		return nil, err
 	}
+	effectiveDate, publishedAt, err := mapper.ThailandCBRResponseToPublicationDate(body)
+	if err != nil {
+		return nil, err
+	}
 
 	tz, err := time.LoadLocation(g.Config.Timezone)
 	if err != nil {
@@ -76,9 +113,130 @@ func (g *thailandCRBGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeR
 	}
 	dateStr := g.TimeNow().In(tz).Format(entity.DateLayout)
 	return &entity.ExchangeRates{
-		Country:    entity.Thailand,
-		TimeZone:   tz,
-		DateLoaded: dateStr,
-		Rates:      m,
+		Country:       entity.Thailand,
+		TimeZone:      tz,
+		DateLoaded:    dateStr,
+		EffectiveDate: effectiveDate,
+		PublishedAt:   publishedAt,
+		Rates:         m,
+	}, nil
+}
+
+// GetCBRRatesForDate returns exchange rates of the bank of Thailand in force for the provided date.
+// Daily RSS feed contains only the latest rates, so the historical API of Bank of Thailand is used instead.
+// The API has no rates for weekends and holidays, so the latest rates published within the week before
+// the date are returned with their date as the effective date.
+func (g *thailandCRBGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	d, err := time.Parse(entity.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %s provided, err %s", date, err)
+	}
+	body, err := g.getHistorical(ctx, d.AddDate(0, 0, -historicalLookbackDays).Format(entity.DateLayout), date)
+	if err != nil {
+		return nil, err
+	}
+
+	m, effectiveDate, err := mapper.ThailandCBRHistoricalResponseToRates(body, date)
+	if err != nil {
+		return nil, err
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	return &entity.ExchangeRates{
+		Country:       entity.Thailand,
+		TimeZone:      tz,
+		DateLoaded:    date,
+		EffectiveDate: effectiveDate,
+		Rates:         m,
 	}, nil
 }
+
+// GetCBRRatesTimeSeries returns exchange rates set by the bank of Thailand for every business day of the range.
+// Historical API returns all currencies for the range in a single response, so currencies are not used for filtering.
+// Long range is loaded in chunks of gateway.MaxRangeDays, so every response stays within gateway.MaxResponseSize.
+func (g *thailandCRBGateway) GetCBRRatesTimeSeries(
+	ctx context.Context,
+	currencies []string,
+	startDate string,
+	endDate string,
+) ([]entity.ExchangeRates, error) {
+	ranges, err := gateway.SplitDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	byDate := map[string]map[string]entity.Rate{}
+	for _, r := range ranges {
+		body, err := g.getHistorical(ctx, r.StartDate, r.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := mapper.ThailandCBRHistoricalResponseToRatesByDate(body)
+		if err != nil {
+			return nil, err
+		}
+		for date, m := range chunk {
+			byDate[date] = m
+		}
+	}
+
+	tz, err := time.LoadLocation(g.Config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("bad location %s provided in the config, err %s", g.Config.Timezone, err)
+	}
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	res := make([]entity.ExchangeRates, 0, len(dates))
+	for _, date := range dates {
+		res = append(res, entity.ExchangeRates{
+			Country:       entity.Thailand,
+			TimeZone:      tz,
+			DateLoaded:    date,
+			EffectiveDate: date,
+			Rates:         byDate[date],
+		})
+	}
+	return res, nil
+}
+
+// getHistorical loads the response of the historical API of Bank of Thailand for the provided period
+func (g *thailandCRBGateway) getHistorical(ctx context.Context, startDate string, endDate string) ([]byte, error) {
+	u, err := url.Parse(g.Config.HistoricalAPIURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad api url %s provided in the config, err %s", g.Config.HistoricalAPIURL, err)
+	}
+	q := u.Query()
+	q.Set("start_period", startDate)
+	q.Set("end_period", endDate)
+	u.RawQuery = q.Encode()
+
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-IBM-Client-Id", g.Config.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := gateway.ReadBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c := res.StatusCode; c != 200 {
+		return nil, fmt.Errorf("unexpected status code %d received from %s", c, g.Config.HistoricalAPIURL)
+	}
+	return body, nil
+}
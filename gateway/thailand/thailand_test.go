@@ -1,11 +1,14 @@
 package thailand
 
 import (
+	"bytes"
 	"context"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/config"
 	internalconfig "my_go/config"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/utils"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +17,20 @@ import (
 	"time"
 )
 
+func decPtr(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+// quotes builds the quotes catalogue of the rate from the pairs of rate type and value
+func quotes(pairs ...string) map[string]decimal.Decimal {
+	m := map[string]decimal.Decimal{}
+	for i := 0; i < len(pairs); i += 2 {
+		m[pairs[i]] = decimal.RequireFromString(pairs[i+1])
+	}
+	return m
+}
+
 func TestNew(t *testing.T) {
 	src := config.Source(
 		strings.NewReader(`{}`),
@@ -45,6 +62,22 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestRegister(t *testing.T) {
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"thailand_cb_config":{"timezone":"Asia/Bangkok"}}`)))
+	badTZProvider, _ := config.NewYAML(config.Source(strings.NewReader(`{"thailand_cb_config":{"timezone":"Mars/Olympus"}}`)))
+	g, _ := New(provider)
+
+	got, err := Register(g, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Thailand, got.Registration.Country)
+	assert.Equal(t, "THB", got.Registration.BaseCurrency)
+	assert.Equal(t, "Asia/Bangkok", got.Registration.TimeZone.String())
+	assert.Equal(t, g, got.Registration.Gateway)
+
+	_, err = Register(g, badTZProvider)
+	assert.Error(t, err)
+}
+
 func Test_thailandCRBGateway_GetCBRRates(t *testing.T) {
 	var nilContext context.Context
 	timeNow := func() time.Time {
@@ -176,27 +209,36 @@ func Test_thailandCRBGateway_GetCBRRates(t *testing.T) {
 				TimeZone: "Asia/Bangkok",
 			},
 			want: &entity.ExchangeRates{
-				Country:    "thailand",
-				DateLoaded: timeNow().Format("2006-01-02"),
-				TimeZone:   thTZ,
+				Country:       "thailand",
+				DateLoaded:    timeNow().Format("2006-01-02"),
+				EffectiveDate: "2023-04-17",
+				TimeZone:      thTZ,
 				Rates: map[string]entity.Rate{
 					"THB": {
 						Nominal:          1,
-						RateTargetToBase: 1,
+						RateTargetToBase: decimal.RequireFromString("1"),
 						BaseCurrency:     "THB",
 						TargetCurrency:   "THB",
 					},
 					"GBP": {
 						Nominal:          1,
-						RateTargetToBase: 42.5171,
+						RateTargetToBase: decimal.RequireFromString("42.5171"),
 						BaseCurrency:     "THB",
 						TargetCurrency:   "GBP",
+						Bid:              decPtr("42.004"),
+						Ask:              decPtr("43.0302"),
+						Mid:              decPtr("42.5171"),
+						Quotes:           quotes(entity.RateTypeBuyingSight, "42.004", entity.RateTypeBuyingTransfer, "42.1612", entity.RateTypeSelling, "43.0302"),
 					},
 					"USD": {
 						Nominal:          1,
-						RateTargetToBase: 34.28235,
+						RateTargetToBase: decimal.RequireFromString("34.28235"),
 						BaseCurrency:     "THB",
 						TargetCurrency:   "USD",
+						Bid:              decPtr("34.0625"),
+						Ask:              decPtr("34.5022"),
+						Mid:              decPtr("34.28235"),
+						Quotes:           quotes(entity.RateTypeBuyingSight, "34.0625", entity.RateTypeBuyingTransfer, "34.1656", entity.RateTypeSelling, "34.5022"),
 					},
 				},
 			},
@@ -288,3 +330,325 @@ func Test_thailandCRBGateway_GetCBRRates(t *testing.T) {
 		})
 	}
 }
+
+func Test_thailandCRBGateway_GetCBRRatesForDate(t *testing.T) {
+	thTZ, _ := time.LoadLocation("Asia/Bangkok")
+	correctJSON := []byte(`{"result":{"success":"true","api":"Daily Weighted-average Interbank Exchange Rate - THB / USD",
+"data":{"data_header":{"last_updated":"2023-04-17"},"data_detail":[
+{"period":"2023-04-17","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0625000","buying_transfer":"34.1656000","selling":"34.5022000","mid_rate":"34.3339000"}]}}}`)
+	weekJSON := []byte(`{"result":{"success":"true","data":{"data_detail":[
+{"period":"2023-04-14","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0000000","buying_transfer":"34.1000000","selling":"35.0000000","mid_rate":"34.5000000"},
+{"period":"2023-04-13","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"33.0000000","buying_transfer":"33.1000000","selling":"34.0000000","mid_rate":"33.5000000"}]}}}`)
+	tests := []struct {
+		name               string
+		date               string
+		wantStartPeriod    string
+		apiURL             *string
+		httpRespStatusCode int
+		httpRespBody       []byte
+		want               *entity.ExchangeRates
+		assertion          assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			date:               "2023-04-17",
+			wantStartPeriod:    "2023-04-10",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want: &entity.ExchangeRates{
+				Country:       "thailand",
+				DateLoaded:    "2023-04-17",
+				EffectiveDate: "2023-04-17",
+				TimeZone:      thTZ,
+				Rates: map[string]entity.Rate{
+					"THB": {
+						Nominal:          1,
+						RateTargetToBase: decimal.RequireFromString("1"),
+						BaseCurrency:     "THB",
+						TargetCurrency:   "THB",
+					},
+					"USD": {
+						Nominal:          1,
+						RateTargetToBase: decimal.RequireFromString("34.28235"),
+						BaseCurrency:     "THB",
+						TargetCurrency:   "USD",
+						Bid:              decPtr("34.0625"),
+						Ask:              decPtr("34.5022"),
+						Mid:              decPtr("34.28235"),
+						Quotes:           quotes(entity.RateTypeBuyingSight, "34.0625", entity.RateTypeBuyingTransfer, "34.1656", entity.RateTypeSelling, "34.5022", entity.RateTypeMidRate, "34.3339"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:               "Happy path, weekend date takes the latest rates published before it",
+			date:               "2023-04-16",
+			wantStartPeriod:    "2023-04-09",
+			httpRespStatusCode: 200,
+			httpRespBody:       weekJSON,
+			want: &entity.ExchangeRates{
+				Country:       "thailand",
+				DateLoaded:    "2023-04-16",
+				EffectiveDate: "2023-04-14",
+				TimeZone:      thTZ,
+				Rates: map[string]entity.Rate{
+					"THB": {
+						Nominal:          1,
+						RateTargetToBase: decimal.RequireFromString("1"),
+						BaseCurrency:     "THB",
+						TargetCurrency:   "THB",
+					},
+					"USD": {
+						Nominal:          1,
+						RateTargetToBase: decimal.RequireFromString("34.5"),
+						BaseCurrency:     "THB",
+						TargetCurrency:   "USD",
+						Bid:              decPtr("34"),
+						Ask:              decPtr("35"),
+						Mid:              decPtr("34.5"),
+						Quotes:           quotes(entity.RateTypeBuyingSight, "34", entity.RateTypeBuyingTransfer, "34.1", entity.RateTypeSelling, "35", entity.RateTypeMidRate, "34.5"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			date:      "17.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad api url in config",
+			date:      "2023-04-17",
+			apiURL:    utils.ToPointer(":not a url"),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			date:               "2023-04-17",
+			wantStartPeriod:    "2023-04-10",
+			httpRespStatusCode: 401,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "no rates published within the week before the date",
+			date:               "2023-04-16",
+			wantStartPeriod:    "2023-04-09",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "some key", r.Header.Get("X-IBM-Client-Id"))
+				assert.Equal(t, tt.wantStartPeriod, r.URL.Query().Get("start_period"))
+				assert.Equal(t, tt.date, r.URL.Query().Get("end_period"))
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer TestServer.Close()
+
+			url := TestServer.URL
+			if tt.apiURL != nil {
+				url = *tt.apiURL
+			}
+			g := &thailandCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.ThailandCBConfig{
+					HistoricalAPIURL: url,
+					APIKey:           "some key",
+					Timezone:         "Asia/Bangkok",
+				},
+			}
+			got, err := g.GetCBRRatesForDate(context.Background(), tt.date)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_thailandCRBGateway_GetCBRRatesTimeSeries(t *testing.T) {
+	thTZ, _ := time.LoadLocation("Asia/Bangkok")
+	correctJSON := []byte(`{"result":{"success":"true","data":{"data_detail":[
+{"period":"2023-04-18","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0000000","buying_transfer":"34.1000000","selling":"35.0000000","mid_rate":"34.5000000"},
+{"period":"2023-04-17","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0625000","buying_transfer":"34.1656000","selling":"34.5022000","mid_rate":"34.3339000"}]}}}`)
+	thb := entity.Rate{
+		Nominal:          1,
+		RateTargetToBase: decimal.RequireFromString("1"),
+		BaseCurrency:     "THB",
+		TargetCurrency:   "THB",
+	}
+	tests := []struct {
+		name               string
+		startDate          string
+		endDate            string
+		timezone           string
+		httpRespStatusCode int
+		httpRespBody       []byte
+		want               []entity.ExchangeRates
+		assertion          assert.ErrorAssertionFunc
+	}{
+		{
+			name:               "Happy path",
+			startDate:          "2023-04-17",
+			endDate:            "2023-04-18",
+			timezone:           "Asia/Bangkok",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want: []entity.ExchangeRates{
+				{
+					Country:       "thailand",
+					DateLoaded:    "2023-04-17",
+					EffectiveDate: "2023-04-17",
+					TimeZone:      thTZ,
+					Rates: map[string]entity.Rate{
+						"THB": thb,
+						"USD": {
+							Nominal:          1,
+							RateTargetToBase: decimal.RequireFromString("34.28235"),
+							BaseCurrency:     "THB",
+							TargetCurrency:   "USD",
+							Bid:              decPtr("34.0625"),
+							Ask:              decPtr("34.5022"),
+							Mid:              decPtr("34.28235"),
+							Quotes:           quotes(entity.RateTypeBuyingSight, "34.0625", entity.RateTypeBuyingTransfer, "34.1656", entity.RateTypeSelling, "34.5022", entity.RateTypeMidRate, "34.3339"),
+						},
+					},
+				},
+				{
+					Country:       "thailand",
+					DateLoaded:    "2023-04-18",
+					EffectiveDate: "2023-04-18",
+					TimeZone:      thTZ,
+					Rates: map[string]entity.Rate{
+						"THB": thb,
+						"USD": {
+							Nominal:          1,
+							RateTargetToBase: decimal.RequireFromString("34.5"),
+							BaseCurrency:     "THB",
+							TargetCurrency:   "USD",
+							Bid:              decPtr("34"),
+							Ask:              decPtr("35"),
+							Mid:              decPtr("34.5"),
+							Quotes:           quotes(entity.RateTypeBuyingSight, "34", entity.RateTypeBuyingTransfer, "34.1", entity.RateTypeSelling, "35", entity.RateTypeMidRate, "34.5"),
+						},
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad start date",
+			startDate: "17.04.2023",
+			endDate:   "2023-04-18",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad end date",
+			startDate: "2023-04-17",
+			endDate:   "18.04.2023",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:               "unexpected status code from server",
+			startDate:          "2023-04-17",
+			endDate:            "2023-04-18",
+			httpRespStatusCode: 500,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad json from server",
+			startDate:          "2023-04-17",
+			endDate:            "2023-04-18",
+			httpRespStatusCode: 200,
+			httpRespBody:       []byte(`{"result"`),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "bad timezone in gateway config",
+			startDate:          "2023-04-17",
+			endDate:            "2023-04-18",
+			timezone:           "1234",
+			httpRespStatusCode: 200,
+			httpRespBody:       correctJSON,
+			want:               nil,
+			assertion:          assert.Error,
+		},
+		{
+			name:               "response over the size limit",
+			startDate:          "2023-04-17",
+			endDate:            "2023-04-18",
+			timezone:           "Asia/Bangkok",
+			httpRespStatusCode: 200,
+			httpRespBody:       append(append([]byte{}, correctJSON...), bytes.Repeat([]byte(" "), gateway.MaxResponseSize)...),
+			want:               nil,
+			assertion:          assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.startDate, r.URL.Query().Get("start_period"))
+				assert.Equal(t, tt.endDate, r.URL.Query().Get("end_period"))
+				w.WriteHeader(tt.httpRespStatusCode)
+				w.Write(tt.httpRespBody)
+			}))
+			defer TestServer.Close()
+
+			g := &thailandCRBGateway{
+				TimeNow: time.Now,
+				Config: internalconfig.ThailandCBConfig{
+					HistoricalAPIURL: TestServer.URL,
+					Timezone:         tt.timezone,
+				},
+			}
+			got, err := g.GetCBRRatesTimeSeries(context.Background(), []string{"USD"}, tt.startDate, tt.endDate)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_thailandCRBGateway_GetCBRRatesTimeSeries_longRange(t *testing.T) {
+	var periods [][2]string
+	TestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start_period")
+		periods = append(periods, [2]string{start, r.URL.Query().Get("end_period")})
+		w.WriteHeader(200)
+		w.Write([]byte(`{"result":{"success":"true","data":{"data_detail":[
+{"period":"` + start + `","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0000000","buying_transfer":"34.1000000","selling":"35.0000000","mid_rate":"34.5000000"}]}}}`))
+	}))
+	defer TestServer.Close()
+
+	g := &thailandCRBGateway{
+		TimeNow: time.Now,
+		Config: internalconfig.ThailandCBConfig{
+			HistoricalAPIURL: TestServer.URL,
+			Timezone:         "Asia/Bangkok",
+		},
+	}
+	got, err := g.GetCBRRatesTimeSeries(context.Background(), []string{"USD"}, "2023-03-01", "2023-04-18")
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{{"2023-03-01", "2023-03-31"}, {"2023-04-01", "2023-04-18"}}, periods)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "2023-03-01", got[0].EffectiveDate)
+		assert.Equal(t, "2023-04-01", got[1].EffectiveDate)
+	}
+}
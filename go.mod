@@ -5,9 +5,12 @@ go 1.19
 require (
 	github.com/golang/mock v1.6.0
 	github.com/pkg/errors v0.8.1
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/config v1.4.0
 	go.uber.org/fx v1.19.2
+	go.uber.org/zap v1.23.0
 	golang.org/x/net v0.9.0
 )
 
@@ -17,7 +20,6 @@ require (
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/dig v1.16.1 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	go.uber.org/zap v1.23.0 // indirect
 	golang.org/x/lint v0.0.0-20190930215403-16217165b5de // indirect
 	golang.org/x/sys v0.7.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
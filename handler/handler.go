@@ -13,10 +13,21 @@ import (
 	"time"
 )
 
+// maxBatchBodySize limits the body of the batch requests, which are larger than the body of the other requests
+const maxBatchBodySize = 4 << 20
+
 // Handler interface encapsulates external endpoints for the service
 type Handler interface {
 	GetCBRates(w http.ResponseWriter, req *http.Request)
 	ConvertCurrency(w http.ResponseWriter, req *http.Request)
+	ConvertBatch(w http.ResponseWriter, req *http.Request)
+	ConvertMulti(w http.ResponseWriter, req *http.Request)
+	ConvertCompare(w http.ResponseWriter, req *http.Request)
+	GetRateMatrix(w http.ResponseWriter, req *http.Request)
+	GetTimeSeries(w http.ResponseWriter, req *http.Request)
+	ListBanks(w http.ResponseWriter, req *http.Request)
+	ListCurrencies(w http.ResponseWriter, req *http.Request)
+	GetMetals(w http.ResponseWriter, req *http.Request)
 	Hello(w http.ResponseWriter, req *http.Request)
 }
 
@@ -205,3 +216,496 @@ func (h *handler) ConvertCurrency(w http.ResponseWriter, req *http.Request) {
 	logger.With("response", response).Info("Request completed")
 	return
 }
+
+// ConvertBatch is the POST endpoint to convert a batch of amounts, the items fail individually
+// Expected json is defined by entity.ConvertBatchRequest
+// Expected response is defined by entity.ConvertBatchResponse
+func (h *handler) ConvertBatch(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "ConvertBatch"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxBatchBodySize))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	convertBatchRequest, err := mapper.BodyToConvertBatchRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.conversionCtrl.ConvertBatch(req.Context(), convertBatchRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	convertBatchResponse, err := mapper.ConvertBatchResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(convertBatchResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.With("items", len(response.Results)).Info("Request completed")
+	return
+}
+
+// ConvertMulti is the POST endpoint to convert amount of currency to several currencies with the same rates
+// Expected json is defined by entity.ConvertMultiRequest
+// Expected response is defined by entity.ConvertMultiResponse
+func (h *handler) ConvertMulti(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "ConvertMulti"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	convertMultiRequest, err := mapper.BodyToConvertMultiRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.conversionCtrl.ConvertMulti(req.Context(), convertMultiRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	convertMultiResponse, err := mapper.ConvertMultiResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(convertMultiResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.Info("Request completed")
+	return
+}
+
+// ConvertCompare is the POST endpoint to convert amount of currency with the rates of every supported central bank
+// Expected json is defined by entity.ConvertCompareRequest
+// Expected response is defined by entity.ConvertCompareResponse
+func (h *handler) ConvertCompare(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "ConvertCompare"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	convertCompareRequest, err := mapper.BodyToConvertCompareRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.conversionCtrl.Compare(req.Context(), convertCompareRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	convertCompareResponse, err := mapper.ConvertCompareResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(convertCompareResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.Info("Request completed")
+	return
+}
+
+// GetRateMatrix is the POST endpoint that calculates the cross rates of every pair of the currencies with the same rates
+// Expected json is defined by entity.GetRateMatrixRequest
+// Expected response is defined by entity.GetRateMatrixResponse
+func (h *handler) GetRateMatrix(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "GetRateMatrix"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	getRateMatrixRequest, err := mapper.BodyToGetRateMatrixRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.conversionCtrl.GetRateMatrix(req.Context(), getRateMatrixRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	getRateMatrixResponse, err := mapper.GetRateMatrixResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(getRateMatrixResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.Info("Request completed")
+	return
+}
+
+// GetTimeSeries is the POST endpoint that loads the rate of a currency pair for every business day of the date range
+// Expected json is defined by entity.GetTimeSeriesRequest
+// Expected response is defined by entity.GetTimeSeriesResponse
+func (h *handler) GetTimeSeries(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "GetTimeSeries"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	getTimeSeriesRequest, err := mapper.BodyToGetTimeSeriesRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.conversionCtrl.GetTimeSeries(req.Context(), getTimeSeriesRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	getTimeSeriesResponse, err := mapper.GetTimeSeriesResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(getTimeSeriesResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.With("response", response).Info("Request completed")
+	return
+}
+
+// ListBanks is the GET endpoint that lists all the supported central banks
+// Expected response is defined by entity.ListBanksResponse
+func (h *handler) ListBanks(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "ListBanks"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodGet {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	response, err := h.repositoryCtrl.ListBanks(req.Context())
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	listBanksResponse, err := mapper.ListBanksResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(listBanksResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.Info("Request completed")
+	return
+}
+
+// ListCurrencies is the GET endpoint that lists the reference data of all the known currencies
+// along with the central banks quoting them
+// Expected response is defined by entity.ListCurrenciesResponse
+func (h *handler) ListCurrencies(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "ListCurrencies"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodGet {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	response, err := h.repositoryCtrl.ListCurrencies(req.Context())
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	listCurrenciesResponse, err := mapper.ListCurrenciesResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(listCurrenciesResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.Info("Request completed")
+	return
+}
+
+// GetMetals is the POST endpoint that loads precious metals prices set by the Central Bank provided in the request
+// Expected json request is defined by entity.GetMetalsRequest
+// Expected json response is defined by entity.GetMetalsResponse
+func (h *handler) GetMetals(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.With(
+		zap.String("scope", "handler"),
+		zap.String("function", "GetMetals"),
+	).Sugar()
+	logger.Info("Request received")
+	if req == nil || req.Method != http.MethodPost {
+		http.Error(w, entity.MethodNotAllowed, http.StatusMethodNotAllowed)
+		logger.Error(entity.MethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, entity.UnableToReadTheBody, http.StatusBadRequest)
+		logger.Error(entity.UnableToReadTheBody)
+		return
+	}
+	getMetalsRequest, err := mapper.BodyToGetMetalsRequest(data)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.BadRequest, err),
+			http.StatusBadRequest,
+		)
+		logger.Errorf(entity.BadRequest, err)
+		return
+	}
+	response, err := h.repositoryCtrl.GetMetals(req.Context(), getMetalsRequest)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheRequest, err),
+			http.StatusBadGateway,
+		)
+		logger.Errorf(entity.FailedToProcessTheRequest, err)
+		return
+	}
+	getMetalsResponse, err := mapper.GetMetalsResponseToBytes(response)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToProcessTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToProcessTheResponse, err)
+		return // unreachable in tests cause response struct can always be represented as json
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(getMetalsResponse)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf(entity.FailedToWriteTheResponse, err),
+			http.StatusInternalServerError,
+		)
+		logger.Errorf(entity.FailedToWriteTheResponse, err)
+		return // unreachable in tests
+	}
+	logger.With("response", response).Info("Request completed")
+	return
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"my_go/entity"
 	"my_go/mapper"
 	cb_repositorymock "my_go/mocks/controller/cb_repository"
 	conversionmock "my_go/mocks/controller/conversion"
+	"my_go/utils"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -70,14 +72,14 @@ func Test_handler_GetCBRates(t *testing.T) {
 							Nominal:          100,
 							BaseCurrency:     "RUB",
 							TargetCurrency:   "USD",
-							RateTargetToBase: 123.56,
+							RateTargetToBase: decimal.RequireFromString("123.56"),
 						},
 					},
 				},
 				err: nil,
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"rates":{"USD":{"nominal":100,"base_currency":"RUB","target_currency":"USD","rate_target_to_base":123.56}}}`,
+			expectedResponse:   `{"rates":{"USD":{"nominal":100,"base_currency":"RUB","target_currency":"USD","rate_target_to_base":"123.56"}}}`,
 		},
 		{
 			name: "wrong request method",
@@ -183,11 +185,11 @@ func Test_handler_ConvertCurrency(t *testing.T) {
 			},
 			mockConversionController: &mockConversionController{
 				res: &entity.ConvertCurrencyResponse{
-					Amount: 12.2345,
+					Amount: decimal.RequireFromString("12.2345"),
 				},
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"amount":12.2345}`,
+			expectedResponse:   `{"amount":"12.2345"}`,
 		},
 		{
 			name: "wrong request method",
@@ -219,6 +221,36 @@ func Test_handler_ConvertCurrency(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   "bad request, err failed to unmarshal: unexpected end of JSON input\n",
 		},
+		{
+			name: "amount has more decimal places than source currency allows",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"russia","source_currency":"RUB","target_currency":"USD","amount":"10.005"}`),
+				url:    "/convert",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err amount 10.005 has more than 2 decimal places allowed for RUB\n",
+		},
+		{
+			name: "missing amount",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"russia","source_currency":"RUB","target_currency":"USD"}`),
+				url:    "/convert",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err amount is required\n",
+		},
+		{
+			name: "negative amount",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"russia","source_currency":"RUB","target_currency":"USD","amount":-100}`),
+				url:    "/convert",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err amount can't be negative\n",
+		},
 		{
 			name: "controller fails",
 			args: args{
@@ -263,3 +295,839 @@ func Test_handler_ConvertCurrency(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_ConvertBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := []byte(`{"items":[{"country":"russia","source_currency":"RUB","target_currency":"USD","amount":100},` +
+		`{"source_currency":"ABC","target_currency":"USD","amount":1}]}`)
+	type mockConversionController struct {
+		res *entity.ConvertBatchResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		failureBody              bool
+		mockConversionController *mockConversionController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: &entity.ConvertBatchResponse{
+					Results: []entity.ConvertBatchResult{
+						{Index: 0, Amount: utils.ToPointer(decimal.RequireFromString("1.22"))},
+						{Index: 1, Error: "currency ABC not supported"},
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"results":[{"index":0,"amount":"1.22"},{"index":1,"error":"currency ABC not supported"}]}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   body,
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "no items",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"items":[]}`),
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err no items provided\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, "/convert/batch", bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, "/convert/batch", errReader(0))
+			}
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if req, err := mapper.BodyToConvertBatchRequest(tt.args.body); err == nil &&
+				tt.mockConversionController != nil {
+				conversionCtrlMock.
+					EXPECT().
+					ConvertBatch(httpreq.Context(), req).
+					Return(tt.mockConversionController.res, tt.mockConversionController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.ConvertBatch)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ConvertMulti(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := []byte(`{"country":"russia","source_currency":"USD","target_currencies":["EUR","JPY"],"amount":100}`)
+	type mockConversionController struct {
+		res *entity.ConvertMultiResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		failureBody              bool
+		mockConversionController *mockConversionController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: &entity.ConvertMultiResponse{
+					Date:    "2023-04-19",
+					Amounts: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("91.26")},
+					Rates:   map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9126")},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"date":"2023-04-19","amounts":{"EUR":"91.26"},"rates":{"EUR":"0.9126"}}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   body,
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "no currencies",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"source_currency":"USD","amount":100}`),
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err no target currencies provided\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, "/convert/multi", bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, "/convert/multi", errReader(0))
+			}
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if req, err := mapper.BodyToConvertMultiRequest(tt.args.body); err == nil &&
+				tt.mockConversionController != nil {
+				conversionCtrlMock.
+					EXPECT().
+					ConvertMulti(httpreq.Context(), req).
+					Return(tt.mockConversionController.res, tt.mockConversionController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.ConvertMulti)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ConvertCompare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := []byte(`{"source_currency":"USD","target_currency":"EUR","amount":100}`)
+	type mockConversionController struct {
+		res *entity.ConvertCompareResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		failureBody              bool
+		mockConversionController *mockConversionController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: &entity.ConvertCompareResponse{
+					Results: []entity.ConvertCompareResult{
+						{
+							Country: "russia",
+							Date:    "2023-04-19",
+							Amount:  decimal.RequireFromString("91.26"),
+							Rate:    decimal.RequireFromString("0.9126"),
+						},
+					},
+					Spread: &entity.ConversionSpread{
+						Min:    decimal.RequireFromString("91.26"),
+						Max:    decimal.RequireFromString("91.26"),
+						Median: decimal.RequireFromString("91.26"),
+					},
+					UnavailableBanks: []string{"thailand"},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"results":[{"country":"russia","date":"2023-04-19","amount":"91.26","rate":"0.9126"}],` +
+				`"spread":{"min":"91.26","max":"91.26","median":"91.26"},"unavailable_banks":["thailand"]}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   body,
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "too many decimal places",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"source_currency":"JPY","target_currency":"EUR","amount":"100.5"}`),
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err amount 100.5 has more than 0 decimal places allowed for JPY\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, "/convert/compare", bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, "/convert/compare", errReader(0))
+			}
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if req, err := mapper.BodyToConvertCompareRequest(tt.args.body); err == nil &&
+				tt.mockConversionController != nil {
+				conversionCtrlMock.
+					EXPECT().
+					Compare(httpreq.Context(), req).
+					Return(tt.mockConversionController.res, tt.mockConversionController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.ConvertCompare)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_GetRateMatrix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := []byte(`{"country":"russia","currencies":["USD","EUR"]}`)
+	type mockConversionController struct {
+		res *entity.GetRateMatrixResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		failureBody              bool
+		mockConversionController *mockConversionController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: &entity.GetRateMatrixResponse{
+					Date:       "2023-04-19",
+					Currencies: []string{"USD"},
+					Rates: map[string]map[string]decimal.Decimal{
+						"USD": {"USD": decimal.RequireFromString("1")},
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"date":"2023-04-19","currencies":["USD"],"rates":{"USD":{"USD":"1"}}}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   body,
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "no currencies",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"russia"}`),
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err no currencies provided\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   body,
+			},
+			mockConversionController: &mockConversionController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, "/rates/matrix", bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, "/rates/matrix", errReader(0))
+			}
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if req, err := mapper.BodyToGetRateMatrixRequest(tt.args.body); err == nil &&
+				tt.mockConversionController != nil {
+				conversionCtrlMock.
+					EXPECT().
+					GetRateMatrix(httpreq.Context(), req).
+					Return(tt.mockConversionController.res, tt.mockConversionController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.GetRateMatrix)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_GetTimeSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := []byte(`{"country":"russia","base_currency":"USD","target_currency":"EUR",` +
+		`"start_date":"2023-04-18","end_date":"2023-04-19"}`)
+	type mockConversionController struct {
+		res *entity.GetTimeSeriesResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+		url    string
+	}
+	tests := []struct {
+		name                     string
+		args                     args
+		failureBody              bool
+		mockConversionController *mockConversionController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   body,
+				url:    "/rates/timeseries",
+			},
+			mockConversionController: &mockConversionController{
+				res: &entity.GetTimeSeriesResponse{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					Rates: []entity.TimeSeriesPoint{
+						{Date: "2023-04-18", Rate: decimal.RequireFromString("0.9113")},
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"base_currency":"USD","target_currency":"EUR","rates":[{"date":"2023-04-18","rate":"0.9113"}]}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   body,
+				url:    "/rates/timeseries",
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+				url:    "/rates/timeseries",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "failed to convert body to the internal entity",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"srgsrgs_`),
+				url:    "/rates/timeseries",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err failed to unmarshal: unexpected end of JSON input\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   body,
+				url:    "/rates/timeseries",
+			},
+			mockConversionController: &mockConversionController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, tt.args.url, bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, tt.args.url, errReader(0))
+			}
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if req, err := mapper.BodyToGetTimeSeriesRequest(tt.args.body); err == nil &&
+				tt.mockConversionController != nil {
+				conversionCtrlMock.
+					EXPECT().
+					GetTimeSeries(httpreq.Context(), req).
+					Return(tt.mockConversionController.res, tt.mockConversionController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.GetTimeSeries)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ListBanks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type mockRepositoryController struct {
+		res *entity.ListBanksResponse
+		err error
+	}
+	tests := []struct {
+		name                     string
+		method                   string
+		mockRepositoryController *mockRepositoryController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name:   "Happy path",
+			method: "GET",
+			mockRepositoryController: &mockRepositoryController{
+				res: &entity.ListBanksResponse{
+					Banks: []entity.Bank{
+						{
+							Country:      "russia",
+							Name:         "Central Bank of the Russian Federation",
+							BaseCurrency: "RUB",
+							TimeZone:     "Europe/Moscow",
+							Historical:   true,
+						},
+					},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"banks":[{"country":"russia","name":"Central Bank of the Russian Federation",` +
+				`"base_currency":"RUB","time_zone":"Europe/Moscow","historical":true,"time_series":false}]}`,
+		},
+		{
+			name:               "wrong request method",
+			method:             "POST",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name:   "controller fails",
+			method: "GET",
+			mockRepositoryController: &mockRepositoryController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.method, "/banks", nil)
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if tt.mockRepositoryController != nil {
+				repositoryCtrlMock.
+					EXPECT().
+					ListBanks(httpreq.Context()).
+					Return(tt.mockRepositoryController.res, tt.mockRepositoryController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.ListBanks)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ListCurrencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type mockRepositoryController struct {
+		res *entity.ListCurrenciesResponse
+		err error
+	}
+	tests := []struct {
+		name                     string
+		method                   string
+		mockRepositoryController *mockRepositoryController
+		expectedStatusCode       int
+		expectedResponse         string
+	}{
+		{
+			name:   "Happy path",
+			method: "GET",
+			mockRepositoryController: &mockRepositoryController{
+				res: &entity.ListCurrenciesResponse{
+					Currencies: []entity.Currency{
+						{Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2, Banks: []string{"russia"}},
+					},
+					UnavailableBanks: []string{"thailand"},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"currencies":[{"code":"USD","numeric_code":"840","name":"US Dollar","name_ru":"Доллар США",` +
+				`"nominal":1,"minor_units":2,"banks":["russia"]}],"unavailable_banks":["thailand"]}`,
+		},
+		{
+			name:               "wrong request method",
+			method:             "POST",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name:   "controller fails",
+			method: "GET",
+			mockRepositoryController: &mockRepositoryController{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   "failed to process the request, err some error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.method, "/currencies", nil)
+
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			conversionCtrlMock := conversionmock.NewMockController(ctrl)
+			if tt.mockRepositoryController != nil {
+				repositoryCtrlMock.
+					EXPECT().
+					ListCurrencies(httpreq.Context()).
+					Return(tt.mockRepositoryController.res, tt.mockRepositoryController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionCtrlMock,
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.ListCurrencies)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_GetMetals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type mockCBRepositoryController struct {
+		res *entity.GetMetalsResponse
+		err error
+	}
+	type args struct {
+		method string
+		body   []byte
+		url    string
+	}
+	tests := []struct {
+		name                       string
+		args                       args
+		failureBody                bool
+		mockCBRepositoryController *mockCBRepositoryController
+		expectedStatusCode         int
+		expectedResponse           string
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"russia"}`),
+				url:    "/metals",
+			},
+			mockCBRepositoryController: &mockCBRepositoryController{
+				res: &entity.GetMetalsResponse{
+					Date: "2023-04-19",
+					Prices: map[string]entity.MetalPrice{
+						"XAU": {
+							Metal:    "XAU",
+							Name:     "gold",
+							Currency: "RUB",
+							Buy:      decimal.RequireFromString("5061.8"),
+							Sell:     decimal.RequireFromString("5061.8"),
+						},
+					},
+				},
+				err: nil,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"date":"2023-04-19","prices":{"XAU":{"metal":"XAU","name":"gold","currency":"RUB",` +
+				`"buy":"5061.8","sell":"5061.8"}}}`,
+		},
+		{
+			name: "wrong request method",
+			args: args{
+				method: "GET",
+				body:   []byte(`{"country":"russia"}`),
+				url:    "/metals",
+			},
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedResponse:   "method not allowed\n",
+		},
+		{
+			name: "failed to read body",
+			args: args{
+				method: "POST",
+				url:    "/metals",
+			},
+			failureBody:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "unable to read the body\n",
+		},
+		{
+			name: "failed to convert body to the internal entity",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"srgsrgs_`),
+				url:    "/metals",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "bad request, err failed to unmarshal: unexpected end of JSON input\n",
+		},
+		{
+			name: "controller fails",
+			args: args{
+				method: "POST",
+				body:   []byte(`{"country":"thailand"}`),
+				url:    "/metals",
+			},
+			mockCBRepositoryController: &mockCBRepositoryController{
+				res: nil,
+				err: errors.New("metal prices are not supported for country thailand"),
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedResponse:   "failed to process the request, err metal prices are not supported for country thailand\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpreq, _ := http.NewRequest(tt.args.method, tt.args.url, bytes.NewReader(tt.args.body))
+			if tt.failureBody {
+				httpreq, _ = http.NewRequest(tt.args.method, tt.args.url, errReader(0))
+			}
+			repositoryCtrlMock := cb_repositorymock.NewMockController(ctrl)
+			if req, err := mapper.BodyToGetMetalsRequest(tt.args.body); err == nil &&
+				tt.mockCBRepositoryController != nil {
+				repositoryCtrlMock.
+					EXPECT().
+					GetMetals(httpreq.Context(), req).
+					Return(tt.mockCBRepositoryController.res, tt.mockCBRepositoryController.err)
+			}
+			h := &handler{
+				logger:         zap.NewNop(),
+				repositoryCtrl: repositoryCtrlMock,
+				conversionCtrl: conversionmock.NewMockController(ctrl),
+			}
+			rr := httptest.NewRecorder()
+			testhandler := http.HandlerFunc(h.GetMetals)
+			testhandler.ServeHTTP(rr, httpreq)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package integration_tests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	uberconfig "go.uber.org/config"
+	"go.uber.org/fx"
+	"my_go/controller"
+	"my_go/entity"
+	"my_go/gateway"
+	"my_go/gateway/russia"
+	"my_go/gateway/thailand"
+	"my_go/handler"
+	"my_go/logger"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"my_go/repository"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// This test ensures that cache refreshes are coalesced per country:
+// slow refresh of Thailand rates doesn't block the requests for Russia rates
+// and concurrent cold requests for Russia rates trigger exactly one network call.
+func TestCacheConcurrency(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	thTZ, _ := time.LoadLocation("Asia/Bangkok")
+	ctrl := gomock.NewController(t)
+	const russiaRequests = 20
+	t.Run("slow country doesn't block others and cold requests are coalesced", func(t *testing.T) {
+		thailandStarted := make(chan struct{})
+		thailandRelease := make(chan struct{})
+		NewTestRUCBGateway := func() russia.Gateway {
+			gw := russiagatewaymock.NewMockGateway(ctrl)
+			gw.
+				EXPECT().
+				GetCBRRates(gomock.Any()).
+				Times(1).
+				DoAndReturn(func(ctx context.Context) (*entity.ExchangeRates, error) {
+					// slow enough for all the concurrent requests to arrive while the refresh is in progress
+					time.Sleep(200 * time.Millisecond)
+					return &entity.ExchangeRates{
+						Country:    "russia",
+						DateLoaded: time.Now().In(ruTZ).Format(entity.DateLayout),
+						TimeZone:   ruTZ,
+						Rates:      map[string]entity.Rate{},
+					}, nil
+				})
+			return gw
+		}
+		NewTestTHCBGateway := func() thailand.Gateway {
+			gw := thailandgatewaymock.NewMockGateway(ctrl)
+			gw.
+				EXPECT().
+				GetCBRRates(gomock.Any()).
+				Times(1).
+				DoAndReturn(func(ctx context.Context) (*entity.ExchangeRates, error) {
+					close(thailandStarted)
+					<-thailandRelease
+					return &entity.ExchangeRates{
+						Country:    "thailand",
+						DateLoaded: time.Now().In(thTZ).Format(entity.DateLayout),
+						TimeZone:   thTZ,
+						Rates:      map[string]entity.Rate{},
+					}, nil
+				})
+			return gw
+		}
+		NewMux := func(lc fx.Lifecycle) *http.ServeMux {
+			mux := http.NewServeMux()
+			server := &http.Server{
+				Addr:    "127.0.0.1:8000",
+				Handler: mux,
+			}
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					ln, err := net.Listen("tcp", server.Addr)
+					if err != nil {
+						return err
+					}
+					go server.Serve(ln)
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					return server.Shutdown(ctx)
+				},
+			})
+			return mux
+		}
+		NewConfig := func() uberconfig.Provider {
+			configOption := uberconfig.Source(strings.NewReader(`{"defaults":{"default_cb": "russia"},"storage":{"backend":"memory"}}`))
+			provider, _ := uberconfig.NewYAML(configOption)
+			return provider
+		}
+		Register := func(mux *http.ServeMux, h handler.Handler) {
+			mux.HandleFunc("/get_exchange_rates", h.GetCBRates)
+		}
+		app := fx.New(
+			fx.Provide(NewTestRUCBGateway),
+			fx.Provide(NewTestTHCBGateway),
+			fx.Provide(russia.Register),
+			fx.Provide(thailand.Register),
+			gateway.Module,
+			fx.Provide(NewMux),
+			fx.Provide(NewConfig),
+			handler.Module,
+			controller.Module,
+			logger.Module,
+			repository.Module,
+			fx.Invoke(Register),
+		)
+		startCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := app.Start(startCtx)
+		assert.NoError(t, err)
+
+		getRates := func(country string, timeout time.Duration) (int, error) {
+			req, _ := http.NewRequest(
+				"POST",
+				"http://localhost:8000/get_exchange_rates",
+				strings.NewReader(`{"country":"`+country+`"}`),
+			)
+			client := http.Client{
+				Timeout: timeout,
+				// connections must not outlive the test server and be reused by other tests
+				Transport: &http.Transport{DisableKeepAlives: true},
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return 0, err
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode, nil
+		}
+
+		thailandDone := make(chan struct{})
+		go func() {
+			defer close(thailandDone)
+			status, err := getRates("thailand", 10*time.Second)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, status)
+		}()
+		<-thailandStarted
+
+		// Thailand refresh is still in progress, Russia requests must not wait for it
+		wg := &sync.WaitGroup{}
+		wg.Add(russiaRequests)
+		for i := 0; i < russiaRequests; i++ {
+			go func() {
+				defer wg.Done()
+				status, err := getRates("russia", 2*time.Second)
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, status)
+			}()
+		}
+		wg.Wait()
+
+		close(thailandRelease)
+		<-thailandDone
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = app.Stop(stopCtx)
+		assert.NoError(t, err)
+	})
+}
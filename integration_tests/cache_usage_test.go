@@ -8,6 +8,7 @@ import (
 	"go.uber.org/fx"
 	"my_go/controller"
 	"my_go/entity"
+	"my_go/gateway"
 	"my_go/gateway/russia"
 	"my_go/gateway/thailand"
 	"my_go/handler"
@@ -75,7 +76,7 @@ func TestCacheUsage(t *testing.T) {
 			return mux
 		}
 		NewConfig := func() uberconfig.Provider {
-			configOption := uberconfig.Source(strings.NewReader(`{"defaults":{"default_cb": "russia"}}`))
+			configOption := uberconfig.Source(strings.NewReader(`{"defaults":{"default_cb": "russia"},"storage":{"backend":"memory"}}`))
 			provider, _ := uberconfig.NewYAML(configOption)
 			return provider
 		}
@@ -87,6 +88,9 @@ func TestCacheUsage(t *testing.T) {
 		app := fx.New(
 			fx.Provide(NewTestRUCBGateway),
 			fx.Provide(NewTestTHCBGateway),
+			fx.Provide(russia.Register),
+			fx.Provide(thailand.Register),
+			gateway.Module,
 			fx.Provide(NewMux),
 			fx.Provide(NewConfig),
 			handler.Module,
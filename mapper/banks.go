@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"my_go/entity"
+	"my_go/gateway"
+)
+
+// RegistrationsToListBanksResponse converts the registered gateways to entity.ListBanksResponse.
+// Supported features are discovered from the interfaces implemented by the gateway.
+func RegistrationsToListBanksResponse(registrations []gateway.Registration) *entity.ListBanksResponse {
+	banks := make([]entity.Bank, 0, len(registrations))
+	for _, reg := range registrations {
+		bank := entity.Bank{
+			Country:      reg.Country,
+			Name:         reg.Name,
+			BaseCurrency: reg.BaseCurrency,
+			Website:      reg.Website,
+		}
+		if reg.TimeZone != nil {
+			bank.TimeZone = reg.TimeZone.String()
+		}
+		_, bank.Historical = reg.Gateway.(gateway.HistoricalCBGateway)
+		_, bank.TimeSeries = reg.Gateway.(gateway.TimeSeriesCBGateway)
+		banks = append(banks, bank)
+	}
+	return &entity.ListBanksResponse{
+		Banks: banks,
+	}
+}
+
+// ListBanksResponseToBytes converts internal entity.ListBanksResponse to http response body
+func ListBanksResponseToBytes(response *entity.ListBanksResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
@@ -0,0 +1,93 @@
+package mapper
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	"testing"
+	"time"
+)
+
+// latestOnlyGateway is a gateway that supports neither historical rates nor time series
+type latestOnlyGateway struct{}
+
+func (latestOnlyGateway) GetCBRRates(_ context.Context) (*entity.ExchangeRates, error) {
+	return nil, nil
+}
+
+func TestRegistrationsToListBanksResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	tests := []struct {
+		name          string
+		registrations []gateway.Registration
+		want          *entity.ListBanksResponse
+	}{
+		{
+			name: "Happy path",
+			registrations: []gateway.Registration{
+				{
+					Bank: gateway.Bank{
+						Country:      "russia",
+						Name:         "Central Bank of the Russian Federation",
+						BaseCurrency: "RUB",
+						TimeZone:     ruTZ,
+						Website:      "https://www.cbr.ru",
+					},
+					Gateway: russiagatewaymock.NewMockGateway(ctrl),
+				},
+				{
+					Bank: gateway.Bank{
+						Country:      "utopia",
+						BaseCurrency: "UTO",
+					},
+					Gateway: latestOnlyGateway{},
+				},
+			},
+			want: &entity.ListBanksResponse{
+				Banks: []entity.Bank{
+					{
+						Country:      "russia",
+						Name:         "Central Bank of the Russian Federation",
+						BaseCurrency: "RUB",
+						TimeZone:     "Europe/Moscow",
+						Website:      "https://www.cbr.ru",
+						Historical:   true,
+						TimeSeries:   true,
+					},
+					{
+						Country:      "utopia",
+						BaseCurrency: "UTO",
+					},
+				},
+			},
+		},
+		{
+			name:          "no banks",
+			registrations: nil,
+			want: &entity.ListBanksResponse{
+				Banks: []entity.Bank{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RegistrationsToListBanksResponse(tt.registrations))
+		})
+	}
+}
+
+func TestListBanksResponseToBytes(t *testing.T) {
+	got, err := ListBanksResponseToBytes(&entity.ListBanksResponse{
+		Banks: []entity.Bank{{Country: "thailand", BaseCurrency: "THB", TimeSeries: true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`{"banks":[{"country":"thailand","base_currency":"THB","historical":false,"time_series":true}]}`,
+		string(got),
+	)
+}
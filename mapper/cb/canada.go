@@ -0,0 +1,118 @@
+package cb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	canadaBaseCurrency = "CAD"
+	canadaDateKey      = "d"
+	canadaSeriesPrefix = "FX"
+)
+
+// CanadaCBResponseToRates converts json response of Bank of Canada Valet API to the map where keys are
+// currency ID and values are entity.Rate. Rates of the latest date in the response are used.
+func CanadaCBResponseToRates(body []byte) (map[string]entity.Rate, error) {
+	byDate, err := CanadaCBResponseToRatesByDate(body)
+	if err != nil {
+		return nil, err
+	}
+	return byDate[canadaLatestDate(byDate)], nil
+}
+
+// CanadaCBResponseToEffectiveDate returns the latest date (in entity.DateLayout format) the rates
+// in json response of Bank of Canada Valet API are published for.
+func CanadaCBResponseToEffectiveDate(body []byte) (string, error) {
+	byDate, err := CanadaCBResponseToRatesByDate(body)
+	if err != nil {
+		return "", err
+	}
+	return canadaLatestDate(byDate), nil
+}
+
+// CanadaCBResponseToRatesByDate pivots series oriented json response of Bank of Canada Valet API to the map
+// where keys are dates and values are maps of currency ID to entity.Rate.
+// Currency is taken from the series name (e.g. FXUSDCAD is USD), series of other naming are skipped
+// along with missing or non-numeric values. Valet quotes CAD for 1 unit of the currency.
+// For the convenience of the conversion calculation the rate of CAD to CAD conversion is added for every date.
+func CanadaCBResponseToRatesByDate(body []byte) (map[string]map[string]entity.Rate, error) {
+	resp := cb_entity.CanadaCBData{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("json unmarshal failed %s", err)
+	}
+	byDate := map[string]map[string]entity.Rate{}
+	for _, o := range resp.Observations {
+		var date string
+		if err := json.Unmarshal(o[canadaDateKey], &date); err != nil {
+			return nil, fmt.Errorf("no date in the observation: %s", err)
+		}
+		if _, err := time.Parse(entity.DateLayout, date); err != nil {
+			return nil, fmt.Errorf("bad date %s of the observation", date)
+		}
+		m := map[string]entity.Rate{}
+		for series, raw := range o {
+			currency, ok := canadaSeriesCurrency(series)
+			if !ok {
+				continue
+			}
+			v := cb_entity.CanadaCBValue{}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				continue
+			}
+			value, err := money.Parse(v.V)
+			if err != nil || !value.IsPositive() {
+				continue
+			}
+			m[currency] = entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     canadaBaseCurrency,
+				TargetCurrency:   currency,
+				RateTargetToBase: value,
+			}
+		}
+		if len(m) == 0 {
+			continue
+		}
+		m[canadaBaseCurrency] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     canadaBaseCurrency,
+			TargetCurrency:   canadaBaseCurrency,
+			RateTargetToBase: decimal.NewFromInt(1),
+		}
+		byDate[date] = m
+	}
+	if len(byDate) == 0 {
+		return nil, errors.New("no observations in the response")
+	}
+	return byDate, nil
+}
+
+// canadaSeriesCurrency extracts the currency from the series name of FX{currency}CAD format
+func canadaSeriesCurrency(series string) (string, bool) {
+	if !strings.HasPrefix(series, canadaSeriesPrefix) || !strings.HasSuffix(series, canadaBaseCurrency) {
+		return "", false
+	}
+	currency := strings.TrimSuffix(strings.TrimPrefix(series, canadaSeriesPrefix), canadaBaseCurrency)
+	if len(currency) != 3 {
+		return "", false
+	}
+	return currency, true
+}
+
+func canadaLatestDate(byDate map[string]map[string]entity.Rate) string {
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates[len(dates)-1]
+}
@@ -0,0 +1,110 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+var canadaObservations = []byte(`{"groupDetail":{"label":"Daily exchange rates"},` +
+	`"seriesDetail":{"FXUSDCAD":{"label":"USD/CAD"},"FXEURCAD":{"label":"EUR/CAD"}},` +
+	`"observations":[` +
+	`{"d":"2023-04-18","FXUSDCAD":{"v":"1.3383"},"FXEURCAD":{"v":"1.4681"}},` +
+	`{"d":"2023-04-19","FXUSDCAD":{"v":"1.3425"},"FXEURCAD":{"v":"1.4712"}}]}`)
+
+func TestCanadaCBResponseToRatesByDate(t *testing.T) {
+	cad := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "CAD",
+		TargetCurrency:   "CAD",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	rate := func(currency string, value string) entity.Rate {
+		return entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     "CAD",
+			TargetCurrency:   currency,
+			RateTargetToBase: decimal.RequireFromString(value),
+		}
+	}
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			body: canadaObservations,
+			want: map[string]map[string]entity.Rate{
+				"2023-04-18": {"CAD": cad, "USD": rate("USD", "1.3383"), "EUR": rate("EUR", "1.4681")},
+				"2023-04-19": {"CAD": cad, "USD": rate("USD", "1.3425"), "EUR": rate("EUR", "1.4712")},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, unknown series, missing values and empty observations skipped",
+			body: []byte(`{"observations":[` +
+				`{"d":"2023-04-18","FXUSDCAD":{"v":""},"FXEURCAD":{"v":"1.4681"},"FXCORRA":{"v":"4.5"},"FXUSDEURCAD":{"v":"1"}},` +
+				`{"d":"2023-04-19","FXUSDCAD":"n/a"},` +
+				`{"d":"2023-04-20"}]}`),
+			want: map[string]map[string]entity.Rate{
+				"2023-04-18": {"CAD": cad, "EUR": rate("EUR", "1.4681")},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Malformed json",
+			body:      []byte(`{"observations":`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "No observations",
+			body:      []byte(`{"observations":[]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Observation without date",
+			body:      []byte(`{"observations":[{"FXUSDCAD":{"v":"1.3383"}}]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Bad date of the observation",
+			body:      []byte(`{"observations":[{"d":"18/04/2023","FXUSDCAD":{"v":"1.3383"}}]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanadaCBResponseToRatesByDate(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCanadaCBResponseToRates(t *testing.T) {
+	got, err := CanadaCBResponseToRates(canadaObservations)
+	assert.NoError(t, err)
+	assert.Equal(t, decimal.RequireFromString("1.3425"), got["USD"].RateTargetToBase)
+	assert.Len(t, got, 3)
+
+	got, err = CanadaCBResponseToRates([]byte(`{"observations":[]}`))
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestCanadaCBResponseToEffectiveDate(t *testing.T) {
+	got, err := CanadaCBResponseToEffectiveDate(canadaObservations)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-04-19", got)
+
+	got, err = CanadaCBResponseToEffectiveDate([]byte(`{`))
+	assert.Error(t, err)
+	assert.Equal(t, "", got)
+}
@@ -0,0 +1,111 @@
+package cb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	czechBaseCurrency = "CZK"
+	czechDateLayout   = "02.01.2006"
+	czechColumns      = 5
+)
+
+// CzechCBResponseToRates converts the text response of Czech National Bank to the map where keys are
+// currency ID and values are entity.Rate. Lines that can't be parsed (e.g. the header) are skipped.
+// For the convenience of the conversion calculation the rate of CZK to CZK conversion is added.
+func CzechCBResponseToRates(body []byte) (map[string]entity.Rate, error) {
+	resp, err := parseCzechCBResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Rate{}
+	for _, r := range resp.Rates {
+		value, err := money.Parse(r.Rate)
+		if err != nil || value.IsZero() || r.Amount <= 0 {
+			continue
+		}
+		m[r.Code] = entity.Rate{
+			Nominal:          r.Amount,
+			BaseCurrency:     czechBaseCurrency,
+			TargetCurrency:   r.Code,
+			RateTargetToBase: value,
+		}
+	}
+	m[czechBaseCurrency] = entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     czechBaseCurrency,
+		TargetCurrency:   czechBaseCurrency,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, nil
+}
+
+// CzechCBResponseToEffectiveDate returns the date (in entity.DateLayout format) the rates in the text
+// response of Czech National Bank are set for.
+func CzechCBResponseToEffectiveDate(body []byte) (string, error) {
+	resp, err := parseCzechCBResponse(body)
+	if err != nil {
+		return "", err
+	}
+	d, err := time.Parse(czechDateLayout, resp.Date)
+	if err != nil {
+		return "", fmt.Errorf("bad date %s in the header line: %s", resp.Date, err)
+	}
+	return d.Format(entity.DateLayout), nil
+}
+
+// parseCzechCBResponse splits the text response into the header and the pipe-separated lines.
+// Lines with unexpected number of columns or non-numeric amount are skipped, that covers the table header.
+func parseCzechCBResponse(body []byte) (*cb_entity.CzechCBData, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read the response: %s", err) // unreachable in tests
+		}
+		return nil, errors.New("empty response")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) == 0 {
+		return nil, errors.New("no date in the header line")
+	}
+	resp := &cb_entity.CzechCBData{
+		Date: header[0],
+	}
+	if len(header) > 1 {
+		resp.Number = strings.TrimPrefix(header[1], "#")
+	}
+	for scanner.Scan() {
+		columns := strings.Split(strings.TrimSpace(scanner.Text()), "|")
+		if len(columns) != czechColumns {
+			continue
+		}
+		amount, err := strconv.Atoi(strings.TrimSpace(columns[2]))
+		if err != nil {
+			continue
+		}
+		resp.Rates = append(resp.Rates, cb_entity.CzechCBRate{
+			Country:  strings.TrimSpace(columns[0]),
+			Currency: strings.TrimSpace(columns[1]),
+			Amount:   amount,
+			Code:     strings.TrimSpace(columns[3]),
+			Rate:     strings.TrimSpace(columns[4]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the response: %s", err) // unreachable in tests
+	}
+	if len(resp.Rates) == 0 {
+		return nil, errors.New("no rates in the response")
+	}
+	return resp, nil
+}
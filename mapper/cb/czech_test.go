@@ -0,0 +1,148 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+var czechDailyText = []byte("19.04.2023 #76\n" +
+	"země|měna|množství|kód|kurz\n" +
+	"Austrálie|dolar|1|AUD|14,350\n" +
+	"EMU|euro|1|EUR|23,425\n" +
+	"Indonesie|rupie|1000|IDR|1,440\n" +
+	"Japonsko|jen|100|JPY|15,930\n")
+
+func TestCzechCBResponseToRates(t *testing.T) {
+	czk := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "CZK",
+		TargetCurrency:   "CZK",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			body: czechDailyText,
+			want: map[string]entity.Rate{
+				"CZK": czk,
+				"AUD": {
+					Nominal:          1,
+					BaseCurrency:     "CZK",
+					TargetCurrency:   "AUD",
+					RateTargetToBase: decimal.RequireFromString("14.35"),
+				},
+				"EUR": {
+					Nominal:          1,
+					BaseCurrency:     "CZK",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("23.425"),
+				},
+				"IDR": {
+					Nominal:          1000,
+					BaseCurrency:     "CZK",
+					TargetCurrency:   "IDR",
+					RateTargetToBase: decimal.RequireFromString("1.44"),
+				},
+				"JPY": {
+					Nominal:          100,
+					BaseCurrency:     "CZK",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("15.93"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, BOM, CRLF and broken lines skipped",
+			body: []byte("\xef\xbb\xbf19.04.2023 #76\r\n" +
+				"země|měna|množství|kód|kurz\r\n" +
+				"Austrálie|dolar|1|AUD|14,350\r\n" +
+				"EMU|euro|1|EUR\r\n" +
+				"Japonsko|jen|sto|JPY|15,930\r\n" +
+				"Indonesie|rupie|1000|IDR|n/a\r\n"),
+			want: map[string]entity.Rate{
+				"CZK": czk,
+				"AUD": {
+					Nominal:          1,
+					BaseCurrency:     "CZK",
+					TargetCurrency:   "AUD",
+					RateTargetToBase: decimal.RequireFromString("14.35"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "header only",
+			body:      []byte("19.04.2023 #76\nzemě|měna|množství|kód|kurz\n"),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "empty response",
+			body:      []byte(""),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "no date in the header",
+			body:      []byte("\nAustrálie|dolar|1|AUD|14,350\n"),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CzechCBResponseToRates(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCzechCBResponseToEffectiveDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			body:      czechDailyText,
+			want:      "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, no fixing number",
+			body:      []byte("18.04.2023\nAustrálie|dolar|1|AUD|14,350\n"),
+			want:      "2023-04-18",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			body:      []byte("2023-04-19 #76\nAustrálie|dolar|1|AUD|14,350\n"),
+			want:      "",
+			assertion: assert.Error,
+		},
+		{
+			name:      "no rates",
+			body:      []byte("19.04.2023 #76\n"),
+			want:      "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CzechCBResponseToEffectiveDate(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package cb
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+)
+
+const ecbBaseCurrency = "EUR"
+
+// ECBResponseToRates converts eurofxref xml response from European Central Bank to the map where keys are
+// currency ID and values are entity.Rate. The latest day of the feed is used.
+// ECB quotes the amount of target currency for 1 EUR, so the rates are marked as Inverse.
+// For the convenience of the conversion calculation the rate of EUR to EUR conversion is added.
+func ECBResponseToRates(body []byte) (map[string]entity.Rate, error) {
+	day, err := ecbLatestDay(body)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Rate{}
+	for _, r := range day.Rates {
+		value, err := money.Parse(r.Rate)
+		if err != nil || value.IsZero() {
+			continue
+		}
+		m[r.Currency] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     ecbBaseCurrency,
+			TargetCurrency:   r.Currency,
+			RateTargetToBase: value,
+			Inverse:          true,
+		}
+	}
+	m[ecbBaseCurrency] = entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     ecbBaseCurrency,
+		TargetCurrency:   ecbBaseCurrency,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, nil
+}
+
+// ECBResponseToEffectiveDate returns the date (in entity.DateLayout format) the rates of the latest day
+// in eurofxref xml response from European Central Bank are set for.
+func ECBResponseToEffectiveDate(body []byte) (string, error) {
+	day, err := ecbLatestDay(body)
+	if err != nil {
+		return "", err
+	}
+	return day.Time, nil
+}
+
+func ecbLatestDay(body []byte) (*cb_entity.ECBDay, error) {
+	resp := cb_entity.ECBData{}
+	err := xml.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("xml unmarshal failed %s", err)
+	}
+	if len(resp.Days) == 0 {
+		return nil, errors.New("no rates in the response")
+	}
+	latest := &resp.Days[0]
+	for i := range resp.Days {
+		if resp.Days[i].Time > latest.Time {
+			latest = &resp.Days[i]
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,151 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+var ecbDailyXML = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2023-04-19'>
+			<Cube currency='USD' rate='1.0958'/>
+			<Cube currency='JPY' rate='147.45'/>
+			<Cube currency='HUF' rate='373.10'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+
+var ecbHistoricalXML = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2023-04-18">
+			<Cube currency="USD" rate="1.0945"/>
+		</Cube>
+		<Cube time="2023-04-19">
+			<Cube currency="USD" rate="1.0958"/>
+			<Cube currency="JPY" rate="N/A"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+
+func TestECBResponseToRates(t *testing.T) {
+	eur := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "EUR",
+		TargetCurrency:   "EUR",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			body: ecbDailyXML,
+			want: map[string]entity.Rate{
+				"EUR": eur,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("1.0958"),
+					Inverse:          true,
+				},
+				"JPY": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("147.45"),
+					Inverse:          true,
+				},
+				"HUF": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "HUF",
+					RateTargetToBase: decimal.RequireFromString("373.1"),
+					Inverse:          true,
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, latest day is used, bad rate skipped",
+			body: ecbHistoricalXML,
+			want: map[string]entity.Rate{
+				"EUR": eur,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("1.0958"),
+					Inverse:          true,
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no rates",
+			body:      []byte(`<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"><Cube></Cube></gesmes:Envelope>`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<gesmes:Envelope`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ECBResponseToRates(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestECBResponseToEffectiveDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			body:      ecbDailyXML,
+			want:      "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, several days",
+			body:      ecbHistoricalXML,
+			want:      "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<gesmes:Envelope`),
+			want:      "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ECBResponseToEffectiveDate(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cb
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+	"time"
+)
+
+const hungaryBaseCurrency = "HUF"
+
+// HungaryCBResponseToRates converts MNBCurrentExchangeRates document returned by GetCurrentExchangeRates operation
+// of Magyar Nemzeti Bank to the map where keys are currency ID and values are entity.Rate.
+// For the convenience of the conversion calculation the rate of HUF to HUF conversion is added.
+func HungaryCBResponseToRates(result []byte) (map[string]entity.Rate, error) {
+	resp, err := parseHungaryCBResponse(result)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Rate{}
+	for _, r := range resp.Day.Rates {
+		value, err := money.Parse(r.Value)
+		if err != nil || !value.IsPositive() || r.Unit <= 0 {
+			continue
+		}
+		m[r.Currency] = entity.Rate{
+			Nominal:          r.Unit,
+			BaseCurrency:     hungaryBaseCurrency,
+			TargetCurrency:   r.Currency,
+			RateTargetToBase: value,
+		}
+	}
+	if len(m) == 0 {
+		return nil, errors.New("no rates in the response")
+	}
+	m[hungaryBaseCurrency] = entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     hungaryBaseCurrency,
+		TargetCurrency:   hungaryBaseCurrency,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, nil
+}
+
+// HungaryCBResponseToEffectiveDate returns the date (in entity.DateLayout format) the rates
+// in MNBCurrentExchangeRates document are set for.
+func HungaryCBResponseToEffectiveDate(result []byte) (string, error) {
+	resp, err := parseHungaryCBResponse(result)
+	if err != nil {
+		return "", err
+	}
+	if _, err := time.Parse(entity.DateLayout, resp.Day.Date); err != nil {
+		return "", fmt.Errorf("bad date %s of the day: %s", resp.Day.Date, err)
+	}
+	return resp.Day.Date, nil
+}
+
+func parseHungaryCBResponse(result []byte) (*cb_entity.HungaryCBData, error) {
+	resp := &cb_entity.HungaryCBData{}
+	if err := xml.Unmarshal(result, resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response: %s", err)
+	}
+	return resp, nil
+}
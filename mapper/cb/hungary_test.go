@@ -0,0 +1,128 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+var hungaryCurrentRates = []byte(`<MNBCurrentExchangeRates><Day date="2023-04-19">` +
+	`<Rate unit="1" curr="EUR">375,53</Rate>` +
+	`<Rate unit="100" curr="JPY">254,12</Rate>` +
+	`<Rate unit="1" curr="USD">342,71</Rate>` +
+	`</Day></MNBCurrentExchangeRates>`)
+
+func TestHungaryCBResponseToRates(t *testing.T) {
+	huf := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "HUF",
+		TargetCurrency:   "HUF",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name      string
+		result    []byte
+		want      map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "Happy path",
+			result: hungaryCurrentRates,
+			want: map[string]entity.Rate{
+				"HUF": huf,
+				"EUR": {
+					Nominal:          1,
+					BaseCurrency:     "HUF",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("375.53"),
+				},
+				"JPY": {
+					Nominal:          100,
+					BaseCurrency:     "HUF",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("254.12"),
+				},
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "HUF",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("342.71"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, broken rates skipped",
+			result: []byte(`<MNBCurrentExchangeRates><Day date="2023-04-19">` +
+				`<Rate unit="1" curr="EUR">375,53</Rate>` +
+				`<Rate unit="0" curr="JPY">254,12</Rate>` +
+				`<Rate unit="1" curr="USD"></Rate>` +
+				`</Day></MNBCurrentExchangeRates>`),
+			want: map[string]entity.Rate{
+				"HUF": huf,
+				"EUR": {
+					Nominal:          1,
+					BaseCurrency:     "HUF",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("375.53"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "No rates",
+			result:    []byte(`<MNBCurrentExchangeRates><Day date="2023-04-19"/></MNBCurrentExchangeRates>`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Malformed xml",
+			result:    []byte(`<MNBCurrentExchangeRates><Day`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HungaryCBResponseToRates(tt.result)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHungaryCBResponseToEffectiveDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    []byte
+		want      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			result:    hungaryCurrentRates,
+			want:      "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Bad date",
+			result:    []byte(`<MNBCurrentExchangeRates><Day date="2023.04.19"/></MNBCurrentExchangeRates>`),
+			want:      "",
+			assertion: assert.Error,
+		},
+		{
+			name:      "Malformed xml",
+			result:    []byte(`<MNBCurrentExchangeRates><Day`),
+			want:      "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HungaryCBResponseToEffectiveDate(tt.result)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
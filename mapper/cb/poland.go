@@ -0,0 +1,80 @@
+package cb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+	"time"
+)
+
+const polandBaseCurrency = "PLN"
+
+// PolandCBResponseToRates converts the JSON response of National Bank of Poland to the map where keys are
+// currency ID and values are entity.Rate. Table A quotes the mid rates for 1 unit of the currency.
+// If the response contains several tables the latest one is used.
+// For the convenience of the conversion calculation the rate of PLN to PLN conversion is added.
+func PolandCBResponseToRates(body []byte) (map[string]entity.Rate, error) {
+	table, err := latestPolandCBTable(body)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Rate{}
+	for _, r := range table.Rates {
+		value, err := money.Parse(r.Mid.String())
+		if err != nil || !value.IsPositive() || r.Code == "" {
+			continue
+		}
+		m[r.Code] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     polandBaseCurrency,
+			TargetCurrency:   r.Code,
+			RateTargetToBase: value,
+		}
+	}
+	if len(m) == 0 {
+		return nil, errors.New("no rates in the response")
+	}
+	m[polandBaseCurrency] = entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     polandBaseCurrency,
+		TargetCurrency:   polandBaseCurrency,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, nil
+}
+
+// PolandCBResponseToTable returns the effective date (in entity.DateLayout format) and the number
+// of the latest table in the JSON response of National Bank of Poland.
+func PolandCBResponseToTable(body []byte) (effectiveDate string, number string, err error) {
+	table, err := latestPolandCBTable(body)
+	if err != nil {
+		return "", "", err
+	}
+	return table.EffectiveDate, table.No, nil
+}
+
+// latestPolandCBTable unmarshals the response and picks the table with the latest effective date,
+// tables with malformed date are rejected.
+func latestPolandCBTable(body []byte) (*cb_entity.PolandCBTable, error) {
+	var tables []cb_entity.PolandCBTable
+	if err := json.Unmarshal(body, &tables); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response: %s", err)
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("no tables in the response")
+	}
+	var latest *cb_entity.PolandCBTable
+	for i := range tables {
+		if _, err := time.Parse(entity.DateLayout, tables[i].EffectiveDate); err != nil {
+			return nil, fmt.Errorf("bad effective date %s of the table %s", tables[i].EffectiveDate, tables[i].No)
+		}
+		if latest == nil || tables[i].EffectiveDate > latest.EffectiveDate {
+			latest = &tables[i]
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,135 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+var polandTablesJSON = []byte(`[{"table":"A","no":"075/A/NBP/2023","effectiveDate":"2023-04-19","rates":[` +
+	`{"currency":"dolar amerykański","code":"USD","mid":4.2006},` +
+	`{"currency":"euro","code":"EUR","mid":4.6059},` +
+	`{"currency":"jen (Japonia)","code":"JPY","mid":0.031288}]}]`)
+
+func TestPolandCBResponseToRates(t *testing.T) {
+	pln := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "PLN",
+		TargetCurrency:   "PLN",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			body: polandTablesJSON,
+			want: map[string]entity.Rate{
+				"PLN": pln,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "PLN",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("4.2006"),
+				},
+				"EUR": {
+					Nominal:          1,
+					BaseCurrency:     "PLN",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("4.6059"),
+				},
+				"JPY": {
+					Nominal:          1,
+					BaseCurrency:     "PLN",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("0.031288"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, latest table used, broken rates skipped",
+			body: []byte(`[` +
+				`{"table":"A","no":"075/A/NBP/2023","effectiveDate":"2023-04-19","rates":[{"code":"USD","mid":4.2006}]},` +
+				`{"table":"A","no":"074/A/NBP/2023","effectiveDate":"2023-04-18","rates":[{"code":"USD","mid":4.1979}]},` +
+				`{"table":"A","no":"076/A/NBP/2023","effectiveDate":"2023-04-20","rates":[` +
+				`{"code":"USD","mid":4.2040},{"code":"EUR","mid":0},{"code":"","mid":1.5}]}]`),
+			want: map[string]entity.Rate{
+				"PLN": pln,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "PLN",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("4.204"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Malformed json",
+			body:      []byte(`{"table":`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "No tables",
+			body:      []byte(`[]`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "No rates",
+			body:      []byte(`[{"table":"A","no":"075/A/NBP/2023","effectiveDate":"2023-04-19","rates":[]}]`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Bad effective date",
+			body:      []byte(`[{"table":"A","no":"075/A/NBP/2023","effectiveDate":"19.04.2023","rates":[{"code":"USD","mid":4.2006}]}]`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PolandCBResponseToRates(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPolandCBResponseToTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       []byte
+		wantDate   string
+		wantNumber string
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Happy path",
+			body:       polandTablesJSON,
+			wantDate:   "2023-04-19",
+			wantNumber: "075/A/NBP/2023",
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Malformed json",
+			body:      []byte(`[{`),
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDate, gotNumber, err := PolandCBResponseToTable(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.wantDate, gotDate)
+			assert.Equal(t, tt.wantNumber, gotNumber)
+		})
+	}
+}
@@ -3,29 +3,31 @@ package cb
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"golang.org/x/net/html/charset"
 	"my_go/entity"
 	cb_entity "my_go/entity/cb"
-	"strconv"
+	"my_go/money"
 	"strings"
+	"time"
 )
 
+const russiaCBRDateLayout = "02.01.2006"
+
 // RussiaCBRResponseToRates converts the response from Central Bank of Russia to
 // map where keys are currency ID and values are entity.Rate.
 // For the convenience of the conversion calculation the rate of RUR to RUR conversion is added.
 func RussiaCBRResponseToRates(body []byte) (map[string]entity.Rate, error) {
-	reader := bytes.NewReader(body)
-	parser := xml.NewDecoder(reader)
-	parser.CharsetReader = charset.NewReaderLabel
 	resp := cb_entity.RussiaCBRData{}
-	err := parser.Decode(&resp)
+	err := decodeRussiaCBRXML(body, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("xml unmarshal failed %s", err)
+		return nil, err
 	}
 	m := map[string]entity.Rate{}
 	for _, r := range resp.Rates {
-		ratio, err := strconv.ParseFloat(strings.Replace(r.Value, ",", ".", 1), 64)
+		ratio, err := money.Parse(r.Value)
 		if err != nil {
 			// TODO add error log here
 			continue
@@ -41,7 +43,176 @@ func RussiaCBRResponseToRates(body []byte) (map[string]entity.Rate, error) {
 		Nominal:          1,
 		BaseCurrency:     "RUB",
 		TargetCurrency:   "RUB",
-		RateTargetToBase: 1,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, nil
+}
+
+// RussiaCBRDynamicResponseToRates converts the response from Central Bank of Russia dynamic API to
+// map where keys are dates (in entity.DateLayout format) and values are entity.Rate of the provided currency.
+func RussiaCBRDynamicResponseToRates(body []byte, currency string) (map[string]entity.Rate, error) {
+	resp := cb_entity.RussiaCBRDynamicData{}
+	err := decodeRussiaCBRXML(body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Rate{}
+	for _, r := range resp.Records {
+		date, err := time.Parse(russiaCBRDateLayout, r.Date)
+		if err != nil {
+			continue
+		}
+		ratio, err := money.Parse(r.Value)
+		if err != nil {
+			continue
+		}
+		m[date.Format(entity.DateLayout)] = entity.Rate{
+			Nominal:          r.Nominal,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   currency,
+			RateTargetToBase: ratio,
+		}
+	}
+	return m, nil
+}
+
+// RussiaCBRResponseToPublicationDate returns the official date (in entity.DateLayout format) the rates in the
+// response from Central Bank of Russia are set for. Central Bank of Russia doesn't provide publication timestamp,
+// so zero time is returned for it. Empty date is returned if the response has no date.
+func RussiaCBRResponseToPublicationDate(body []byte) (string, time.Time, error) {
+	resp := cb_entity.RussiaCBRData{}
+	err := decodeRussiaCBRXML(body, &resp)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.Date == "" {
+		return "", time.Time{}, nil
+	}
+	date, err := time.Parse(russiaCBRDateLayout, resp.Date)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("bad date %s in the response, err %s", resp.Date, err)
+	}
+	return date.Format(entity.DateLayout), time.Time{}, nil
+}
+
+// russiaCBRMetals maps the codes of precious metals used by Central Bank of Russia to ISO 4217 codes
+var russiaCBRMetals = map[string]string{
+	"1": entity.Gold,
+	"2": entity.Silver,
+	"3": entity.Platinum,
+	"4": entity.Palladium,
+}
+
+// RussiaCBRMetalsResponseToPrices converts the response from Central Bank of Russia precious metals API to
+// map where keys are ISO 4217 codes of the metals and values are entity.MetalPrice of a gram of the metal.
+// Response contains the prices for a date range, only the prices of the latest date are returned along
+// with that date (in entity.DateLayout format). Records of unknown metals are skipped.
+func RussiaCBRMetalsResponseToPrices(body []byte) (map[string]entity.MetalPrice, string, error) {
+	resp := cb_entity.RussiaCBRMetalsData{}
+	err := decodeRussiaCBRXML(body, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+	byDate := map[string]map[string]entity.MetalPrice{}
+	latest := ""
+	for _, r := range resp.Records {
+		metal, ok := russiaCBRMetals[r.Code]
+		if !ok {
+			continue
+		}
+		d, err := time.Parse(russiaCBRDateLayout, r.Date)
+		if err != nil {
+			continue
+		}
+		buy, err := money.Parse(r.Buy)
+		if err != nil {
+			continue
+		}
+		sell, err := money.Parse(r.Sell)
+		if err != nil {
+			continue
+		}
+		date := d.Format(entity.DateLayout)
+		m, ok := byDate[date]
+		if !ok {
+			m = map[string]entity.MetalPrice{}
+			byDate[date] = m
+		}
+		m[metal] = entity.MetalPrice{
+			Metal:    metal,
+			Name:     entity.Metals[metal],
+			Currency: "RUB",
+			Buy:      buy,
+			Sell:     sell,
+		}
+		if date > latest {
+			latest = date
+		}
+	}
+	if latest == "" {
+		return nil, "", errors.New("no metal prices in the response")
+	}
+	return byDate[latest], latest, nil
+}
+
+// RussiaCBRCurrenciesResponseToCurrencies converts the currencies reference response from Central Bank of Russia
+// to map where keys are ISO 4217 alphabetic codes and values are entity.Currency.
+// Currencies without ISO codes (no longer in use) are skipped, numeric codes are padded to 3 digits.
+func RussiaCBRCurrenciesResponseToCurrencies(body []byte) (map[string]entity.Currency, error) {
+	resp := cb_entity.RussiaCBRCurrenciesData{}
+	err := decodeRussiaCBRXML(body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]entity.Currency{}
+	for _, r := range resp.Currencies {
+		code := strings.TrimSpace(r.CharCode)
+		if code == "" {
+			continue
+		}
+		numeric := strings.TrimSpace(r.NumCode)
+		if numeric != "" && len(numeric) < 3 {
+			numeric = strings.Repeat("0", 3-len(numeric)) + numeric
+		}
+		m[code] = entity.Currency{
+			Code:        code,
+			NumericCode: numeric,
+			Name:        strings.TrimSpace(r.EngName),
+			NameRu:      strings.TrimSpace(r.Name),
+			Nominal:     r.Nominal,
+		}
 	}
 	return m, nil
 }
+
+// RussiaCBRCurrenciesResponseToCurrencyIDs converts the currencies reference response from Central Bank of Russia
+// to map where keys are ISO 4217 alphabetic codes (e.g. USD) and values are internal IDs of the Central Bank of Russia
+// (e.g. R01235). Internal IDs are required to request the rates from the dynamic API.
+// Currencies without ISO codes (no longer in use) are skipped.
+func RussiaCBRCurrenciesResponseToCurrencyIDs(body []byte) (map[string]string, error) {
+	resp := cb_entity.RussiaCBRCurrenciesData{}
+	err := decodeRussiaCBRXML(body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]string{}
+	for _, r := range resp.Currencies {
+		code := strings.TrimSpace(r.CharCode)
+		if code == "" || r.ID == "" {
+			continue
+		}
+		m[code] = r.ID
+	}
+	return m, nil
+}
+
+func decodeRussiaCBRXML(body []byte, v interface{}) error {
+	reader := bytes.NewReader(body)
+	parser := xml.NewDecoder(reader)
+	parser.CharsetReader = charset.NewReaderLabel
+	err := parser.Decode(v)
+	if err != nil {
+		return fmt.Errorf("xml unmarshal failed %s", err)
+	}
+	return nil
+}
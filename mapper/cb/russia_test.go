@@ -1,9 +1,11 @@
 package cb
 
 import (
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
 	"testing"
+	"time"
 )
 
 func TestRussiaCBRResponseToRates(t *testing.T) {
@@ -58,19 +60,19 @@ func TestRussiaCBRResponseToRates(t *testing.T) {
 					Nominal:          1,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "AUD",
-					RateTargetToBase: 54.8131,
+					RateTargetToBase: decimal.RequireFromString("54.8131"),
 				},
 				"AZN": {
 					Nominal:          10,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "AZN",
-					RateTargetToBase: 48.0164,
+					RateTargetToBase: decimal.RequireFromString("48.0164"),
 				},
 				"RUB": {
 					Nominal:          1,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "RUB",
-					RateTargetToBase: 1,
+					RateTargetToBase: decimal.RequireFromString("1"),
 				},
 			},
 			assertion: assert.NoError,
@@ -93,13 +95,13 @@ func TestRussiaCBRResponseToRates(t *testing.T) {
 					Nominal:          10,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "AZN",
-					RateTargetToBase: 48.0164,
+					RateTargetToBase: decimal.RequireFromString("48.0164"),
 				},
 				"RUB": {
 					Nominal:          1,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "RUB",
-					RateTargetToBase: 1,
+					RateTargetToBase: decimal.RequireFromString("1"),
 				},
 			},
 			assertion: assert.NoError,
@@ -113,3 +115,239 @@ func TestRussiaCBRResponseToRates(t *testing.T) {
 		})
 	}
 }
+
+func TestRussiaCBRDynamicResponseToRates(t *testing.T) {
+	correctXML := []byte(`<ValCurs ID="R01235" DateRange1="14.04.2023" DateRange2="19.04.2023" name="Foreign Currency Market Dynamic">
+<Record Date="14.04.2023" Id="R01235"><Nominal>1</Nominal><Value>81,7127</Value></Record>
+<Record Date="15.04.2023" Id="R01235"><Nominal>1</Nominal><Value>81,5280</Value></Record>
+<Record Date="bad date" Id="R01235"><Nominal>1</Nominal><Value>81,5280</Value></Record>
+<Record Date="18.04.2023" Id="R01235"><Nominal>1</Nominal><Value>bad value</Value></Record>
+</ValCurs>`)
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.Rate
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, bad records are skipped",
+			body: correctXML,
+			want: map[string]entity.Rate{
+				"2023-04-14": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("81.7127"),
+				},
+				"2023-04-15": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("81.528"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<ValCurs`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RussiaCBRDynamicResponseToRates(tt.body, "USD")
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRussiaCBRMetalsResponseToPrices(t *testing.T) {
+	correctXML := []byte(`<Metall FromDate="20230413" ToDate="20230418" name="Precious metals quotations">
+<Record Date="18.04.2023" Code="1"><Buy>5050,63</Buy><Sell>5050,63</Sell></Record>
+<Record Date="18.04.2023" Code="2"><Buy>63,67</Buy><Sell>63,67</Sell></Record>
+<Record Date="14.04.2023" Code="1"><Buy>5011,12</Buy><Sell>5011,12</Sell></Record>
+<Record Date="18.04.2023" Code="3"><Buy>2601,5</Buy><Sell>bad value</Sell></Record>
+<Record Date="18.04.2023" Code="4"><Buy>bad value</Buy><Sell>3857,45</Sell></Record>
+<Record Date="18.04.2023" Code="9"><Buy>1</Buy><Sell>1</Sell></Record>
+<Record Date="bad date" Code="4"><Buy>3857,45</Buy><Sell>3857,45</Sell></Record>
+</Metall>`)
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.MetalPrice
+		wantDate  string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, latest date only, bad records are skipped",
+			body: correctXML,
+			want: map[string]entity.MetalPrice{
+				"XAU": {
+					Metal:    "XAU",
+					Name:     "gold",
+					Currency: "RUB",
+					Buy:      decimal.RequireFromString("5050.63"),
+					Sell:     decimal.RequireFromString("5050.63"),
+				},
+				"XAG": {
+					Metal:    "XAG",
+					Name:     "silver",
+					Currency: "RUB",
+					Buy:      decimal.RequireFromString("63.67"),
+					Sell:     decimal.RequireFromString("63.67"),
+				},
+			},
+			wantDate:  "2023-04-18",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no records",
+			body:      []byte(`<Metall FromDate="20230415" ToDate="20230416" name="Precious metals quotations"></Metall>`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<Metall`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotDate, err := RussiaCBRMetalsResponseToPrices(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDate, gotDate)
+		})
+	}
+}
+
+func TestRussiaCBRCurrenciesResponseToCurrencies(t *testing.T) {
+	correctXML := []byte(`<Valuta name="Foreign Currency Market Lib">
+<Item ID="R01235"><Name>Доллар США</Name><EngName>US Dollar</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01235    </ParentCode><ISO_Num_Code>840</ISO_Num_Code><ISO_Char_Code>USD</ISO_Char_Code></Item>
+<Item ID="R01090B"><Name>Белорусский рубль</Name><EngName>Belarussian Ruble</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01090    </ParentCode><ISO_Num_Code>933</ISO_Num_Code><ISO_Char_Code>BYN</ISO_Char_Code></Item>
+<Item ID="R01010"><Name>Австралийский доллар</Name><EngName>Australian Dollar</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01010    </ParentCode><ISO_Num_Code>36</ISO_Num_Code><ISO_Char_Code>AUD</ISO_Char_Code></Item>
+<Item ID="R01436"><Name>Литовский лит</Name><EngName>Lithuanian Lita</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01435    </ParentCode><ISO_Num_Code></ISO_Num_Code><ISO_Char_Code></ISO_Char_Code></Item>
+</Valuta>`)
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]entity.Currency
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, numeric codes padded, currencies without codes skipped",
+			body: correctXML,
+			want: map[string]entity.Currency{
+				"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1},
+				"BYN": {Code: "BYN", NumericCode: "933", Name: "Belarussian Ruble", NameRu: "Белорусский рубль", Nominal: 1},
+				"AUD": {Code: "AUD", NumericCode: "036", Name: "Australian Dollar", NameRu: "Австралийский доллар", Nominal: 1},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<Valuta`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RussiaCBRCurrenciesResponseToCurrencies(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRussiaCBRCurrenciesResponseToCurrencyIDs(t *testing.T) {
+	correctXML := []byte(`<Valuta name="Foreign Currency Market Lib">
+<Item ID="R01235"><Name>Доллар США</Name><EngName>US Dollar</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01235    </ParentCode><ISO_Num_Code>840</ISO_Num_Code><ISO_Char_Code>USD</ISO_Char_Code></Item>
+<Item ID="R01090B"><Name>Белорусский рубль</Name><EngName>Belarussian Ruble</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01090    </ParentCode><ISO_Num_Code>933</ISO_Num_Code><ISO_Char_Code>BYN</ISO_Char_Code></Item>
+<Item ID="R01436"><Name>Литовский лит</Name><EngName>Lithuanian Lita</EngName><Nominal>1</Nominal>` +
+		`<ParentCode>R01435    </ParentCode><ISO_Num_Code></ISO_Num_Code><ISO_Char_Code></ISO_Char_Code></Item>
+</Valuta>`)
+	tests := []struct {
+		name      string
+		body      []byte
+		want      map[string]string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, currencies without codes skipped",
+			body: correctXML,
+			want: map[string]string{
+				"USD": "R01235",
+				"BYN": "R01090B",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<Valuta`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RussiaCBRCurrenciesResponseToCurrencyIDs(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRussiaCBRResponseToPublicationDate(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            []byte
+		wantDate        string
+		wantPublishedAt time.Time
+		assertion       assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			body:      []byte(`<ValCurs Date="18.04.2023" name="Foreign Currency Market"></ValCurs>`),
+			wantDate:  "2023-04-18",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no date",
+			body:      []byte(`<ValCurs name="Foreign Currency Market"></ValCurs>`),
+			wantDate:  "",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			body:      []byte(`<ValCurs Date="2023-04-18" name="Foreign Currency Market"></ValCurs>`),
+			wantDate:  "",
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<ValCurs`),
+			wantDate:  "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, publishedAt, err := RussiaCBRResponseToPublicationDate(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.wantDate, date)
+			assert.Equal(t, tt.wantPublishedAt, publishedAt)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package cb
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"io"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"my_go/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sdmxSeriesKeySeparator = "."
+	sdmxTimePeriod         = "TIME_PERIOD"
+	sdmxObsValue           = "OBS_VALUE"
+)
+
+// SDMXJSONResponseToObservations flattens SDMX-JSON data message to the list of observations.
+// Series keys are built of the ids of the series dimension values, the first observation dimension
+// is considered to be the time period. Observations without value are skipped.
+func SDMXJSONResponseToObservations(body []byte) ([]cb_entity.SDMXObservation, error) {
+	resp := cb_entity.SDMXJSONData{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response: %s", err)
+	}
+	if resp.Data != nil {
+		resp = *resp.Data
+	}
+	dims := resp.Structure.Dimensions
+	if len(dims.Observation) == 0 {
+		return nil, errors.New("no observation dimension in the structure")
+	}
+	var res []cb_entity.SDMXObservation
+	for _, ds := range resp.DataSets {
+		for key, series := range ds.Series {
+			seriesKey, err := sdmxJSONKey(key, dims.Series)
+			if err != nil {
+				return nil, err
+			}
+			for obsKey, obs := range series.Observations {
+				period, err := sdmxJSONKey(obsKey, dims.Observation[:1])
+				if err != nil {
+					return nil, err
+				}
+				if len(obs) == 0 {
+					continue
+				}
+				value, ok := sdmxJSONValue(obs[0])
+				if !ok {
+					continue
+				}
+				res = append(res, cb_entity.SDMXObservation{
+					SeriesKey:  seriesKey,
+					TimePeriod: period,
+					Value:      value,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// sdmxJSONKey resolves the key made of the indexes of the dimension values (e.g. "0:2:0:0")
+// to the ids of the values joined by dot (e.g. "B.USD.NOK.SP")
+func sdmxJSONKey(key string, dims []cb_entity.SDMXJSONDimension) (string, error) {
+	indexes := strings.Split(key, ":")
+	if len(indexes) != len(dims) {
+		return "", fmt.Errorf("key %s doesn't match %d dimensions of the structure", key, len(dims))
+	}
+	ids := make([]string, len(indexes))
+	for i, idx := range indexes {
+		n, err := strconv.Atoi(idx)
+		if err != nil || n < 0 || n >= len(dims[i].Values) {
+			return "", fmt.Errorf("bad index %s of the dimension %s in the key %s", idx, dims[i].ID, key)
+		}
+		ids[i] = dims[i].Values[n].ID
+	}
+	return strings.Join(ids, sdmxSeriesKeySeparator), nil
+}
+
+// sdmxJSONValue returns the observation value that is published either as string or as number
+func sdmxJSONValue(raw json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, s != ""
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String(), true
+	}
+	return "", false
+}
+
+// SDMXMLResponseToObservations flattens SDMX-ML structure-specific data message to the list of observations.
+// The message doesn't define the order of the dimensions, so the series keys are built of the values of the
+// keyDimensions attributes joined by dot. Dimensions might be set either on Series or on Obs elements.
+func SDMXMLResponseToObservations(body []byte, keyDimensions []string) ([]cb_entity.SDMXObservation, error) {
+	if len(keyDimensions) == 0 {
+		return nil, errors.New("no key dimensions provided")
+	}
+	var (
+		res         []cb_entity.SDMXObservation
+		seriesAttrs map[string]string
+		dataSet     bool
+	)
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the response: %s", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "DataSet":
+				dataSet = true
+			case "Series":
+				seriesAttrs = sdmxMLAttrs(t.Attr, nil)
+			case "Obs":
+				attrs := sdmxMLAttrs(t.Attr, seriesAttrs)
+				value := attrs[sdmxObsValue]
+				if value == "" {
+					continue
+				}
+				ids := make([]string, len(keyDimensions))
+				for i, d := range keyDimensions {
+					ids[i] = attrs[d]
+				}
+				res = append(res, cb_entity.SDMXObservation{
+					SeriesKey:  strings.Join(ids, sdmxSeriesKeySeparator),
+					TimePeriod: attrs[sdmxTimePeriod],
+					Value:      value,
+				})
+			}
+		case xml.EndElement:
+			if t.Name.Local == "Series" {
+				seriesAttrs = nil
+			}
+		}
+	}
+	if !dataSet {
+		return nil, errors.New("no data set in the response")
+	}
+	return res, nil
+}
+
+// sdmxMLAttrs maps the attributes by the local name on top of the inherited ones
+func sdmxMLAttrs(attrs []xml.Attr, inherited map[string]string) map[string]string {
+	m := make(map[string]string, len(attrs)+len(inherited))
+	for k, v := range inherited {
+		m[k] = v
+	}
+	for _, a := range attrs {
+		m[a.Name.Local] = a.Value
+	}
+	return m
+}
+
+// SDMXObservationsToRates converts the observations of the series to the map where keys are currency ID and
+// values are entity.Rate along with the effective date. The latest daily time period among the known series
+// is considered to be the effective date, series without observation for that date are skipped.
+// Observations of unknown series, with non-daily time period or non-numeric value (e.g. NaN) are ignored.
+// Inverse is set when the bank quotes the amount of the currency for Nominal units of base currency.
+// For the convenience of the conversion calculation the rate of base currency to base currency is added.
+func SDMXObservationsToRates(
+	observations []cb_entity.SDMXObservation,
+	series map[string]cb_entity.SDMXSeries,
+	baseCurrency string,
+	inverse bool,
+) (map[string]entity.Rate, string, error) {
+	values := map[string]map[string]decimal.Decimal{}
+	effectiveDate := ""
+	for _, o := range observations {
+		if _, ok := series[o.SeriesKey]; !ok {
+			continue
+		}
+		if _, err := time.Parse(entity.DateLayout, o.TimePeriod); err != nil {
+			continue
+		}
+		value, err := money.Parse(o.Value)
+		if err != nil || !value.IsPositive() {
+			continue
+		}
+		if values[o.TimePeriod] == nil {
+			values[o.TimePeriod] = map[string]decimal.Decimal{}
+		}
+		values[o.TimePeriod][o.SeriesKey] = value
+		if o.TimePeriod > effectiveDate {
+			effectiveDate = o.TimePeriod
+		}
+	}
+	if effectiveDate == "" {
+		return nil, "", errors.New("no observations of the configured series in the response")
+	}
+	m := map[string]entity.Rate{}
+	for key, value := range values[effectiveDate] {
+		s := series[key]
+		nominal := s.Nominal
+		if nominal <= 0 {
+			nominal = 1
+		}
+		m[s.Currency] = entity.Rate{
+			Nominal:          nominal,
+			BaseCurrency:     baseCurrency,
+			TargetCurrency:   s.Currency,
+			RateTargetToBase: value,
+			Inverse:          inverse,
+		}
+	}
+	m[baseCurrency] = entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     baseCurrency,
+		TargetCurrency:   baseCurrency,
+		RateTargetToBase: decimal.NewFromInt(1),
+	}
+	return m, effectiveDate, nil
+}
@@ -0,0 +1,251 @@
+package cb
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	cb_entity "my_go/entity/cb"
+	"sort"
+	"testing"
+)
+
+var sdmxJSONMessage = []byte(`{"data":{"dataSets":[{"series":{` +
+	`"0:0:0:0":{"observations":{"0":["10.5","0"],"1":["10.6"]}},` +
+	`"0:1:0:0":{"observations":{"0":[149.35],"1":[null]}}}}],` +
+	`"structure":{"dimensions":{"series":[` +
+	`{"id":"FREQ","values":[{"id":"B"}]},` +
+	`{"id":"BASE_CUR","values":[{"id":"USD"},{"id":"JPY"}]},` +
+	`{"id":"QUOTE_CUR","values":[{"id":"NOK"}]},` +
+	`{"id":"TENOR","values":[{"id":"SP"}]}],` +
+	`"observation":[{"id":"TIME_PERIOD","values":[{"id":"2023-04-18"},{"id":"2023-04-19"}]}]}}}}`)
+
+var sdmxMLMessage = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<message:StructureSpecificData xmlns:message="http://www.sdmx.org/resources/sdmxml/schemas/v2_1/message"
+	xmlns:ss="http://www.sdmx.org/resources/sdmxml/schemas/v2_1/data/structurespecific">
+	<message:Header><message:ID>EXR</message:ID></message:Header>
+	<message:DataSet ss:dataScope="DataStructure">
+		<Series FREQ="D" CURRENCY="USD" CURRENCY_DENOM="EUR" EXR_TYPE="SP00" EXR_SUFFIX="A">
+			<Obs TIME_PERIOD="2023-04-18" OBS_VALUE="1.0973"/>
+			<Obs TIME_PERIOD="2023-04-19" OBS_VALUE="1.0958"/>
+		</Series>
+		<Series FREQ="D" CURRENCY="JPY" CURRENCY_DENOM="EUR" EXR_TYPE="SP00" EXR_SUFFIX="A">
+			<Obs TIME_PERIOD="2023-04-19" OBS_VALUE="147.2"/>
+		</Series>
+		<Obs FREQ="D" CURRENCY="GBP" CURRENCY_DENOM="EUR" EXR_TYPE="SP00" EXR_SUFFIX="A" TIME_PERIOD="2023-04-19" OBS_VALUE="0.88"/>
+		<Obs FREQ="D" CURRENCY="CHF" CURRENCY_DENOM="EUR" EXR_TYPE="SP00" EXR_SUFFIX="A" TIME_PERIOD="2023-04-19" OBS_VALUE=""/>
+	</message:DataSet>
+</message:StructureSpecificData>`)
+
+var ecbKeyDimensions = []string{"FREQ", "CURRENCY", "CURRENCY_DENOM", "EXR_TYPE", "EXR_SUFFIX"}
+
+func sortObservations(o []cb_entity.SDMXObservation) {
+	sort.Slice(o, func(i, j int) bool {
+		if o[i].SeriesKey != o[j].SeriesKey {
+			return o[i].SeriesKey < o[j].SeriesKey
+		}
+		return o[i].TimePeriod < o[j].TimePeriod
+	})
+}
+
+func TestSDMXJSONResponseToObservations(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      []cb_entity.SDMXObservation
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, string and number values, null skipped",
+			body: sdmxJSONMessage,
+			want: []cb_entity.SDMXObservation{
+				{SeriesKey: "B.JPY.NOK.SP", TimePeriod: "2023-04-18", Value: "149.35"},
+				{SeriesKey: "B.USD.NOK.SP", TimePeriod: "2023-04-18", Value: "10.5"},
+				{SeriesKey: "B.USD.NOK.SP", TimePeriod: "2023-04-19", Value: "10.6"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, top level data sets",
+			body: []byte(`{"dataSets":[{"series":{"0":{"observations":{"0":[1.0958]}}}}],` +
+				`"structure":{"dimensions":{"series":[{"id":"CURRENCY","values":[{"id":"USD"}]}],` +
+				`"observation":[{"id":"TIME_PERIOD","values":[{"id":"2023-04-19"}]}]}}}`),
+			want: []cb_entity.SDMXObservation{
+				{SeriesKey: "USD", TimePeriod: "2023-04-19", Value: "1.0958"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Malformed json",
+			body:      []byte(`{"data":`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "No observation dimension",
+			body:      []byte(`{"dataSets":[],"structure":{"dimensions":{"series":[],"observation":[]}}}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "Series key doesn't match the structure",
+			body: []byte(`{"dataSets":[{"series":{"0:0":{"observations":{"0":[1.0958]}}}}],` +
+				`"structure":{"dimensions":{"series":[{"id":"CURRENCY","values":[{"id":"USD"}]}],` +
+				`"observation":[{"id":"TIME_PERIOD","values":[{"id":"2023-04-19"}]}]}}}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "Observation index out of range",
+			body: []byte(`{"dataSets":[{"series":{"0":{"observations":{"1":[1.0958]}}}}],` +
+				`"structure":{"dimensions":{"series":[{"id":"CURRENCY","values":[{"id":"USD"}]}],` +
+				`"observation":[{"id":"TIME_PERIOD","values":[{"id":"2023-04-19"}]}]}}}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SDMXJSONResponseToObservations(tt.body)
+			tt.assertion(t, err)
+			sortObservations(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSDMXMLResponseToObservations(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          []byte
+		keyDimensions []string
+		want          []cb_entity.SDMXObservation
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Happy path, series and flat observations",
+			body:          sdmxMLMessage,
+			keyDimensions: ecbKeyDimensions,
+			want: []cb_entity.SDMXObservation{
+				{SeriesKey: "D.USD.EUR.SP00.A", TimePeriod: "2023-04-18", Value: "1.0973"},
+				{SeriesKey: "D.USD.EUR.SP00.A", TimePeriod: "2023-04-19", Value: "1.0958"},
+				{SeriesKey: "D.JPY.EUR.SP00.A", TimePeriod: "2023-04-19", Value: "147.2"},
+				{SeriesKey: "D.GBP.EUR.SP00.A", TimePeriod: "2023-04-19", Value: "0.88"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:          "No key dimensions",
+			body:          sdmxMLMessage,
+			keyDimensions: nil,
+			want:          nil,
+			assertion:     assert.Error,
+		},
+		{
+			name:          "Malformed xml",
+			body:          []byte(`<message:StructureSpecificData><message:DataSet>`),
+			keyDimensions: ecbKeyDimensions,
+			want:          nil,
+			assertion:     assert.Error,
+		},
+		{
+			name:          "Error message instead of data",
+			body:          []byte(`<message:Error><message:ErrorMessage code="100"/></message:Error>`),
+			keyDimensions: ecbKeyDimensions,
+			want:          nil,
+			assertion:     assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SDMXMLResponseToObservations(tt.body, tt.keyDimensions)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSDMXObservationsToRates(t *testing.T) {
+	series := map[string]cb_entity.SDMXSeries{
+		"B.USD.NOK.SP": {Currency: "USD"},
+		"B.JPY.NOK.SP": {Currency: "JPY", Nominal: 100},
+	}
+	nok := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "NOK",
+		TargetCurrency:   "NOK",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	tests := []struct {
+		name         string
+		observations []cb_entity.SDMXObservation
+		inverse      bool
+		want         map[string]entity.Rate
+		wantDate     string
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, nominal from the series",
+			observations: []cb_entity.SDMXObservation{
+				{SeriesKey: "B.USD.NOK.SP", TimePeriod: "2023-04-19", Value: "10.6"},
+				{SeriesKey: "B.JPY.NOK.SP", TimePeriod: "2023-04-19", Value: "7.8912"},
+			},
+			want: map[string]entity.Rate{
+				"NOK": nok,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "NOK",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("10.6"),
+				},
+				"JPY": {
+					Nominal:          100,
+					BaseCurrency:     "NOK",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("7.8912"),
+				},
+			},
+			wantDate:  "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, latest date used, unknown series and bad observations ignored, inverse",
+			observations: []cb_entity.SDMXObservation{
+				{SeriesKey: "B.USD.NOK.SP", TimePeriod: "2023-04-18", Value: "10.5"},
+				{SeriesKey: "B.USD.NOK.SP", TimePeriod: "2023-04-19", Value: "10.6"},
+				{SeriesKey: "B.JPY.NOK.SP", TimePeriod: "2023-04-18", Value: "7.8"},
+				{SeriesKey: "B.JPY.NOK.SP", TimePeriod: "2023-04-20", Value: "NaN"},
+				{SeriesKey: "B.JPY.NOK.SP", TimePeriod: "2023-05", Value: "7.7"},
+				{SeriesKey: "B.GBP.NOK.SP", TimePeriod: "2023-04-21", Value: "13.1"},
+			},
+			inverse: true,
+			want: map[string]entity.Rate{
+				"NOK": nok,
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "NOK",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("10.6"),
+					Inverse:          true,
+				},
+			},
+			wantDate:  "2023-04-19",
+			assertion: assert.NoError,
+		},
+		{
+			name: "No observations of the configured series",
+			observations: []cb_entity.SDMXObservation{
+				{SeriesKey: "B.GBP.NOK.SP", TimePeriod: "2023-04-19", Value: "13.1"},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotDate, err := SDMXObservationsToRates(tt.observations, series, "NOK", tt.inverse)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDate, gotDate)
+		})
+	}
+}
@@ -2,28 +2,40 @@ package cb
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"golang.org/x/net/html/charset"
 	"my_go/entity"
 	cb_entity "my_go/entity/cb"
+	"my_go/money"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
-const (
-	buyRateString  = "Bank of Thailand Average Buying Sight Bill"
-	sellRateString = "Bank of Thailand Average Selling Rate"
-)
+// thailandRateTypes maps the titles of the daily feed items to the rate types of the quotes catalogue
+var thailandRateTypes = []struct {
+	title    string
+	rateType string
+}{
+	{title: "Bank of Thailand Average Buying Sight Bill", rateType: entity.RateTypeBuyingSight},
+	{title: "Bank of Thailand Average Buying Transfer", rateType: entity.RateTypeBuyingTransfer},
+	{title: "Bank of Thailand Average Selling Rate", rateType: entity.RateTypeSelling},
+}
 
 var descriptionRegEx = regexp.MustCompile("[0-9\\.]+ Thai Baht = ([0-9]+) [A-Z]{3}")
 
 // ThailandCBRResponseToRates converts xml response from Thai central bank to the map of
 // currency ids to map where keys are currency ID and values are entity.Rate.
-// The foreign exchange rate for Thai bank is taken as average between Buying Sight bill and Selling Rate as no clear
-// definition (as for example bank of Russia has) for a daily rate is provided
-// For the convenience of the conversion calculation the rate of RUR to RUR conversion is added.
+// All the published rate types are kept in Quotes of the rate.
+// Buying Sight bill and Selling Rate are also kept as Bid and Ask of the rate, the foreign exchange rate for Thai bank
+// is taken as average between them (Mid) as no clear definition (as for example bank of Russia has) for a daily
+// rate is provided. If only one of them is published it is used as the rate, the currencies having neither of them
+// are skipped (same as by the historical API). The order of the items doesn't matter.
+// For the convenience of the conversion calculation the rate of THB to THB conversion is added.
 func ThailandCBRResponseToRates(body []byte) (map[string]entity.Rate, error) {
 	reader := bytes.NewReader(body)
 	parser := xml.NewDecoder(reader)
@@ -35,7 +47,8 @@ func ThailandCBRResponseToRates(body []byte) (map[string]entity.Rate, error) {
 	}
 	m := map[string]entity.Rate{}
 	for _, r := range resp.Rates {
-		if !strings.Contains(r.Title, buyRateString) && !strings.Contains(r.Title, sellRateString) {
+		rateType := thailandRateType(r.Title)
+		if rateType == "" {
 			continue
 		}
 		v := descriptionRegEx.FindStringSubmatch(r.Description)
@@ -46,26 +59,188 @@ func ThailandCBRResponseToRates(body []byte) (map[string]entity.Rate, error) {
 		if err != nil {
 			continue // unreachable in tests cause this group contains from 0..9 only
 		}
-		data, ok := m[r.TargetCurrency]
-		var rateTargetToBase float64
-		if !ok {
-			rateTargetToBase = r.Value
-		} else {
-			// might be issues if rates are with 6 digits. but no problems for 4 digits at all.
-			rateTargetToBase = (r.Value + data.RateTargetToBase) / 2
+		value, err := money.Parse(r.Value)
+		if err != nil {
+			continue
+		}
+		rate := m[r.TargetCurrency]
+		rate.Nominal = nominal
+		rate.TargetCurrency = r.TargetCurrency
+		rate.BaseCurrency = "THB"
+		if rate.Quotes == nil {
+			rate.Quotes = map[string]decimal.Decimal{}
 		}
-		m[r.TargetCurrency] = entity.Rate{
-			Nominal:          nominal,
-			RateTargetToBase: rateTargetToBase,
-			TargetCurrency:   r.TargetCurrency,
-			BaseCurrency:     "THB",
+		rate.Quotes[rateType] = value
+		m[r.TargetCurrency] = rate
+	}
+	for currency, rate := range m {
+		rate = withMid(rate)
+		if rate.Bid == nil && rate.Ask == nil {
+			// only Buying Transfer is published, there is no rate to take
+			delete(m, currency)
+			continue
 		}
+		m[currency] = rate
 	}
 	m["THB"] = entity.Rate{
 		Nominal:          1,
 		BaseCurrency:     "THB",
 		TargetCurrency:   "THB",
-		RateTargetToBase: 1,
+		RateTargetToBase: decimal.NewFromInt(1),
 	}
 	return m, nil
 }
+
+// thailandRateType returns the rate type of the daily feed item by its title, empty if the type is unknown
+func thailandRateType(title string) string {
+	for _, t := range thailandRateTypes {
+		if strings.Contains(title, t.title) {
+			return t.rateType
+		}
+	}
+	return ""
+}
+
+// withMid sets Bid and Ask of the rate of Thai bank from the Buying Sight bill and Selling Rate quotes and
+// the rate as the average of Bid and Ask if both are present, otherwise the present one is used.
+func withMid(rate entity.Rate) entity.Rate {
+	if v, ok := rate.Quotes[entity.RateTypeBuyingSight]; ok {
+		rate.Bid = &v
+	}
+	if v, ok := rate.Quotes[entity.RateTypeSelling]; ok {
+		rate.Ask = &v
+	}
+	switch {
+	case rate.Bid != nil && rate.Ask != nil:
+		mid := money.Average(*rate.Bid, *rate.Ask)
+		rate.Mid = &mid
+		rate.RateTargetToBase = mid
+	case rate.Bid != nil:
+		rate.RateTargetToBase = *rate.Bid
+	case rate.Ask != nil:
+		rate.RateTargetToBase = *rate.Ask
+	}
+	return rate
+}
+
+// ThailandCBRResponseToPublicationDate returns the official date (in entity.DateLayout format) the rates in the
+// xml response from Thai central bank are set for and the publication timestamp.
+// The date of the feed is taken from the channel, if it's missing the latest date of the items is used.
+// Feed provides either the date only or the timestamp, publication timestamp is zero in the former case.
+// Empty date is returned if the response has no date.
+func ThailandCBRResponseToPublicationDate(body []byte) (string, time.Time, error) {
+	reader := bytes.NewReader(body)
+	parser := xml.NewDecoder(reader)
+	parser.CharsetReader = charset.NewReaderLabel
+	resp := cb_entity.ThailandCBRData{}
+	err := parser.Decode(&resp)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("xml unmarshal failed %s", err)
+	}
+	raw := strings.TrimSpace(resp.Date)
+	if raw == "" {
+		for _, r := range resp.Rates {
+			if d := strings.TrimSpace(r.Date); d > raw {
+				raw = d
+			}
+		}
+	}
+	if raw == "" {
+		return "", time.Time{}, nil
+	}
+	if date, err := time.Parse(entity.DateLayout, raw); err == nil {
+		return date.Format(entity.DateLayout), time.Time{}, nil
+	}
+	publishedAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("bad date %s in the response", raw)
+	}
+	return publishedAt.Format(entity.DateLayout), publishedAt, nil
+}
+
+var currencyNameNominalRegEx = regexp.MustCompile("\\(([0-9]+) [A-Z ]+\\)")
+
+// ThailandCBRHistoricalResponseToRates converts json response from Thai central bank historical API
+// to the map where keys are currency ID and values are entity.Rate along with the date the rates are published for.
+// Only the records of the latest date on or before the provided date are taken into account,
+// as no rates are published for weekends and holidays.
+func ThailandCBRHistoricalResponseToRates(body []byte, date string) (map[string]entity.Rate, string, error) {
+	byDate, err := ThailandCBRHistoricalResponseToRatesByDate(body)
+	if err != nil {
+		return nil, "", err
+	}
+	effectiveDate := ""
+	for d := range byDate {
+		// dates are in the 2006-01-02 layout, so they are compared as strings
+		if d <= date && d > effectiveDate {
+			effectiveDate = d
+		}
+	}
+	if effectiveDate == "" {
+		return nil, "", fmt.Errorf("no rates published on or before %s", date)
+	}
+	return byDate[effectiveDate], effectiveDate, nil
+}
+
+// ThailandCBRHistoricalResponseToRatesByDate converts json response from Thai central bank historical API
+// to the map where keys are dates and values are maps of currency ID to entity.Rate.
+// Same as for the daily feed all the published rate types are kept in Quotes, Buying Sight bill and
+// Selling Rate are kept as Bid and Ask and the rate is taken as average between them.
+// The records missing either Buying Sight bill or Selling Rate are skipped.
+// Historical API provides nominal only as a part of currency name e.g. "JAPAN : YEN (100 YEN)",
+// if it's not provided nominal of 1 is assumed.
+// For the convenience of the conversion calculation the rate of THB to THB conversion is added for every date.
+func ThailandCBRHistoricalResponseToRatesByDate(body []byte) (map[string]map[string]entity.Rate, error) {
+	resp := cb_entity.ThailandCBRHistoricalData{}
+	err := json.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("json unmarshal failed %s", err)
+	}
+	byDate := map[string]map[string]entity.Rate{}
+	for _, r := range resp.Result.Data.DataDetail {
+		buy, err := money.Parse(r.BuyingSight)
+		if err != nil {
+			continue
+		}
+		sell, err := money.Parse(r.Selling)
+		if err != nil {
+			continue
+		}
+		nominal := 1
+		if v := currencyNameNominalRegEx.FindStringSubmatch(r.CurrencyNameEng); len(v) == 2 {
+			nominal, err = strconv.Atoi(v[1])
+			if err != nil {
+				continue // unreachable in tests cause this group contains from 0..9 only
+			}
+		}
+		m, ok := byDate[r.Period]
+		if !ok {
+			m = map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+					RateTargetToBase: decimal.NewFromInt(1),
+				},
+			}
+			byDate[r.Period] = m
+		}
+		quotes := map[string]decimal.Decimal{
+			entity.RateTypeBuyingSight: buy,
+			entity.RateTypeSelling:     sell,
+		}
+		if v, err := money.Parse(r.BuyingTransfer); err == nil {
+			quotes[entity.RateTypeBuyingTransfer] = v
+		}
+		if v, err := money.Parse(r.MidRate); err == nil {
+			quotes[entity.RateTypeMidRate] = v
+		}
+		m[r.CurrencyID] = withMid(entity.Rate{
+			Nominal:        nominal,
+			TargetCurrency: r.CurrencyID,
+			BaseCurrency:   "THB",
+			Quotes:         quotes,
+		})
+	}
+	return byDate, nil
+}
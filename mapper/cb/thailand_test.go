@@ -1,11 +1,27 @@
 package cb
 
 import (
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
 	"testing"
+	"time"
 )
 
+func decPtr(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+// quotes builds the quotes catalogue of the rate from the pairs of rate type and value
+func quotes(pairs ...string) map[string]decimal.Decimal {
+	m := map[string]decimal.Decimal{}
+	for i := 0; i < len(pairs); i += 2 {
+		m[pairs[i]] = decimal.RequireFromString(pairs[i+1])
+	}
+	return m
+}
+
 func TestThailandCBRResponseToRates(t *testing.T) {
 	correctXML := []byte(`
 <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:cb="http://centralbanks.org/cb/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3c.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.w3c.org/1999/02/22-rdf-syntax-ns#rdf.xsd">
@@ -139,6 +155,44 @@ func TestThailandCBRResponseToRates(t *testing.T) {
 <cb:application>statistics</cb:application>
 </item>
 </rdf:RDF>
+`)
+	reversedXML := []byte(`
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:cb="http://centralbanks.org/cb/1.0/">
+<item>
+<title>TH: 25.9000 THB = 100 JPY 2023-04-17 Bank of Thailand Average Selling Rate</title>
+<description>25.9000 Thai Baht = 100 JPY</description>
+<cb:targetCurrency>JPY</cb:targetCurrency>
+<cb:value frequency="business" decimals="4">25.9000</cb:value>
+</item>
+<item>
+<title>TH: 25.1000 THB = 100 JPY 2023-04-17 Bank of Thailand Average Buying Transfer</title>
+<description>25.1000 Thai Baht = 100 JPY</description>
+<cb:targetCurrency>JPY</cb:targetCurrency>
+<cb:value frequency="business" decimals="4">25.1000</cb:value>
+</item>
+<item>
+<title>TH: 25.0000 THB = 100 JPY 2023-04-17 Bank of Thailand Average Buying Sight Bill</title>
+<description>25.0000 Thai Baht = 100 JPY</description>
+<cb:targetCurrency>JPY</cb:targetCurrency>
+<cb:value frequency="business" decimals="4">25.0000</cb:value>
+</item>
+</rdf:RDF>
+`)
+	transferOnlyXML := []byte(`
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:cb="http://centralbanks.org/cb/1.0/">
+<item>
+<title>TH: 25.1000 THB = 100 JPY 2023-04-17 Bank of Thailand Average Buying Transfer</title>
+<description>25.1000 Thai Baht = 100 JPY</description>
+<cb:targetCurrency>JPY</cb:targetCurrency>
+<cb:value frequency="business" decimals="4">25.1000</cb:value>
+</item>
+<item>
+<title>TH: 34.5022 THB = 1 USD 2023-04-17 Bank of Thailand Average Selling Rate</title>
+<description>34.5022 Thai Baht = 1 USD</description>
+<cb:targetCurrency>USD</cb:targetCurrency>
+<cb:value frequency="business" decimals="4">34.5022</cb:value>
+</item>
+</rdf:RDF>
 `)
 	type args struct {
 		body []byte
@@ -157,21 +211,29 @@ func TestThailandCBRResponseToRates(t *testing.T) {
 			want: map[string]entity.Rate{
 				"THB": {
 					Nominal:          1,
-					RateTargetToBase: 1,
+					RateTargetToBase: decimal.RequireFromString("1"),
 					BaseCurrency:     "THB",
 					TargetCurrency:   "THB",
 				},
 				"GBP": {
 					Nominal:          1,
-					RateTargetToBase: 42.5171,
+					RateTargetToBase: decimal.RequireFromString("42.5171"),
 					BaseCurrency:     "THB",
 					TargetCurrency:   "GBP",
+					Bid:              decPtr("42.004"),
+					Ask:              decPtr("43.0302"),
+					Mid:              decPtr("42.5171"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "42.004", entity.RateTypeBuyingTransfer, "42.1612", entity.RateTypeSelling, "43.0302"),
 				},
 				"USD": {
 					Nominal:          1,
-					RateTargetToBase: 34.28235,
+					RateTargetToBase: decimal.RequireFromString("34.28235"),
 					BaseCurrency:     "THB",
 					TargetCurrency:   "USD",
+					Bid:              decPtr("34.0625"),
+					Ask:              decPtr("34.5022"),
+					Mid:              decPtr("34.28235"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "34.0625", entity.RateTypeBuyingTransfer, "34.1656", entity.RateTypeSelling, "34.5022"),
 				},
 			},
 			assertion: assert.NoError,
@@ -192,15 +254,65 @@ func TestThailandCBRResponseToRates(t *testing.T) {
 			want: map[string]entity.Rate{
 				"THB": {
 					Nominal:          1,
-					RateTargetToBase: 1,
+					RateTargetToBase: decimal.RequireFromString("1"),
 					BaseCurrency:     "THB",
 					TargetCurrency:   "THB",
 				},
 				"USD": {
 					Nominal:          1,
-					RateTargetToBase: 34.5022,
+					RateTargetToBase: decimal.RequireFromString("34.5022"),
 					BaseCurrency:     "THB",
 					TargetCurrency:   "USD",
+					Ask:              decPtr("34.5022"),
+					Quotes:           quotes(entity.RateTypeSelling, "34.5022"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "selling rate before buying one",
+			args: args{
+				body: reversedXML,
+			},
+			want: map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("1"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+				},
+				"JPY": {
+					Nominal:          100,
+					RateTargetToBase: decimal.RequireFromString("25.45"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "JPY",
+					Bid:              decPtr("25"),
+					Ask:              decPtr("25.9"),
+					Mid:              decPtr("25.45"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "25", entity.RateTypeBuyingTransfer, "25.1", entity.RateTypeSelling, "25.9"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "currency with Buying Transfer only is skipped",
+			args: args{
+				body: transferOnlyXML,
+			},
+			want: map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("1"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+				},
+				"USD": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("34.5022"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "USD",
+					Ask:              decPtr("34.5022"),
+					Quotes:           quotes(entity.RateTypeSelling, "34.5022"),
 				},
 			},
 			assertion: assert.NoError,
@@ -214,3 +326,188 @@ func TestThailandCBRResponseToRates(t *testing.T) {
 		})
 	}
 }
+
+func TestThailandCBRHistoricalResponseToRates(t *testing.T) {
+	correctJSON := []byte(`{"result":{"success":"true","data":{"data_detail":[
+{"period":"2023-04-17","currency_id":"USD","currency_name_eng":"USA : DOLLAR (USD)",
+"buying_sight":"34.0625000","buying_transfer":"34.1656000","selling":"34.5022000","mid_rate":"34.3339000"},
+{"period":"2023-04-17","currency_id":"JPY","currency_name_eng":"JAPAN : YEN (100 YEN)",
+"buying_sight":"25.0000000","buying_transfer":"25.1000000","selling":"26.0000000","mid_rate":"25.5000000"},
+{"period":"2023-04-17","currency_id":"MMK","currency_name_eng":"MYANMAR : KYAT (MMK)",
+"buying_sight":"","buying_transfer":"","selling":"0.0170000","mid_rate":""},
+{"period":"2023-04-17","currency_id":"VND","currency_name_eng":"VIETNAM : DONG (VND)",
+"buying_sight":"0.0014000","buying_transfer":"","selling":"","mid_rate":""},
+{"period":"2023-04-14","currency_id":"GBP","currency_name_eng":"UNITED KINGDOM : POUND STERLING (GBP)",
+"buying_sight":"42.0040000","buying_transfer":"42.1612000","selling":"43.0302000","mid_rate":"42.5171000"}
+]}}}`)
+	type args struct {
+		body []byte
+		date string
+	}
+	tests := []struct {
+		name              string
+		args              args
+		want              map[string]entity.Rate
+		wantEffectiveDate string
+		assertion         assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				body: correctJSON,
+				date: "2023-04-17",
+			},
+			want: map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("1"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+				},
+				"USD": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("34.28235"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "USD",
+					Bid:              decPtr("34.0625"),
+					Ask:              decPtr("34.5022"),
+					Mid:              decPtr("34.28235"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "34.0625", entity.RateTypeBuyingTransfer, "34.1656", entity.RateTypeSelling, "34.5022", entity.RateTypeMidRate, "34.3339"),
+				},
+				"JPY": {
+					Nominal:          100,
+					RateTargetToBase: decimal.RequireFromString("25.5"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "JPY",
+					Bid:              decPtr("25"),
+					Ask:              decPtr("26"),
+					Mid:              decPtr("25.5"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "25", entity.RateTypeBuyingTransfer, "25.1", entity.RateTypeSelling, "26", entity.RateTypeMidRate, "25.5"),
+				},
+			},
+			wantEffectiveDate: "2023-04-17",
+			assertion:         assert.NoError,
+		},
+		{
+			name: "Happy path, weekend date takes the latest rates published before it",
+			args: args{
+				body: correctJSON,
+				date: "2023-04-16",
+			},
+			want: map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("1"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+				},
+				"GBP": {
+					Nominal:          1,
+					RateTargetToBase: decimal.RequireFromString("42.5171"),
+					BaseCurrency:     "THB",
+					TargetCurrency:   "GBP",
+					Bid:              decPtr("42.004"),
+					Ask:              decPtr("43.0302"),
+					Mid:              decPtr("42.5171"),
+					Quotes:           quotes(entity.RateTypeBuyingSight, "42.004", entity.RateTypeBuyingTransfer, "42.1612", entity.RateTypeSelling, "43.0302", entity.RateTypeMidRate, "42.5171"),
+				},
+			},
+			wantEffectiveDate: "2023-04-14",
+			assertion:         assert.NoError,
+		},
+		{
+			name: "bad json received",
+			args: args{
+				body: []byte(`{"result":`),
+				date: "2023-04-17",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "no rates on or before the date",
+			args: args{
+				body: correctJSON,
+				date: "2023-04-13",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotEffectiveDate, err := ThailandCBRHistoricalResponseToRates(tt.args.body, tt.args.date)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantEffectiveDate, gotEffectiveDate)
+			tt.assertion(t, err)
+		})
+	}
+}
+
+func TestThailandCBRResponseToPublicationDate(t *testing.T) {
+	feed := func(channelDate string, itemDates ...string) []byte {
+		body := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" ` +
+			`xmlns:dc="http://purl.org/dc/elements/1.1/">`
+		if channelDate != "" {
+			body += `<channel><dc:date>` + channelDate + `</dc:date></channel>`
+		}
+		for _, d := range itemDates {
+			body += `<item><dc:date>` + d + `</dc:date></item>`
+		}
+		return []byte(body + `</rdf:RDF>`)
+	}
+	thTZ := time.FixedZone("", 7*60*60)
+	tests := []struct {
+		name            string
+		body            []byte
+		wantDate        string
+		wantPublishedAt time.Time
+		assertion       assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, channel date",
+			body:      feed("2023-04-17", "2023-04-14"),
+			wantDate:  "2023-04-17",
+			assertion: assert.NoError,
+		},
+		{
+			name:            "Happy path, channel timestamp",
+			body:            feed("2023-04-17T18:05:00+07:00"),
+			wantDate:        "2023-04-17",
+			wantPublishedAt: time.Date(2023, 4, 17, 18, 5, 0, 0, thTZ),
+			assertion:       assert.NoError,
+		},
+		{
+			name:      "no channel, the latest item date is used",
+			body:      feed("", "2023-04-14", "2023-04-17", "2023-04-13"),
+			wantDate:  "2023-04-17",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no date",
+			body:      feed(""),
+			wantDate:  "",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			body:      feed("17.04.2023"),
+			wantDate:  "",
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad xml",
+			body:      []byte(`<rdf:RDF`),
+			wantDate:  "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, publishedAt, err := ThailandCBRResponseToPublicationDate(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.wantDate, date)
+			assert.True(t, tt.wantPublishedAt.Equal(publishedAt))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	"my_go/money"
+	"sort"
+)
+
+// BodyToConvertCompareRequest converts the http request body to internal entity.ConvertCompareRequest.
+// Amount is validated the same way as by BodyToConvertCurrencyRequest.
+func BodyToConvertCompareRequest(body []byte) (*entity.ConvertCompareRequest, error) {
+	var r entity.ConvertCompareRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	_, err = requestDate(r.Date)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckConvertCurrencyRequest(compareToConvertCurrencyRequest(&r, ""))
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ConvertCompareRequestToGetExchangeRateRequest converts entity.ConvertCompareRequest to
+// entity.GetExchangeRateRequest to the central bank of the country
+func ConvertCompareRequestToGetExchangeRateRequest(
+	req *entity.ConvertCompareRequest,
+	country string,
+) (*entity.GetExchangeRateRequest, error) {
+	if req == nil {
+		return nil, errors.New("nil ConvertCompareRequest")
+	}
+	return ConvertCurrencyRequestToGetExchangeRateRequest(compareToConvertCurrencyRequest(req, country), country)
+}
+
+// GetExchangeRateResultsToConvertCompareResponse converts the results of the central banks of the countries
+// (in the same order) to entity.ConvertCompareResponse. The banks that failed are listed as unavailable.
+func GetExchangeRateResultsToConvertCompareResponse(
+	countries []string,
+	results []entity.GetExchangeRateResult,
+) (*entity.ConvertCompareResponse, error) {
+	if len(results) != len(countries) {
+		return nil, fmt.Errorf("%d results returned for %d central banks", len(results), len(countries))
+	}
+	res := &entity.ConvertCompareResponse{
+		Results: make([]entity.ConvertCompareResult, 0, len(countries)),
+	}
+	amounts := make([]decimal.Decimal, 0, len(countries))
+	for i, country := range countries {
+		r := results[i]
+		if r.Err != nil || r.Response == nil {
+			res.UnavailableBanks = append(res.UnavailableBanks, country)
+			continue
+		}
+		res.Results = append(res.Results, entity.ConvertCompareResult{
+			Country: country,
+			Date:    r.Response.EffectiveDate,
+			Amount:  r.Response.Amount,
+			Rate:    r.Response.Rate.RateTargetToBase,
+			Stale:   r.Response.Stale,
+		})
+		amounts = append(amounts, r.Response.Amount)
+	}
+	res.Spread = spread(amounts)
+	return res, nil
+}
+
+// ConvertCompareResponseToBytes converts internal entity.ConvertCompareResponse to http response body
+func ConvertCompareResponseToBytes(response *entity.ConvertCompareResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// compareToConvertCurrencyRequest returns the conversion of the compare request with the central bank of the country
+func compareToConvertCurrencyRequest(req *entity.ConvertCompareRequest, country string) *entity.ConvertCurrencyRequest {
+	return &entity.ConvertCurrencyRequest{
+		Country:        &country,
+		Date:           req.Date,
+		SourceCurrency: req.SourceCurrency,
+		TargetCurrency: req.TargetCurrency,
+		Amount:         req.Amount,
+		RoundingMode:   req.RoundingMode,
+		RateType:       req.RateType,
+	}
+}
+
+// spread returns the minimum, the maximum and the median of the amounts, nil if there are no amounts
+func spread(amounts []decimal.Decimal) *entity.ConversionSpread {
+	if len(amounts) == 0 {
+		return nil
+	}
+	sorted := make([]decimal.Decimal, len(amounts))
+	copy(sorted, amounts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = money.Average(sorted[n/2-1], sorted[n/2])
+	}
+	return &entity.ConversionSpread{
+		Min:    sorted[0],
+		Max:    sorted[n-1],
+		Median: median,
+	}
+}
@@ -0,0 +1,263 @@
+package mapper
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"my_go/money"
+	"my_go/utils"
+	"testing"
+)
+
+func TestBodyToConvertCompareRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      *entity.ConvertCompareRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			body: []byte(`{"date":"2023-04-19","source_currency":"USD","target_currency":"EUR","amount":"100.5"}`),
+			want: &entity.ConvertCompareRequest{
+				Date:           utils.ToPointer("2023-04-19"),
+				SourceCurrency: "USD",
+				TargetCurrency: "EUR",
+				Amount:         decPtr("100.5"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			body:      []byte(`{"date":"19.04.2023","source_currency":"USD","target_currency":"EUR","amount":"100"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "missing amount",
+			body:      []byte(`{"source_currency":"USD","target_currency":"EUR","amount":null}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "negative amount",
+			body:      []byte(`{"source_currency":"USD","target_currency":"EUR","amount":"-100"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many decimal places",
+			body:      []byte(`{"source_currency":"JPY","target_currency":"EUR","amount":"100.5"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "unsupported rounding mode",
+			body:      []byte(`{"source_currency":"USD","target_currency":"EUR","amount":"100","rounding_mode":"up"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "unsupported rate type",
+			body:      []byte(`{"source_currency":"USD","target_currency":"EUR","amount":"100","rate_type":"bid"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad json",
+			body:      []byte(`{s"r`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToConvertCompareRequest(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertCompareRequestToGetExchangeRateRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *entity.ConvertCompareRequest
+		country   string
+		want      *entity.GetExchangeRateRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			req: &entity.ConvertCompareRequest{
+				Date:           utils.ToPointer("2023-04-19"),
+				SourceCurrency: "USD",
+				TargetCurrency: "JPY",
+				Amount:         decPtr("100"),
+				RoundingMode:   "down",
+				RateType:       entity.RateTypeMid,
+			},
+			country: "thailand",
+			want: &entity.GetExchangeRateRequest{
+				Country:          "thailand",
+				Date:             "2023-04-19",
+				BaseCurrencyID:   "USD",
+				TargetCurrencyID: "JPY",
+				Amount:           decimal.RequireFromString("100"),
+				Places:           0,
+				Rounding:         money.Down,
+				RateType:         entity.RateTypeMid,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			country:   "russia",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertCompareRequestToGetExchangeRateRequest(tt.req, tt.country)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetExchangeRateResultsToConvertCompareResponse(t *testing.T) {
+	stale := rateResult("USD", "EUR", "0.9131", "91.31", "2023-04-18")
+	stale.Response.Stale = true
+	tests := []struct {
+		name      string
+		countries []string
+		results   []entity.GetExchangeRateResult
+		want      *entity.ConvertCompareResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, odd number of banks",
+			countries: []string{"russia", "thailand", "eurozone", "canada"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+				stale,
+				{Err: errors.New("context deadline exceeded")},
+				rateResult("USD", "EUR", "0.9119", "91.19", "2023-04-19"),
+			},
+			want: &entity.ConvertCompareResponse{
+				Results: []entity.ConvertCompareResult{
+					{
+						Country: "russia",
+						Date:    "2023-04-19",
+						Amount:  decimal.RequireFromString("91.26"),
+						Rate:    decimal.RequireFromString("0.9126"),
+					},
+					{
+						Country: "thailand",
+						Date:    "2023-04-18",
+						Amount:  decimal.RequireFromString("91.31"),
+						Rate:    decimal.RequireFromString("0.9131"),
+						Stale:   true,
+					},
+					{
+						Country: "canada",
+						Date:    "2023-04-19",
+						Amount:  decimal.RequireFromString("91.19"),
+						Rate:    decimal.RequireFromString("0.9119"),
+					},
+				},
+				Spread: &entity.ConversionSpread{
+					Min:    decimal.RequireFromString("91.19"),
+					Max:    decimal.RequireFromString("91.31"),
+					Median: decimal.RequireFromString("91.26"),
+				},
+				UnavailableBanks: []string{"eurozone"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, even number of banks",
+			countries: []string{"russia", "canada"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+				rateResult("USD", "EUR", "0.9119", "91.19", "2023-04-19"),
+			},
+			want: &entity.ConvertCompareResponse{
+				Results: []entity.ConvertCompareResult{
+					{
+						Country: "russia",
+						Date:    "2023-04-19",
+						Amount:  decimal.RequireFromString("91.26"),
+						Rate:    decimal.RequireFromString("0.9126"),
+					},
+					{
+						Country: "canada",
+						Date:    "2023-04-19",
+						Amount:  decimal.RequireFromString("91.19"),
+						Rate:    decimal.RequireFromString("0.9119"),
+					},
+				},
+				Spread: &entity.ConversionSpread{
+					Min:    decimal.RequireFromString("91.19"),
+					Max:    decimal.RequireFromString("91.26"),
+					Median: decimal.RequireFromString("91.225"),
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, no bank converted the pair",
+			countries: []string{"russia", "thailand"},
+			results: []entity.GetExchangeRateResult{
+				{Err: errors.New("currency ABC not supported")},
+				{},
+			},
+			want: &entity.ConvertCompareResponse{
+				Results:          []entity.ConvertCompareResult{},
+				UnavailableBanks: []string{"russia", "thailand"},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "results missing",
+			countries: []string{"russia", "thailand"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExchangeRateResultsToConvertCompareResponse(tt.countries, tt.results)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertCompareResponseToBytes(t *testing.T) {
+	got, err := ConvertCompareResponseToBytes(&entity.ConvertCompareResponse{
+		Results: []entity.ConvertCompareResult{
+			{
+				Country: "russia",
+				Date:    "2023-04-19",
+				Amount:  decimal.RequireFromString("91.26"),
+				Rate:    decimal.RequireFromString("0.9126"),
+			},
+		},
+		Spread: &entity.ConversionSpread{
+			Min:    decimal.RequireFromString("91.26"),
+			Max:    decimal.RequireFromString("91.26"),
+			Median: decimal.RequireFromString("91.26"),
+		},
+		UnavailableBanks: []string{"thailand"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"results":[{"country":"russia","date":"2023-04-19","amount":"91.26","rate":"0.9126"}],`+
+		`"spread":{"min":"91.26","max":"91.26","median":"91.26"},"unavailable_banks":["thailand"]}`, string(got))
+}
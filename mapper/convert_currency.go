@@ -4,20 +4,113 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"my_go/entity"
-	"strconv"
+	"my_go/money"
+	"strings"
 )
 
-// BodyToConvertCurrencyRequest converts the http response body to internal entity.ConvertCurrencyRequest
+// MaxBatchItems is the maximum number of items of the batch conversion request
+const MaxBatchItems = 10000
+
+// BodyToConvertCurrencyRequest converts the http response body to internal entity.ConvertCurrencyRequest.
+// Amount is required, can't be negative and is validated against the minor units of the source currency.
 func BodyToConvertCurrencyRequest(body []byte) (*entity.ConvertCurrencyRequest, error) {
 	var r entity.ConvertCurrencyRequest
 	err := json.Unmarshal(body, &r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal: %s", err)
 	}
+	err = CheckConvertCurrencyRequest(&r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CheckConvertCurrencyRequest validates the amount (required, non-negative and within the minor units of the source
+// currency), the rounding mode and the rate type of entity.ConvertCurrencyRequest
+func CheckConvertCurrencyRequest(r *entity.ConvertCurrencyRequest) error {
+	if r == nil {
+		return errors.New("nil ConvertCurrencyRequest")
+	}
+	err := checkAmount(r.Amount, r.SourceCurrency)
+	if err != nil {
+		return err
+	}
+	_, err = money.ParseRoundingMode(r.RoundingMode)
+	if err != nil {
+		return err
+	}
+	return CheckRateType(r.RateType)
+}
+
+// checkAmount validates that the amount is provided, isn't negative and fits the minor units of the currency
+func checkAmount(amount *decimal.Decimal, currency string) error {
+	if amount == nil {
+		return errors.New("amount is required")
+	}
+	if amount.IsNegative() {
+		return errors.New("amount can't be negative")
+	}
+	err := money.CheckAmount(*amount)
+	if err != nil {
+		return err
+	}
+	return money.CheckMinorUnits(*amount, currency)
+}
+
+// BodyToConvertBatchRequest converts the http response body to internal entity.ConvertBatchRequest.
+// Items are not validated, so the invalid items fail individually.
+func BodyToConvertBatchRequest(body []byte) (*entity.ConvertBatchRequest, error) {
+	var r entity.ConvertBatchRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	if len(r.Items) == 0 {
+		return nil, errors.New("no items provided")
+	}
+	if len(r.Items) > MaxBatchItems {
+		return nil, fmt.Errorf("too many items %d provided, up to %d allowed", len(r.Items), MaxBatchItems)
+	}
 	return &r, nil
 }
 
+// ConvertBatchResponseToBytes converts internal entity.ConvertBatchResponse to http response body
+func ConvertBatchResponseToBytes(response *entity.ConvertBatchResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// GetExchangeRateResultToConvertBatchResult converts the result of the item of the batch at the index
+// to entity.ConvertBatchResult
+func GetExchangeRateResultToConvertBatchResult(index int, r entity.GetExchangeRateResult) entity.ConvertBatchResult {
+	if r.Err != nil {
+		return ConvertBatchError(index, r.Err)
+	}
+	resp, err := GetExchangeRateResponseToConvertCurrencyResponse(r.Response)
+	if err != nil {
+		return ConvertBatchError(index, err)
+	}
+	return entity.ConvertBatchResult{
+		Index:  index,
+		Amount: &resp.Amount,
+		Stale:  resp.Stale,
+	}
+}
+
+// ConvertBatchError returns the result of the failed item of the batch at the index
+func ConvertBatchError(index int, err error) entity.ConvertBatchResult {
+	return entity.ConvertBatchResult{
+		Index: index,
+		Error: err.Error(),
+	}
+}
+
 // ConvertCurrencyResponseToBytes converts internal entity.ConvertCurrencyResponse to http response body
 func ConvertCurrencyResponseToBytes(response *entity.ConvertCurrencyResponse) ([]byte, error) {
 	b, err := json.Marshal(response)
@@ -28,7 +121,8 @@ func ConvertCurrencyResponseToBytes(response *entity.ConvertCurrencyResponse) ([
 }
 
 // CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse converts rates amd get exchange rate request to
-// entity.GetExchangeRateResponse
+// entity.GetExchangeRateResponse. Rate is calculated for 1 unit of base currency, requested amount is converted
+// with exact rate and rounded to requested places.
 func CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(
 	r *entity.ExchangeRates,
 	req *entity.GetExchangeRateRequest,
@@ -39,34 +133,139 @@ func CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(
 	if req == nil {
 		return nil, errors.New("nil GetExchangeRateRequest")
 	}
+	num, den, err := exchangeRateFraction(r, req)
+	if err != nil {
+		return nil, err
+	}
+	return exchangeRateResponse(num, den, req)
+}
+
+// CBRRatesAndPathToGetExchangeRateResponse converts the legs of the path and the rates of the central banks
+// they are calculated with (in the same order) to entity.GetExchangeRateResponse of the request.
+// Rate of every leg is calculated for 1 unit of its base currency, the rate and the amount of the request
+// are calculated with the exact product of the legs, so they are rounded only once.
+func CBRRatesAndPathToGetExchangeRateResponse(
+	rates []*entity.GetCBRatesResponse,
+	path []*entity.GetExchangeRateRequest,
+	req *entity.GetExchangeRateRequest,
+) (*entity.GetExchangeRateResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil GetExchangeRateRequest")
+	}
+	if len(path) == 0 || len(rates) != len(path) {
+		return nil, fmt.Errorf("%d rates provided for path of %d legs", len(rates), len(path))
+	}
+	num, den := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	legs := make([]entity.ExchangeRateLeg, 0, len(path))
+	for i, leg := range path {
+		if rates[i] == nil || rates[i].Rates == nil {
+			return nil, errors.New("nil ExchangeRates")
+		}
+		legNum, legDen, err := exchangeRateFraction(rates[i].Rates, leg)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := money.QuoRate(legNum, legDen)
+		if err != nil {
+			return nil, fmt.Errorf("bad rate of currency %s for CB %s: %s", leg.TargetCurrencyID, leg.Country, err)
+		}
+		legs = append(legs, entity.ExchangeRateLeg{
+			Country:       leg.Country,
+			EffectiveDate: rates[i].Rates.EffectiveDate,
+			Stale:         rates[i].Stale,
+			Rate: entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     leg.BaseCurrencyID,
+				TargetCurrency:   leg.TargetCurrencyID,
+				RateTargetToBase: rate,
+			},
+		})
+		num = num.Mul(legNum)
+		den = den.Mul(legDen)
+	}
+	res, err := exchangeRateResponse(num, den, req)
+	if err != nil {
+		return nil, err
+	}
+	res.EffectiveDate = legs[0].EffectiveDate
+	for _, leg := range legs {
+		res.Stale = res.Stale || leg.Stale
+		if leg.EffectiveDate != res.EffectiveDate {
+			res.EffectiveDate = ""
+		}
+	}
+	res.Path = legs
+	return res, nil
+}
+
+// exchangeRateFraction returns the exact amount of target currency for 1 unit of base currency of the request
+// as a fraction num / den calculated with the rates of the central bank
+func exchangeRateFraction(
+	r *entity.ExchangeRates,
+	req *entity.GetExchangeRateRequest,
+) (num decimal.Decimal, den decimal.Decimal, err error) {
 	targetRate, ok := r.Rates[req.TargetCurrencyID]
 	if !ok {
-		return nil, fmt.Errorf(
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf(
 			"targetRate, currency %s not supported by CB %s", req.TargetCurrencyID, req.Country,
 		)
 	}
 	baseRate, ok := r.Rates[req.BaseCurrencyID]
 	if !ok {
-		return nil, fmt.Errorf("baseRate, currency %s not supported by CB %s", req.BaseCurrencyID, req.Country)
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf(
+			"baseRate, currency %s not supported by CB %s", req.BaseCurrencyID, req.Country,
+		)
+	}
+
+	// (baseValue / targetValue) is calculated with a single division
+	// so the only rounding is the final one, the same applies to the converted amount
+	baseRateType, targetRateType := sideRateTypes(req.RateType)
+	baseNum, baseDen, err := unitValue(baseRate, baseRateType)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("baseRate, %s for CB %s", err, req.Country)
 	}
+	targetNum, targetDen, err := unitValue(targetRate, targetRateType)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("targetRate, %s for CB %s", err, req.Country)
+	}
+	if baseDen.IsZero() || targetDen.IsZero() {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf(
+			"bad rate of currency pair %s/%s for CB %s", req.BaseCurrencyID, req.TargetCurrencyID, req.Country,
+		)
+	}
+	return baseNum.Mul(targetDen), targetNum.Mul(baseDen), nil
+}
 
-	newRawRate := float64(targetRate.Nominal) / targetRate.RateTargetToBase *
-		(baseRate.RateTargetToBase / float64(baseRate.Nominal)) * float64(req.Amount)
-	strRate := fmt.Sprintf("%.4f", newRawRate)
-	rate, _ := strconv.ParseFloat(strRate, 64)
+// exchangeRateResponse returns the rate num / den rounded with money.QuoRate and the amount of the request
+// converted with the exact rate and rounded to the requested places
+func exchangeRateResponse(
+	num decimal.Decimal,
+	den decimal.Decimal,
+	req *entity.GetExchangeRateRequest,
+) (*entity.GetExchangeRateResponse, error) {
+	rate, err := money.QuoRate(num, den)
+	if err != nil {
+		return nil, fmt.Errorf("bad rate of currency %s for CB %s: %s", req.TargetCurrencyID, req.Country, err)
+	}
+	amount, err := money.QuoRound(req.Amount.Mul(num), den, req.Places, req.Rounding)
+	if err != nil {
+		return nil, fmt.Errorf("bad rate of currency %s for CB %s: %s", req.TargetCurrencyID, req.Country, err)
+	}
 
 	return &entity.GetExchangeRateResponse{
 		Rate: entity.Rate{
-			Nominal:          req.Amount,
+			Nominal:          1,
 			BaseCurrency:     req.BaseCurrencyID,
 			TargetCurrency:   req.TargetCurrencyID,
-			RateTargetToBase: rate, // int64 overflow
+			RateTargetToBase: rate,
 		},
+		Amount: amount,
 	}, nil
 }
 
 // ConvertCurrencyRequestToGetExchangeRateRequest converts entity.ConvertCurrencyRequest
 // to entity.GetExchangeRateRequest. defaultCB is used as a fallback if country if not provided.
+// Converted amount is rounded to the minor units of the target currency.
 func ConvertCurrencyRequestToGetExchangeRateRequest(
 	req *entity.ConvertCurrencyRequest,
 	defaultCB string,
@@ -78,11 +277,31 @@ func ConvertCurrencyRequestToGetExchangeRateRequest(
 	if req.Country != nil {
 		country = *req.Country
 	}
+	date, err := requestDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount == nil {
+		return nil, errors.New("amount is required")
+	}
+	rounding, err := money.ParseRoundingMode(req.RoundingMode)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckRateType(req.RateType)
+	if err != nil {
+		return nil, err
+	}
 	return &entity.GetExchangeRateRequest{
 		Country:          country,
+		Date:             date,
 		BaseCurrencyID:   req.SourceCurrency,
 		TargetCurrencyID: req.TargetCurrency,
-		Amount:           req.Amount,
+		Amount:           money.Normalize(*req.Amount),
+		Places:           money.MinorUnits(req.TargetCurrency),
+		Rounding:         rounding,
+		RateType:         req.RateType,
+		Triangulate:      req.Triangulate,
 	}, nil
 }
 
@@ -94,7 +313,109 @@ func GetExchangeRateResponseToConvertCurrencyResponse(
 	if r == nil {
 		return nil, fmt.Errorf("nil Rate")
 	}
+	var path []entity.ConversionLeg
+	for _, leg := range r.Path {
+		path = append(path, entity.ConversionLeg{
+			Country:        leg.Country,
+			Date:           leg.EffectiveDate,
+			SourceCurrency: leg.Rate.BaseCurrency,
+			TargetCurrency: leg.Rate.TargetCurrency,
+			Rate:           leg.Rate.RateTargetToBase,
+			Stale:          leg.Stale,
+		})
+	}
 	return &entity.ConvertCurrencyResponse{
-		Amount: r.Rate.RateTargetToBase,
+		Amount: r.Amount,
+		Stale:  r.Stale,
+		Path:   path,
 	}, nil
 }
+
+// unitValue returns the value of 1 unit of the rate target currency in the bank base currency
+// as a fraction num / den, so the direction of the quote doesn't introduce extra rounding.
+// The quote of the rate is selected by rateType.
+func unitValue(r entity.Rate, rateType string) (num decimal.Decimal, den decimal.Decimal, err error) {
+	value, err := quote(r, rateType)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	nominal := decimal.NewFromInt(int64(r.Nominal))
+	if r.Inverse {
+		return nominal, value, nil
+	}
+	return value, nominal, nil
+}
+
+// sideRateTypes returns the rate types of the base and the target currency of the conversion with the rate type.
+// The bank buying the base currency sells the target one, so buy applies the bid to the base currency and the ask
+// to the target one and sell is the reverse. Other rate types are applied to both currencies.
+func sideRateTypes(rateType string) (base string, target string) {
+	switch rateType {
+	case entity.RateTypeBuy:
+		return entity.RateTypeBuy, entity.RateTypeSell
+	case entity.RateTypeSell:
+		return entity.RateTypeSell, entity.RateTypeBuy
+	}
+	return rateType, rateType
+}
+
+// quote returns the quote of the rate of the provided type. Empty type means the reference rate,
+// mid falls back to the reference rate if the bank publishes no separate quotes.
+// The types of the quotes catalogue are taken from the quotes of the rate.
+// The rate of the base currency to itself is the same for all the types.
+func quote(r entity.Rate, rateType string) (decimal.Decimal, error) {
+	var q *decimal.Decimal
+	switch rateType {
+	case "":
+		return r.RateTargetToBase, nil
+	case entity.RateTypeMid:
+		if r.Mid == nil {
+			return r.RateTargetToBase, nil
+		}
+		q = r.Mid
+	case entity.RateTypeBuy:
+		q = r.Bid
+	case entity.RateTypeSell:
+		q = r.Ask
+	default:
+		if !isQuoteRateType(rateType) {
+			return decimal.Decimal{}, fmt.Errorf("unsupported rate type %s", rateType)
+		}
+		if v, ok := r.Quotes[rateType]; ok {
+			q = &v
+		}
+	}
+	if q == nil {
+		if r.BaseCurrency == r.TargetCurrency {
+			return r.RateTargetToBase, nil
+		}
+		return decimal.Decimal{}, fmt.Errorf("%s rate of currency %s is not published", rateType, r.TargetCurrency)
+	}
+	return *q, nil
+}
+
+// CheckRateType validates optional rate type provided in the external API request or in the config
+func CheckRateType(rateType string) error {
+	switch rateType {
+	case "", entity.RateTypeBuy, entity.RateTypeSell, entity.RateTypeMid:
+		return nil
+	}
+	if isQuoteRateType(rateType) {
+		return nil
+	}
+	return fmt.Errorf(
+		"unsupported rate_type %s, expected one of %s, %s, %s, %s",
+		rateType, entity.RateTypeBuy, entity.RateTypeSell, entity.RateTypeMid,
+		strings.Join(entity.QuoteRateTypes, ", "),
+	)
+}
+
+// isQuoteRateType checks whether the rate type belongs to the quotes catalogue
+func isQuoteRateType(rateType string) bool {
+	for _, t := range entity.QuoteRateTypes {
+		if t == rateType {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,90 @@
 package mapper
 
 import (
+	"errors"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
+	"my_go/money"
 	"my_go/utils"
+	"strings"
 	"testing"
 	"time"
 )
 
+func decPtr(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
 func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T) {
 	thaiTZ, _ := time.LoadLocation("Asia/Bangkok")
+	thaiQuotes := &entity.ExchangeRates{
+		Country:    "thailand",
+		DateLoaded: "2023-04-17",
+		TimeZone:   thaiTZ,
+		Rates: map[string]entity.Rate{
+			"THB": {
+				Nominal:          1,
+				BaseCurrency:     "THB",
+				TargetCurrency:   "THB",
+				RateTargetToBase: decimal.RequireFromString("1"),
+			},
+			"USD": {
+				Nominal:          1,
+				BaseCurrency:     "THB",
+				TargetCurrency:   "USD",
+				RateTargetToBase: decimal.RequireFromString("34.5"),
+				Bid:              decPtr("34"),
+				Ask:              decPtr("35"),
+				Mid:              decPtr("34.5"),
+				Quotes: map[string]decimal.Decimal{
+					entity.RateTypeBuyingSight:    decimal.RequireFromString("34"),
+					entity.RateTypeBuyingTransfer: decimal.RequireFromString("34.2"),
+					entity.RateTypeSelling:        decimal.RequireFromString("35"),
+				},
+			},
+			"VND": {
+				Nominal:          1,
+				BaseCurrency:     "THB",
+				TargetCurrency:   "VND",
+				RateTargetToBase: decimal.RequireFromString("0.0014"),
+				Bid:              decPtr("0.0014"),
+			},
+			"EUR": {
+				Nominal:          1,
+				BaseCurrency:     "THB",
+				TargetCurrency:   "EUR",
+				RateTargetToBase: decimal.RequireFromString("37.5"),
+				Bid:              decPtr("37"),
+				Ask:              decPtr("38"),
+			},
+		},
+	}
+	thaiQuoteRequest := func(base string, target string, rateType string) *entity.GetExchangeRateRequest {
+		return &entity.GetExchangeRateRequest{
+			Country:          "thailand",
+			BaseCurrencyID:   base,
+			TargetCurrencyID: target,
+			Amount:           decimal.RequireFromString("100"),
+			Places:           2,
+			RateType:         rateType,
+		}
+	}
+	thaiPairResponse := func(base string, target string, rate string, amount string) *entity.GetExchangeRateResponse {
+		return &entity.GetExchangeRateResponse{
+			Rate: entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     base,
+				TargetCurrency:   target,
+				RateTargetToBase: decimal.RequireFromString(rate),
+			},
+			Amount: decimal.RequireFromString(amount),
+		}
+	}
+	thaiQuoteResponse := func(rate string, amount string) *entity.GetExchangeRateResponse {
+		return thaiPairResponse("USD", "THB", rate, amount)
+	}
 	type args struct {
 		r   *entity.ExchangeRates
 		req *entity.GetExchangeRateRequest
@@ -32,13 +107,13 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 							Nominal:          1,
 							BaseCurrency:     "THB",
 							TargetCurrency:   "USD",
-							RateTargetToBase: 34.079000,
+							RateTargetToBase: decimal.RequireFromString("34.079"),
 						},
 						"JPY": {
 							Nominal:          100,
 							BaseCurrency:     "THB",
 							TargetCurrency:   "JPY",
-							RateTargetToBase: 25.159600,
+							RateTargetToBase: decimal.RequireFromString("25.1596"),
 						},
 					},
 				},
@@ -46,19 +121,274 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 					Country:          "thailand",
 					BaseCurrencyID:   "USD",
 					TargetCurrencyID: "JPY",
-					Amount:           25,
+					Amount:           decimal.RequireFromString("25.5"),
+					Places:           0,
 				},
 			},
 			want: &entity.GetExchangeRateResponse{
 				Rate: entity.Rate{
-					Nominal:          25,
+					Nominal:          1,
 					BaseCurrency:     "USD",
 					TargetCurrency:   "JPY",
-					RateTargetToBase: 3386.282,
+					RateTargetToBase: decimal.RequireFromString("135.4513"),
+				},
+				Amount: decimal.RequireFromString("3454"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, buy rate type",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "buy")},
+			want:      thaiQuoteResponse("34", "3400"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, sell rate type",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "sell")},
+			want:      thaiQuoteResponse("35", "3500"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, buy rate type, bank sells the target currency at ask",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("THB", "USD", "buy")},
+			want:      thaiPairResponse("THB", "USD", "0.0285714", "2.86"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, buy rate type of cross pair, bid of base currency and ask of target currency",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "EUR", "buy")},
+			want:      thaiPairResponse("USD", "EUR", "0.894737", "89.47"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, sell rate type of cross pair, ask of base currency and bid of target currency",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "EUR", "sell")},
+			want:      thaiPairResponse("USD", "EUR", "0.945946", "94.59"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, mid rate type",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "mid")},
+			want:      thaiQuoteResponse("34.5", "3450"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy Path, buying transfer rate type",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "buying_transfer")},
+			want:      thaiQuoteResponse("34.2", "3420"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "mid rate of the bank not published",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "mid_rate")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "sell rate of target currency not published",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "VND", "buy")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "sell rate of base currency not published",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("VND", "USD", "sell")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "unsupported rate type",
+			args:      args{r: thaiQuotes, req: thaiQuoteRequest("USD", "THB", "bid")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "Happy Path, rate tie rounded half to even, amount rounded half up",
+			args: args{
+				r: &entity.ExchangeRates{
+					Country:    "thailand",
+					DateLoaded: "2022-01-01",
+					TimeZone:   thaiTZ,
+					Rates: map[string]entity.Rate{
+						"THB": {
+							Nominal:          1,
+							BaseCurrency:     "THB",
+							TargetCurrency:   "THB",
+							RateTargetToBase: decimal.RequireFromString("1"),
+						},
+						"IDR": {
+							Nominal:          1000,
+							BaseCurrency:     "THB",
+							TargetCurrency:   "IDR",
+							RateTargetToBase: decimal.RequireFromString("1.234565"),
+						},
+					},
+				},
+				req: &entity.GetExchangeRateRequest{
+					Country:          "thailand",
+					BaseCurrencyID:   "IDR",
+					TargetCurrencyID: "THB",
+					Amount:           decimal.RequireFromString("1"),
+					Places:           8,
+					Rounding:         money.HalfUp,
+				},
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "IDR",
+					TargetCurrency:   "THB",
+					RateTargetToBase: decimal.RequireFromString("0.00123456"),
+				},
+				Amount: decimal.RequireFromString("0.00123457"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy Path, inverse rates",
+			args: args{
+				r: &entity.ExchangeRates{
+					Country:    "eurozone",
+					DateLoaded: "2023-04-19",
+					Rates: map[string]entity.Rate{
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "EUR",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("1.0958"),
+							Inverse:          true,
+						},
+						"JPY": {
+							Nominal:          1,
+							BaseCurrency:     "EUR",
+							TargetCurrency:   "JPY",
+							RateTargetToBase: decimal.RequireFromString("145.53"),
+							Inverse:          true,
+						},
+					},
+				},
+				req: &entity.GetExchangeRateRequest{
+					Country:          "eurozone",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "JPY",
+					Amount:           decimal.RequireFromString("100"),
+				},
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "USD",
+					TargetCurrency:   "JPY",
+					RateTargetToBase: decimal.RequireFromString("132.8071"),
+				},
+				Amount: decimal.RequireFromString("13281"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy Path, inverse rate to base currency",
+			args: args{
+				r: &entity.ExchangeRates{
+					Country:    "eurozone",
+					DateLoaded: "2023-04-19",
+					Rates: map[string]entity.Rate{
+						"EUR": {
+							Nominal:          1,
+							BaseCurrency:     "EUR",
+							TargetCurrency:   "EUR",
+							RateTargetToBase: decimal.RequireFromString("1"),
+						},
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "EUR",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("1.0958"),
+							Inverse:          true,
+						},
+					},
+				},
+				req: &entity.GetExchangeRateRequest{
+					Country:          "eurozone",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "EUR",
+					Amount:           decimal.RequireFromString("19.99"),
+					Places:           2,
+				},
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "USD",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("0.912575"),
 				},
+				Amount: decimal.RequireFromString("18.24"),
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "zero inverse rate",
+			args: args{
+				r: &entity.ExchangeRates{
+					Country:    "eurozone",
+					DateLoaded: "2023-04-19",
+					Rates: map[string]entity.Rate{
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "EUR",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("1.0958"),
+							Inverse:          true,
+						},
+						"JPY": {
+							Nominal:        1,
+							BaseCurrency:   "EUR",
+							TargetCurrency: "JPY",
+							Inverse:        true,
+						},
+					},
+				},
+				req: &entity.GetExchangeRateRequest{
+					Country:          "eurozone",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "JPY",
+					Amount:           decimal.RequireFromString("100"),
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "zero target rate",
+			args: args{
+				r: &entity.ExchangeRates{
+					Country:    "thailand",
+					DateLoaded: "2022-01-01",
+					TimeZone:   thaiTZ,
+					Rates: map[string]entity.Rate{
+						"USD": {
+							Nominal:          1,
+							BaseCurrency:     "THB",
+							TargetCurrency:   "USD",
+							RateTargetToBase: decimal.RequireFromString("34.079"),
+						},
+						"JPY": {
+							Nominal:        100,
+							BaseCurrency:   "THB",
+							TargetCurrency: "JPY",
+						},
+					},
+				},
+				req: &entity.GetExchangeRateRequest{
+					Country:          "thailand",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "JPY",
+					Amount:           decimal.RequireFromString("25"),
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 		{
 			name: "nil request",
 			args: args{
@@ -67,7 +397,7 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 					Country:          "thailand",
 					BaseCurrencyID:   "USD",
 					TargetCurrencyID: "JPY",
-					Amount:           25,
+					Amount:           decimal.RequireFromString("25"),
 				},
 			},
 			want:      nil,
@@ -95,7 +425,7 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 							Nominal:          100,
 							BaseCurrency:     "THB",
 							TargetCurrency:   "JPY",
-							RateTargetToBase: 25.159600,
+							RateTargetToBase: decimal.RequireFromString("25.1596"),
 						},
 					},
 				},
@@ -103,7 +433,7 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 					Country:          "thailand",
 					BaseCurrencyID:   "USD",
 					TargetCurrencyID: "JPY",
-					Amount:           25,
+					Amount:           decimal.RequireFromString("25"),
 				},
 			},
 			want:      nil,
@@ -121,7 +451,7 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 							Nominal:          1,
 							BaseCurrency:     "THB",
 							TargetCurrency:   "USD",
-							RateTargetToBase: 34.079000,
+							RateTargetToBase: decimal.RequireFromString("34.079"),
 						},
 					},
 				},
@@ -129,7 +459,7 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 					Country:          "thailand",
 					BaseCurrencyID:   "USD",
 					TargetCurrencyID: "JPY",
-					Amount:           25,
+					Amount:           decimal.RequireFromString("25"),
 				},
 			},
 			want:      nil,
@@ -145,6 +475,142 @@ func TestCBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(t *testing.T
 	}
 }
 
+func TestCBRRatesAndPathToGetExchangeRateResponse(t *testing.T) {
+	russia := &entity.GetCBRatesResponse{
+		Rates: &entity.ExchangeRates{
+			Country:       "russia",
+			EffectiveDate: "2023-04-19",
+			Rates: map[string]entity.Rate{
+				"RUB": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "RUB", RateTargetToBase: decimal.RequireFromString("1")},
+				"EUR": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("89.3699")},
+			},
+		},
+	}
+	eurozone := &entity.GetCBRatesResponse{
+		Rates: &entity.ExchangeRates{
+			Country:       "eurozone",
+			EffectiveDate: "2023-04-19",
+			Rates: map[string]entity.Rate{
+				"EUR": {Nominal: 1, BaseCurrency: "EUR", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("1")},
+				"ISK": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "ISK",
+					RateTargetToBase: decimal.RequireFromString("150.3"),
+					Inverse:          true,
+				},
+			},
+		},
+		Stale: true,
+	}
+	path := []*entity.GetExchangeRateRequest{
+		{Country: "russia", BaseCurrencyID: "RUB", TargetCurrencyID: "EUR"},
+		{Country: "eurozone", BaseCurrencyID: "EUR", TargetCurrencyID: "ISK"},
+	}
+	req := &entity.GetExchangeRateRequest{
+		Country:          "russia",
+		BaseCurrencyID:   "RUB",
+		TargetCurrencyID: "ISK",
+		Amount:           decimal.RequireFromString("1000"),
+		Places:           0,
+		Triangulate:      true,
+	}
+	rate := func(base string, target string, value string) entity.Rate {
+		return entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     base,
+			TargetCurrency:   target,
+			RateTargetToBase: decimal.RequireFromString(value),
+		}
+	}
+	tests := []struct {
+		name      string
+		rates     []*entity.GetCBRatesResponse
+		path      []*entity.GetExchangeRateRequest
+		req       *entity.GetExchangeRateRequest
+		want      *entity.GetExchangeRateResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:  "Happy path, amount converted with the exact product of the legs",
+			rates: []*entity.GetCBRatesResponse{russia, eurozone},
+			path:  path,
+			req:   req,
+			want: &entity.GetExchangeRateResponse{
+				Rate:          rate("RUB", "ISK", "1.68177"),
+				Amount:        decimal.RequireFromString("1682"),
+				Stale:         true,
+				EffectiveDate: "2023-04-19",
+				Path: []entity.ExchangeRateLeg{
+					{Country: "russia", EffectiveDate: "2023-04-19", Rate: rate("RUB", "EUR", "0.0111894")},
+					{Country: "eurozone", EffectiveDate: "2023-04-19", Stale: true, Rate: rate("EUR", "ISK", "150.3")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, legs of different dates",
+			rates: []*entity.GetCBRatesResponse{russia, {
+				Rates: &entity.ExchangeRates{
+					Country:       "eurozone",
+					EffectiveDate: "2023-04-18",
+					Rates:         eurozone.Rates.Rates,
+				},
+			}},
+			path: path,
+			req:  req,
+			want: &entity.GetExchangeRateResponse{
+				Rate:   rate("RUB", "ISK", "1.68177"),
+				Amount: decimal.RequireFromString("1682"),
+				Path: []entity.ExchangeRateLeg{
+					{Country: "russia", EffectiveDate: "2023-04-19", Rate: rate("RUB", "EUR", "0.0111894")},
+					{Country: "eurozone", EffectiveDate: "2023-04-18", Rate: rate("EUR", "ISK", "150.3")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "currency of the leg not supported",
+			rates:     []*entity.GetCBRatesResponse{russia, russia},
+			path:      path,
+			req:       req,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "rates missing for the leg",
+			rates:     []*entity.GetCBRatesResponse{russia},
+			path:      path,
+			req:       req,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil rates",
+			rates:     []*entity.GetCBRatesResponse{russia, {}},
+			path:      path,
+			req:       req,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			rates:     []*entity.GetCBRatesResponse{russia, eurozone},
+			path:      path,
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CBRRatesAndPathToGetExchangeRateResponse(tt.rates, tt.path, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestBodyToConvertCurrencyRequest(t *testing.T) {
 	correctJSON := []byte(`{"country":"russia","source_currency":"RUB","target_currency":"USD","amount":100}`)
 	type args struct {
@@ -165,10 +631,137 @@ func TestBodyToConvertCurrencyRequest(t *testing.T) {
 				Country:        utils.ToPointer("russia"),
 				SourceCurrency: "RUB",
 				TargetCurrency: "USD",
-				Amount:         100,
+				Amount:         decPtr("100"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, fractional amount as decimal string, rounding mode",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":"19.99","rounding_mode":"half_up"}`),
+			},
+			want: &entity.ConvertCurrencyRequest{
+				SourceCurrency: "USD",
+				TargetCurrency: "RUB",
+				Amount:         decPtr("19.99"),
+				RoundingMode:   "half_up",
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "Happy path, large amount as number",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":123456789012345678901234567890.5}`),
+			},
+			want: &entity.ConvertCurrencyRequest{
+				SourceCurrency: "USD",
+				TargetCurrency: "RUB",
+				Amount:         decPtr("123456789012345678901234567890.5"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, zero amount",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":0}`),
+			},
+			want: &entity.ConvertCurrencyRequest{
+				SourceCurrency: "USD",
+				TargetCurrency: "RUB",
+				Amount:         decPtr("0"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "more decimal places than minor units of source currency",
+			args: args{
+				body: []byte(`{"source_currency":"JPY","target_currency":"RUB","amount":"100.5"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "missing amount",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "negative amount",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":"-1"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "amount with huge exponent",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":"1e5000000"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "too many digits of amount as number",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":123456789012345678901234567890123456789}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad amount",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":"12,5"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad rounding mode",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"RUB","amount":"12","rounding_mode":"up"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "Happy path, rate type",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"THB","amount":"100","rate_type":"sell"}`),
+			},
+			want: &entity.ConvertCurrencyRequest{
+				SourceCurrency: "USD",
+				TargetCurrency: "THB",
+				Amount:         decPtr("100"),
+				RateType:       "sell",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, rate type of the quotes catalogue",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"THB","amount":"100","rate_type":"buying_sight"}`),
+			},
+			want: &entity.ConvertCurrencyRequest{
+				SourceCurrency: "USD",
+				TargetCurrency: "THB",
+				Amount:         decPtr("100"),
+				RateType:       "buying_sight",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "bad rate type",
+			args: args{
+				body: []byte(`{"source_currency":"USD","target_currency":"THB","amount":"100","rate_type":"ask"}`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 		{
 			name: "bad json",
 			args: args{
@@ -201,10 +794,10 @@ func TestConvertCurrencyResponseToBytes(t *testing.T) {
 			name: "Happy path",
 			args: args{
 				response: &entity.ConvertCurrencyResponse{
-					Amount: 123.34,
+					Amount: decimal.RequireFromString("123.34"),
 				},
 			},
-			want:      []byte(`{"amount":123.34}`),
+			want:      []byte(`{"amount":"123.34"}`),
 			assertion: assert.NoError,
 		},
 		{
@@ -230,13 +823,13 @@ func TestConvertCurrencyRequestToGetExchangeRateRequest(t *testing.T) {
 		Country:        utils.ToPointer("thailand"),
 		SourceCurrency: "JPY",
 		TargetCurrency: "USD",
-		Amount:         12,
+		Amount:         decPtr("12"),
 	}
 	requestWithoutCountry := &entity.ConvertCurrencyRequest{
 		Country:        nil,
 		SourceCurrency: "JPY",
 		TargetCurrency: "USD",
-		Amount:         12,
+		Amount:         decPtr("12"),
 	}
 	type args struct {
 		req       *entity.ConvertCurrencyRequest
@@ -258,7 +851,9 @@ func TestConvertCurrencyRequestToGetExchangeRateRequest(t *testing.T) {
 				Country:          "thailand",
 				BaseCurrencyID:   "JPY",
 				TargetCurrencyID: "USD",
-				Amount:           12,
+				Amount:           decimal.RequireFromString("12"),
+				Places:           2,
+				Rounding:         money.HalfEven,
 			},
 			assertion: assert.NoError,
 		},
@@ -272,10 +867,141 @@ func TestConvertCurrencyRequestToGetExchangeRateRequest(t *testing.T) {
 				Country:          "russia",
 				BaseCurrencyID:   "JPY",
 				TargetCurrencyID: "USD",
-				Amount:           12,
+				Amount:           decimal.RequireFromString("12"),
+				Places:           2,
+				Rounding:         money.HalfEven,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, triangulation requested",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					SourceCurrency: "JPY",
+					TargetCurrency: "USD",
+					Amount:         decPtr("12"),
+					Triangulate:    true,
+				},
+				defaultCB: "russia",
+			},
+			want: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "JPY",
+				TargetCurrencyID: "USD",
+				Amount:           decimal.RequireFromString("12"),
+				Places:           2,
+				Rounding:         money.HalfEven,
+				Triangulate:      true,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, date provided",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					Date:           utils.ToPointer("2023-04-17"),
+					SourceCurrency: "JPY",
+					TargetCurrency: "USD",
+					Amount:         decPtr("12"),
+				},
+				defaultCB: "russia",
+			},
+			want: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				Date:             "2023-04-17",
+				BaseCurrencyID:   "JPY",
+				TargetCurrencyID: "USD",
+				Amount:           decimal.RequireFromString("12"),
+				Places:           2,
+				Rounding:         money.HalfEven,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, target currency without minor units, rounding mode provided",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					SourceCurrency: "USD",
+					TargetCurrency: "JPY",
+					Amount:         decPtr("19.990"),
+					RoundingMode:   "down",
+				},
+				defaultCB: "russia",
+			},
+			want: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "USD",
+				TargetCurrencyID: "JPY",
+				Amount:           decimal.RequireFromString("19.99"),
+				Places:           0,
+				Rounding:         money.Down,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, rate type provided",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					SourceCurrency: "USD",
+					TargetCurrency: "THB",
+					Amount:         decPtr("100"),
+					RateType:       "buy",
+				},
+				defaultCB: "thailand",
+			},
+			want: &entity.GetExchangeRateRequest{
+				Country:          "thailand",
+				BaseCurrencyID:   "USD",
+				TargetCurrencyID: "THB",
+				Amount:           decimal.RequireFromString("100"),
+				Places:           2,
+				Rounding:         money.HalfEven,
+				RateType:         "buy",
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "bad rate type",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					SourceCurrency: "USD",
+					TargetCurrency: "THB",
+					Amount:         decPtr("100"),
+					RateType:       "ask",
+				},
+				defaultCB: "thailand",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad rounding mode",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					SourceCurrency: "JPY",
+					TargetCurrency: "USD",
+					Amount:         decPtr("12"),
+					RoundingMode:   "up",
+				},
+				defaultCB: "russia",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad date format",
+			args: args{
+				req: &entity.ConvertCurrencyRequest{
+					Date:           utils.ToPointer("17.04.2023"),
+					SourceCurrency: "JPY",
+					TargetCurrency: "USD",
+					Amount:         decPtr("12"),
+				},
+				defaultCB: "russia",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -288,10 +1014,10 @@ func TestConvertCurrencyRequestToGetExchangeRateRequest(t *testing.T) {
 
 func TestGetCBRRateToConvertCurrencyResponse(t *testing.T) {
 	rate := entity.Rate{
-		Nominal:          100,
+		Nominal:          1,
 		BaseCurrency:     "RUB",
 		TargetCurrency:   "USD",
-		RateTargetToBase: 123.56,
+		RateTargetToBase: decimal.RequireFromString("1.2356"),
 	}
 	type args struct {
 		r *entity.GetExchangeRateResponse
@@ -306,11 +1032,82 @@ func TestGetCBRRateToConvertCurrencyResponse(t *testing.T) {
 			name: "Happy path",
 			args: args{
 				r: &entity.GetExchangeRateResponse{
-					Rate: rate,
+					Rate:   rate,
+					Amount: decimal.RequireFromString("123.56"),
+				},
+			},
+			want: &entity.ConvertCurrencyResponse{
+				Amount: decimal.RequireFromString("123.56"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, stale rate",
+			args: args{
+				r: &entity.GetExchangeRateResponse{
+					Rate:   rate,
+					Amount: decimal.RequireFromString("123.56"),
+					Stale:  true,
 				},
 			},
 			want: &entity.ConvertCurrencyResponse{
-				Amount: 123.56,
+				Amount: decimal.RequireFromString("123.56"),
+				Stale:  true,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, triangulated",
+			args: args{
+				r: &entity.GetExchangeRateResponse{
+					Rate:   rate,
+					Amount: decimal.RequireFromString("123.56"),
+					Stale:  true,
+					Path: []entity.ExchangeRateLeg{
+						{
+							Country:       "russia",
+							EffectiveDate: "2023-04-19",
+							Rate: entity.Rate{
+								Nominal:          1,
+								BaseCurrency:     "JPY",
+								TargetCurrency:   "EUR",
+								RateTargetToBase: decimal.RequireFromString("0.0068"),
+							},
+						},
+						{
+							Country:       "eurozone",
+							EffectiveDate: "2023-04-18",
+							Stale:         true,
+							Rate: entity.Rate{
+								Nominal:          1,
+								BaseCurrency:     "EUR",
+								TargetCurrency:   "USD",
+								RateTargetToBase: decimal.RequireFromString("1.0958"),
+							},
+						},
+					},
+				},
+			},
+			want: &entity.ConvertCurrencyResponse{
+				Amount: decimal.RequireFromString("123.56"),
+				Stale:  true,
+				Path: []entity.ConversionLeg{
+					{
+						Country:        "russia",
+						Date:           "2023-04-19",
+						SourceCurrency: "JPY",
+						TargetCurrency: "EUR",
+						Rate:           decimal.RequireFromString("0.0068"),
+					},
+					{
+						Country:        "eurozone",
+						Date:           "2023-04-18",
+						SourceCurrency: "EUR",
+						TargetCurrency: "USD",
+						Rate:           decimal.RequireFromString("1.0958"),
+						Stale:          true,
+					},
+				},
 			},
 			assertion: assert.NoError,
 		},
@@ -331,3 +1128,156 @@ func TestGetCBRRateToConvertCurrencyResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestBodyToConvertBatchRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      *entity.ConvertBatchRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, invalid items kept",
+			body: []byte(`{"items":[{"country":"russia","source_currency":"USD","target_currency":"RUB","amount":"10.5"},` +
+				`{"source_currency":"JPY","target_currency":"RUB","amount":"1.5","rate_type":"best"}]}`),
+			want: &entity.ConvertBatchRequest{
+				Items: []entity.ConvertCurrencyRequest{
+					{
+						Country:        utils.ToPointer("russia"),
+						SourceCurrency: "USD",
+						TargetCurrency: "RUB",
+						Amount:         decPtr("10.5"),
+					},
+					{
+						SourceCurrency: "JPY",
+						TargetCurrency: "RUB",
+						Amount:         decPtr("1.5"),
+						RateType:       "best",
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no items",
+			body:      []byte(`{"items":[]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad json",
+			body:      []byte(`{"items":[{"amount":"abc"}]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many items",
+			body:      []byte(`{"items":[` + strings.Repeat(`{},`, MaxBatchItems) + `{}]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToConvertBatchRequest(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckConvertCurrencyRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *entity.ConvertCurrencyRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD", Amount: decPtr("1.25")},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "missing amount",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD"},
+			assertion: assert.Error,
+		},
+		{
+			name:      "negative amount",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD", Amount: decPtr("-0.01")},
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many decimal places",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "JPY", Amount: decPtr("1.5")},
+			assertion: assert.Error,
+		},
+		{
+			name:      "amount with huge exponent",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD", Amount: decPtr("1e5000000")},
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad rounding mode",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD", Amount: decPtr("1"), RoundingMode: "ceiling"},
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad rate type",
+			req:       &entity.ConvertCurrencyRequest{SourceCurrency: "USD", Amount: decPtr("1"), RateType: "best"},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion(t, CheckConvertCurrencyRequest(tt.req))
+		})
+	}
+}
+
+func TestGetExchangeRateResultToConvertBatchResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result entity.GetExchangeRateResult
+		want   entity.ConvertBatchResult
+	}{
+		{
+			name: "Happy path",
+			result: entity.GetExchangeRateResult{
+				Response: &entity.GetExchangeRateResponse{Amount: decimal.RequireFromString("12.4"), Stale: true},
+			},
+			want: entity.ConvertBatchResult{Index: 3, Amount: decPtr("12.4"), Stale: true},
+		},
+		{
+			name:   "item failed",
+			result: entity.GetExchangeRateResult{Err: errors.New("currency ABC not supported")},
+			want:   entity.ConvertBatchResult{Index: 3, Error: "currency ABC not supported"},
+		},
+		{
+			name:   "no response and no error",
+			result: entity.GetExchangeRateResult{},
+			want:   entity.ConvertBatchResult{Index: 3, Error: "nil Rate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetExchangeRateResultToConvertBatchResult(3, tt.result))
+		})
+	}
+}
+
+func TestConvertBatchResponseToBytes(t *testing.T) {
+	got, err := ConvertBatchResponseToBytes(&entity.ConvertBatchResponse{
+		Results: []entity.ConvertBatchResult{
+			{Index: 0, Amount: decPtr("12.40")},
+			{Index: 1, Error: "currency ABC not supported"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"results":[{"index":0,"amount":"12.4"},{"index":1,"error":"currency ABC not supported"}]}`, string(got))
+}
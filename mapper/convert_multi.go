@@ -0,0 +1,242 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	"my_go/money"
+)
+
+const (
+	// MaxTargetCurrencies is the maximum number of target currencies of the multi-target conversion request
+	MaxTargetCurrencies = 50
+	// MaxMatrixCurrencies is the maximum number of currencies of the rate matrix request
+	MaxMatrixCurrencies = 50
+)
+
+// BodyToConvertMultiRequest converts the http request body to internal entity.ConvertMultiRequest.
+// Amount is validated the same way as by BodyToConvertCurrencyRequest, duplicated target currencies are dropped.
+func BodyToConvertMultiRequest(body []byte) (*entity.ConvertMultiRequest, error) {
+	var r entity.ConvertMultiRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	r.TargetCurrencies = unique(r.TargetCurrencies)
+	if len(r.TargetCurrencies) == 0 {
+		return nil, errors.New("no target currencies provided")
+	}
+	if len(r.TargetCurrencies) > MaxTargetCurrencies {
+		return nil, fmt.Errorf(
+			"too many target currencies %d provided, up to %d allowed", len(r.TargetCurrencies), MaxTargetCurrencies,
+		)
+	}
+	err = checkAmount(r.Amount, r.SourceCurrency)
+	if err != nil {
+		return nil, err
+	}
+	_, err = money.ParseRoundingMode(r.RoundingMode)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckRateType(r.RateType)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ConvertMultiRequestToGetExchangeRateRequests converts entity.ConvertMultiRequest to entity.GetExchangeRateRequest
+// for every target currency in the order of the request. defaultCB is used as a fallback if country is not provided.
+func ConvertMultiRequestToGetExchangeRateRequests(
+	req *entity.ConvertMultiRequest,
+	defaultCB string,
+) ([]*entity.GetExchangeRateRequest, error) {
+	if req == nil {
+		return nil, errors.New("nil ConvertMultiRequest")
+	}
+	reqs := make([]*entity.GetExchangeRateRequest, 0, len(req.TargetCurrencies))
+	for _, target := range req.TargetCurrencies {
+		r, err := ConvertCurrencyRequestToGetExchangeRateRequest(&entity.ConvertCurrencyRequest{
+			Country:        req.Country,
+			Date:           req.Date,
+			SourceCurrency: req.SourceCurrency,
+			TargetCurrency: target,
+			Amount:         req.Amount,
+			RoundingMode:   req.RoundingMode,
+			RateType:       req.RateType,
+		}, defaultCB)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, r)
+	}
+	return reqs, nil
+}
+
+// GetExchangeRateResultsToConvertMultiResponse converts the results of the requests made by
+// ConvertMultiRequestToGetExchangeRateRequests to entity.ConvertMultiResponse.
+// Conversion fails if any of the target currencies fails.
+func GetExchangeRateResultsToConvertMultiResponse(
+	results []entity.GetExchangeRateResult,
+) (*entity.ConvertMultiResponse, error) {
+	res := &entity.ConvertMultiResponse{
+		Amounts: make(map[string]decimal.Decimal, len(results)),
+		Rates:   make(map[string]decimal.Decimal, len(results)),
+	}
+	for _, r := range results {
+		resp, err := exchangeRateResult(r, res.Date)
+		if err != nil {
+			return nil, err
+		}
+		res.Date = resp.EffectiveDate
+		res.Stale = res.Stale || resp.Stale
+		res.Amounts[resp.Rate.TargetCurrency] = resp.Amount
+		res.Rates[resp.Rate.TargetCurrency] = resp.Rate.RateTargetToBase
+	}
+	return res, nil
+}
+
+// ConvertMultiResponseToBytes converts internal entity.ConvertMultiResponse to http response body
+func ConvertMultiResponseToBytes(response *entity.ConvertMultiResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// BodyToGetRateMatrixRequest converts the http request body to internal entity.GetRateMatrixRequest,
+// duplicated currencies are dropped.
+func BodyToGetRateMatrixRequest(body []byte) (*entity.GetRateMatrixRequest, error) {
+	var r entity.GetRateMatrixRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	r.Currencies = unique(r.Currencies)
+	if len(r.Currencies) == 0 {
+		return nil, errors.New("no currencies provided")
+	}
+	if len(r.Currencies) > MaxMatrixCurrencies {
+		return nil, fmt.Errorf("too many currencies %d provided, up to %d allowed", len(r.Currencies), MaxMatrixCurrencies)
+	}
+	err = CheckRateType(r.RateType)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetRateMatrixRequestToGetExchangeRateRequests converts entity.GetRateMatrixRequest to entity.GetExchangeRateRequest
+// for every pair of currencies, the request of the pair of i-th and j-th currencies is at i*N+j.
+// defaultCB is used as a fallback if country is not provided.
+func GetRateMatrixRequestToGetExchangeRateRequests(
+	req *entity.GetRateMatrixRequest,
+	defaultCB string,
+) ([]*entity.GetExchangeRateRequest, error) {
+	if req == nil {
+		return nil, errors.New("nil GetRateMatrixRequest")
+	}
+	country := defaultCB
+	if req.Country != nil {
+		country = *req.Country
+	}
+	date, err := requestDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckRateType(req.RateType)
+	if err != nil {
+		return nil, err
+	}
+	reqs := make([]*entity.GetExchangeRateRequest, 0, len(req.Currencies)*len(req.Currencies))
+	for _, base := range req.Currencies {
+		for _, target := range req.Currencies {
+			reqs = append(reqs, &entity.GetExchangeRateRequest{
+				Country:          country,
+				Date:             date,
+				BaseCurrencyID:   base,
+				TargetCurrencyID: target,
+				Amount:           decimal.NewFromInt(1),
+				Places:           money.RatePrecision,
+				Rounding:         money.HalfEven,
+				RateType:         req.RateType,
+			})
+		}
+	}
+	return reqs, nil
+}
+
+// GetExchangeRateResultsToGetRateMatrixResponse converts the results of the requests made by
+// GetRateMatrixRequestToGetExchangeRateRequests for the currencies to entity.GetRateMatrixResponse.
+// Matrix fails if the rate of any pair fails.
+func GetExchangeRateResultsToGetRateMatrixResponse(
+	currencies []string,
+	results []entity.GetExchangeRateResult,
+) (*entity.GetRateMatrixResponse, error) {
+	if len(results) != len(currencies)*len(currencies) {
+		return nil, fmt.Errorf("%d rates returned for %d currencies", len(results), len(currencies))
+	}
+	res := &entity.GetRateMatrixResponse{
+		Currencies: currencies,
+		Rates:      make(map[string]map[string]decimal.Decimal, len(currencies)),
+	}
+	for i, base := range currencies {
+		row := make(map[string]decimal.Decimal, len(currencies))
+		for j, target := range currencies {
+			resp, err := exchangeRateResult(results[i*len(currencies)+j], res.Date)
+			if err != nil {
+				return nil, err
+			}
+			res.Date = resp.EffectiveDate
+			res.Stale = res.Stale || resp.Stale
+			row[target] = resp.Rate.RateTargetToBase
+		}
+		res.Rates[base] = row
+	}
+	return res, nil
+}
+
+// GetRateMatrixResponseToBytes converts internal entity.GetRateMatrixResponse to http response body
+func GetRateMatrixResponseToBytes(response *entity.GetRateMatrixResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// exchangeRateResult returns the response of the result, it fails if the result is calculated with the rates
+// of the date different from the date of the previous results
+func exchangeRateResult(r entity.GetExchangeRateResult, date string) (*entity.GetExchangeRateResponse, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.Response == nil {
+		return nil, errors.New("nil GetExchangeRateResponse")
+	}
+	if date != "" && r.Response.EffectiveDate != date {
+		return nil, fmt.Errorf("rates of different dates %s and %s returned", date, r.Response.EffectiveDate)
+	}
+	return r.Response, nil
+}
+
+// unique returns the values in the order of the first occurrence without duplicates
+func unique(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		res = append(res, v)
+	}
+	return res
+}
@@ -0,0 +1,497 @@
+package mapper
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"my_go/money"
+	"my_go/utils"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBodyToConvertMultiRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      *entity.ConvertMultiRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, duplicated targets dropped",
+			body: []byte(`{"country":"russia","source_currency":"USD","target_currencies":["EUR","JPY","EUR"],"amount":"100"}`),
+			want: &entity.ConvertMultiRequest{
+				Country:          utils.ToPointer("russia"),
+				SourceCurrency:   "USD",
+				TargetCurrencies: []string{"EUR", "JPY"},
+				Amount:           decPtr("100"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "missing amount",
+			body:      []byte(`{"source_currency":"USD","target_currencies":["EUR"]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "negative amount",
+			body:      []byte(`{"source_currency":"USD","target_currencies":["EUR"],"amount":-100}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "amount with huge exponent",
+			body:      []byte(`{"source_currency":"USD","target_currencies":["EUR"],"amount":"1e5000000"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "no target currencies",
+			body:      []byte(`{"source_currency":"USD","amount":"100"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "too many target currencies",
+			body: []byte(`{"source_currency":"USD","amount":"100","target_currencies":["C0"` +
+				manyCurrencies(MaxTargetCurrencies) + `]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "amount has more decimal places than source currency allows",
+			body:      []byte(`{"source_currency":"JPY","target_currencies":["USD"],"amount":"1.5"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad rounding mode",
+			body:      []byte(`{"source_currency":"USD","target_currencies":["EUR"],"amount":"1","rounding_mode":"ceiling"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad rate type",
+			body:      []byte(`{"source_currency":"USD","target_currencies":["EUR"],"amount":"1","rate_type":"best"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad json",
+			body:      []byte(`{"source_currency"`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToConvertMultiRequest(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// manyCurrencies returns n distinct quoted currency codes, each prefixed with comma
+func manyCurrencies(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		b.WriteString(`,"C` + strconv.Itoa(i) + `"`)
+	}
+	return b.String()
+}
+
+func TestConvertMultiRequestToGetExchangeRateRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *entity.ConvertMultiRequest
+		want      []*entity.GetExchangeRateRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, default country",
+			req: &entity.ConvertMultiRequest{
+				SourceCurrency:   "USD",
+				TargetCurrencies: []string{"EUR", "JPY"},
+				Amount:           decPtr("100"),
+				RoundingMode:     "down",
+				RateType:         "mid",
+			},
+			want: []*entity.GetExchangeRateRequest{
+				{
+					Country:          "russia",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "EUR",
+					Amount:           decimal.RequireFromString("100"),
+					Places:           2,
+					Rounding:         money.Down,
+					RateType:         "mid",
+				},
+				{
+					Country:          "russia",
+					BaseCurrencyID:   "USD",
+					TargetCurrencyID: "JPY",
+					Amount:           decimal.RequireFromString("100"),
+					Places:           0,
+					Rounding:         money.Down,
+					RateType:         "mid",
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "bad date",
+			req: &entity.ConvertMultiRequest{
+				Date:             utils.ToPointer("01.03.2023"),
+				SourceCurrency:   "USD",
+				TargetCurrencies: []string{"EUR"},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertMultiRequestToGetExchangeRateRequests(tt.req, "russia")
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func rateResult(base string, target string, rate string, amount string, date string) entity.GetExchangeRateResult {
+	return entity.GetExchangeRateResult{
+		Response: &entity.GetExchangeRateResponse{
+			Rate: entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     base,
+				TargetCurrency:   target,
+				RateTargetToBase: decimal.RequireFromString(rate),
+			},
+			Amount:        decimal.RequireFromString(amount),
+			EffectiveDate: date,
+		},
+	}
+}
+
+func TestGetExchangeRateResultsToConvertMultiResponse(t *testing.T) {
+	stale := rateResult("USD", "JPY", "137.9811", "13798", "2023-04-19")
+	stale.Response.Stale = true
+	tests := []struct {
+		name      string
+		results   []entity.GetExchangeRateResult
+		want      *entity.ConvertMultiResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+				stale,
+			},
+			want: &entity.ConvertMultiResponse{
+				Date: "2023-04-19",
+				Amounts: map[string]decimal.Decimal{
+					"EUR": decimal.RequireFromString("91.26"),
+					"JPY": decimal.RequireFromString("13798"),
+				},
+				Rates: map[string]decimal.Decimal{
+					"EUR": decimal.RequireFromString("0.9126"),
+					"JPY": decimal.RequireFromString("137.9811"),
+				},
+				Stale: true,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "target currency fails",
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+				{Err: errors.New("currency ABC not supported")},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "no response and no error",
+			results: []entity.GetExchangeRateResult{
+				{},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "rates of different dates",
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "EUR", "0.9126", "91.26", "2023-04-19"),
+				rateResult("USD", "JPY", "137.9811", "13798", "2023-04-20"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExchangeRateResultsToConvertMultiResponse(tt.results)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertMultiResponseToBytes(t *testing.T) {
+	got, err := ConvertMultiResponseToBytes(&entity.ConvertMultiResponse{
+		Date:    "2023-04-19",
+		Amounts: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("91.26")},
+		Rates:   map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9126")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"date":"2023-04-19","amounts":{"EUR":"91.26"},"rates":{"EUR":"0.9126"}}`, string(got))
+}
+
+func TestBodyToGetRateMatrixRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      *entity.GetRateMatrixRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, duplicated currencies dropped",
+			body: []byte(`{"country":"russia","currencies":["USD","EUR","USD"],"rate_type":"mid"}`),
+			want: &entity.GetRateMatrixRequest{
+				Country:    utils.ToPointer("russia"),
+				Currencies: []string{"USD", "EUR"},
+				RateType:   "mid",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "no currencies",
+			body:      []byte(`{"country":"russia"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many currencies",
+			body:      []byte(`{"currencies":["C0"` + manyCurrencies(MaxMatrixCurrencies) + `]}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad rate type",
+			body:      []byte(`{"currencies":["USD"],"rate_type":"best"}`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad json",
+			body:      []byte(`{"currencies"`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToGetRateMatrixRequest(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetRateMatrixRequestToGetExchangeRateRequests(t *testing.T) {
+	pair := func(base string, target string) *entity.GetExchangeRateRequest {
+		return &entity.GetExchangeRateRequest{
+			Country:          "thailand",
+			Date:             "2023-03-01",
+			BaseCurrencyID:   base,
+			TargetCurrencyID: target,
+			Amount:           decimal.NewFromInt(1),
+			Places:           money.RatePrecision,
+			Rounding:         money.HalfEven,
+			RateType:         "sell",
+		}
+	}
+	tests := []struct {
+		name      string
+		req       *entity.GetRateMatrixRequest
+		want      []*entity.GetExchangeRateRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			req: &entity.GetRateMatrixRequest{
+				Country:    utils.ToPointer("thailand"),
+				Date:       utils.ToPointer("2023-03-01"),
+				Currencies: []string{"USD", "EUR"},
+				RateType:   "sell",
+			},
+			want: []*entity.GetExchangeRateRequest{
+				pair("USD", "USD"),
+				pair("USD", "EUR"),
+				pair("EUR", "USD"),
+				pair("EUR", "EUR"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "bad date",
+			req: &entity.GetRateMatrixRequest{
+				Date:       utils.ToPointer("01.03.2023"),
+				Currencies: []string{"USD"},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad rate type",
+			req: &entity.GetRateMatrixRequest{
+				Currencies: []string{"USD"},
+				RateType:   "best",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetRateMatrixRequestToGetExchangeRateRequests(tt.req, "russia")
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetExchangeRateResultsToGetRateMatrixResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		currencies []string
+		results    []entity.GetExchangeRateResult
+		want       *entity.GetRateMatrixResponse
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Happy path",
+			currencies: []string{"USD", "EUR"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "USD", "1", "1", "2023-04-19"),
+				rateResult("USD", "EUR", "0.9126", "0.9126", "2023-04-19"),
+				rateResult("EUR", "USD", "1.0958", "1.0958", "2023-04-19"),
+				rateResult("EUR", "EUR", "1", "1", "2023-04-19"),
+			},
+			want: &entity.GetRateMatrixResponse{
+				Date:       "2023-04-19",
+				Currencies: []string{"USD", "EUR"},
+				Rates: map[string]map[string]decimal.Decimal{
+					"USD": {"USD": decimal.RequireFromString("1"), "EUR": decimal.RequireFromString("0.9126")},
+					"EUR": {"USD": decimal.RequireFromString("1.0958"), "EUR": decimal.RequireFromString("1")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "pair fails",
+			currencies: []string{"USD", "ABC"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "USD", "1", "1", "2023-04-19"),
+				{Err: errors.New("currency ABC not supported")},
+				{Err: errors.New("currency ABC not supported")},
+				{Err: errors.New("currency ABC not supported")},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:       "results don't match the currencies",
+			currencies: []string{"USD", "EUR"},
+			results: []entity.GetExchangeRateResult{
+				rateResult("USD", "USD", "1", "1", "2023-04-19"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExchangeRateResultsToGetRateMatrixResponse(tt.currencies, tt.results)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetRateMatrix_smallUnitCurrencies(t *testing.T) {
+	rates := &entity.ExchangeRates{
+		Country:       "russia",
+		EffectiveDate: "2023-04-19",
+		Rates: map[string]entity.Rate{
+			"RUB": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "RUB", RateTargetToBase: decimal.RequireFromString("1")},
+			"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.5556")},
+			"JPY": {Nominal: 100, BaseCurrency: "RUB", TargetCurrency: "JPY", RateTargetToBase: decimal.RequireFromString("60.6689")},
+			"VND": {Nominal: 10000, BaseCurrency: "RUB", TargetCurrency: "VND", RateTargetToBase: decimal.RequireFromString("34.6791")},
+		},
+	}
+	currencies := []string{"VND", "USD", "JPY"}
+	reqs, err := GetRateMatrixRequestToGetExchangeRateRequests(&entity.GetRateMatrixRequest{
+		Date:       utils.ToPointer("2023-04-19"),
+		Currencies: currencies,
+	}, "russia")
+	assert.NoError(t, err)
+	results := make([]entity.GetExchangeRateResult, 0, len(reqs))
+	for _, req := range reqs {
+		res, err := CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(rates, req)
+		results = append(results, entity.GetExchangeRateResult{Response: res, Err: err})
+	}
+
+	got, err := GetExchangeRateResultsToGetRateMatrixResponse(currencies, results)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.GetRateMatrixResponse{
+		Currencies: currencies,
+		Rates: map[string]map[string]decimal.Decimal{
+			"VND": {
+				"VND": decimal.RequireFromString("1"),
+				"USD": decimal.RequireFromString("0.000042522"),
+				"JPY": decimal.RequireFromString("0.00571612"),
+			},
+			"USD": {
+				"VND": decimal.RequireFromString("23517.2193"),
+				"USD": decimal.RequireFromString("1"),
+				"JPY": decimal.RequireFromString("134.4274"),
+			},
+			"JPY": {
+				"VND": decimal.RequireFromString("174.9437"),
+				"USD": decimal.RequireFromString("0.00743896"),
+				"JPY": decimal.RequireFromString("1"),
+			},
+		},
+	}, got)
+}
+
+func TestGetRateMatrixResponseToBytes(t *testing.T) {
+	got, err := GetRateMatrixResponseToBytes(&entity.GetRateMatrixResponse{
+		Date:       "2023-04-19",
+		Currencies: []string{"USD"},
+		Rates: map[string]map[string]decimal.Decimal{
+			"USD": {"USD": decimal.RequireFromString("1")},
+		},
+		Stale: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"date":"2023-04-19","currencies":["USD"],"rates":{"USD":{"USD":"1"}},"stale":true}`, string(got))
+}
@@ -0,0 +1,85 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"my_go/entity"
+	"my_go/money"
+	"sort"
+)
+
+// CurrenciesToListCurrenciesResponse converts the reference data of currencies to entity.ListCurrenciesResponse
+// ordered by code. Quotes maps countries of central banks to the rates they set, the countries are added
+// to the banks of every quoted currency in the order of the provided countries. Currencies quoted by the banks
+// but missing in the reference data are listed with the code only.
+func CurrenciesToListCurrenciesResponse(
+	currencies map[string]entity.Currency,
+	countries []string,
+	quotes map[string]*entity.ExchangeRates,
+	unavailable []string,
+) *entity.ListCurrenciesResponse {
+	m := make(map[string]entity.Currency, len(currencies))
+	for code, c := range currencies {
+		m[code] = c
+	}
+	for _, country := range countries {
+		rates, ok := quotes[country]
+		if !ok || rates == nil {
+			continue
+		}
+		for code := range rates.Rates {
+			c, ok := m[code]
+			if !ok {
+				c = entity.Currency{
+					Code:       code,
+					MinorUnits: money.MinorUnits(code),
+				}
+			}
+			c.Banks = append(c.Banks, country)
+			m[code] = c
+		}
+	}
+
+	codes := make([]string, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	res := make([]entity.Currency, 0, len(codes))
+	for _, code := range codes {
+		res = append(res, m[code])
+	}
+	return &entity.ListCurrenciesResponse{
+		Currencies:       res,
+		UnavailableBanks: unavailable,
+	}
+}
+
+// RatesToCurrencies picks the reference data of the currencies of the rates.
+// Currencies missing in the reference data are returned with the code only.
+func RatesToCurrencies(
+	rates map[string]entity.Rate,
+	currencies map[string]entity.Currency,
+) map[string]entity.Currency {
+	res := make(map[string]entity.Currency, len(rates))
+	for code := range rates {
+		c, ok := currencies[code]
+		if !ok {
+			c = entity.Currency{
+				Code:       code,
+				MinorUnits: money.MinorUnits(code),
+			}
+		}
+		res[code] = c
+	}
+	return res
+}
+
+// ListCurrenciesResponseToBytes converts internal entity.ListCurrenciesResponse to http response body
+func ListCurrenciesResponseToBytes(response *entity.ListCurrenciesResponse) ([]byte, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
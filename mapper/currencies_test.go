@@ -0,0 +1,95 @@
+package mapper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+)
+
+func TestCurrenciesToListCurrenciesResponse(t *testing.T) {
+	currencies := map[string]entity.Currency{
+		"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2},
+		"RUB": {Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2},
+		"THB": {Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2},
+	}
+	tests := []struct {
+		name        string
+		countries   []string
+		quotes      map[string]*entity.ExchangeRates
+		unavailable []string
+		want        *entity.ListCurrenciesResponse
+	}{
+		{
+			name:      "Happy path",
+			countries: []string{"russia", "thailand"},
+			quotes: map[string]*entity.ExchangeRates{
+				"russia":   {Rates: map[string]entity.Rate{"RUB": {}, "USD": {}, "KWD": {}}},
+				"thailand": {Rates: map[string]entity.Rate{"THB": {}, "USD": {}}},
+			},
+			want: &entity.ListCurrenciesResponse{
+				Currencies: []entity.Currency{
+					{Code: "KWD", MinorUnits: 3, Banks: []string{"russia"}},
+					{Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2, Banks: []string{"russia"}},
+					{Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2, Banks: []string{"thailand"}},
+					{
+						Code:        "USD",
+						NumericCode: "840",
+						Name:        "US Dollar",
+						NameRu:      "Доллар США",
+						Nominal:     1,
+						MinorUnits:  2,
+						Banks:       []string{"russia", "thailand"},
+					},
+				},
+			},
+		},
+		{
+			name:      "bank unavailable",
+			countries: []string{"russia", "thailand"},
+			quotes: map[string]*entity.ExchangeRates{
+				"russia": {Rates: map[string]entity.Rate{"RUB": {}}},
+			},
+			unavailable: []string{"thailand"},
+			want: &entity.ListCurrenciesResponse{
+				Currencies: []entity.Currency{
+					{Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2, Banks: []string{"russia"}},
+					{Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2},
+					{Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2},
+				},
+				UnavailableBanks: []string{"thailand"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CurrenciesToListCurrenciesResponse(currencies, tt.countries, tt.quotes, tt.unavailable)
+			assert.Equal(t, tt.want, got)
+			// the reference data is not modified
+			assert.Nil(t, currencies["USD"].Banks)
+		})
+	}
+}
+
+func TestRatesToCurrencies(t *testing.T) {
+	currencies := map[string]entity.Currency{
+		"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2},
+		"EUR": {Code: "EUR", NumericCode: "978", Name: "Euro", MinorUnits: 2},
+	}
+	got := RatesToCurrencies(map[string]entity.Rate{"USD": {}, "JPY": {}}, currencies)
+	assert.Equal(t, map[string]entity.Currency{
+		"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2},
+		"JPY": {Code: "JPY", MinorUnits: 0},
+	}, got)
+}
+
+func TestListCurrenciesResponseToBytes(t *testing.T) {
+	got, err := ListCurrenciesResponseToBytes(&entity.ListCurrenciesResponse{
+		Currencies: []entity.Currency{
+			{Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2, Banks: []string{"russia"}},
+			{Code: "JPY"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"currencies":[{"code":"USD","numeric_code":"840","name":"US Dollar","minor_units":2,`+
+		`"banks":["russia"]},{"code":"JPY","minor_units":0}]}`, string(got))
+}
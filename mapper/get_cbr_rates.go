@@ -2,7 +2,10 @@ package mapper
 
 import (
 	"errors"
+	"fmt"
 	"my_go/entity"
+	"my_go/money"
+	"time"
 )
 
 // GetExchangeRatesRequestToGetCBRatesRequest converts incoming controller request to
@@ -13,20 +16,113 @@ func GetExchangeRatesRequestToGetCBRatesRequest(
 	if r == nil {
 		return nil, errors.New("nil GetExchangeRatesRequest")
 	}
+	date, err := requestDate(r.Date)
+	if err != nil {
+		return nil, err
+	}
 	return &entity.GetCBRatesRequest{
 		Country: r.Country,
+		Date:    date,
 	}, nil
 }
 
 // GetCBRRatesResponseToGetExchangeRatesResponse converts repository response to GetExchangeRatesResponse
-// that will be marshalled and used for external API
+// that will be marshalled and used for external API.
+// Official date the rates are set for is used as response date, if central bank doesn't provide it
+// the date rates were loaded for is used instead.
 func GetCBRRatesResponseToGetExchangeRatesResponse(
 	r *entity.GetCBRatesResponse,
 ) (*entity.GetExchangeRatesResponse, error) {
 	if r == nil {
 		return nil, errors.New("nil GetCBRatesResponse")
 	}
+	date := r.Rates.EffectiveDate
+	if date == "" {
+		date = r.Rates.DateLoaded
+	}
+	publishedAt := ""
+	if !r.Rates.PublishedAt.IsZero() {
+		publishedAt = r.Rates.PublishedAt.Format(time.RFC3339)
+	}
 	return &entity.GetExchangeRatesResponse{
-		Rates: r.Rates.Rates,
+		Date:        date,
+		PublishedAt: publishedAt,
+		Reference:   r.Rates.Reference,
+		Stale:       r.Stale,
+		Rates:       r.Rates.Rates,
 	}, nil
 }
+
+// RebaseRates returns the rates of the currencies normalized to 1 unit of base currency: Nominal is 1,
+// Inverse is set and RateTargetToBase is the amount of target currency for 1 unit of base currency rounded half
+// to even with money.QuoRate, so the small rates keep their significant digits. Empty base means the currency
+// the central bank sets the rates against, empty currencies mean all the currencies quoted by the bank.
+// Every rate is calculated from the reference rates with a single division, so it's rounded the same way
+// as the conversion rate of the pair.
+func RebaseRates(rates map[string]entity.Rate, base string, currencies []string) (map[string]entity.Rate, error) {
+	if base == "" {
+		base = baseCurrency(rates)
+	}
+	baseRate, ok := rates[base]
+	if !ok {
+		return nil, fmt.Errorf("base currency %s not supported by CB", base)
+	}
+	baseNum, baseDen, err := unitValue(baseRate, "")
+	if err != nil {
+		return nil, fmt.Errorf("base currency %s, %s", base, err)
+	}
+	if len(currencies) == 0 {
+		currencies = make([]string, 0, len(rates))
+		for code := range rates {
+			currencies = append(currencies, code)
+		}
+	}
+	res := make(map[string]entity.Rate, len(currencies))
+	for _, code := range currencies {
+		r, ok := rates[code]
+		if !ok {
+			return nil, fmt.Errorf("currency %s not supported by CB", code)
+		}
+		num, den, err := unitValue(r, "")
+		if err != nil {
+			return nil, fmt.Errorf("currency %s, %s", code, err)
+		}
+		if baseDen.IsZero() || den.IsZero() {
+			return nil, fmt.Errorf("bad rate of currency pair %s/%s", base, code)
+		}
+		rate, err := money.QuoRate(baseNum.Mul(den), num.Mul(baseDen))
+		if err != nil {
+			return nil, fmt.Errorf("bad rate of currency %s: %s", code, err)
+		}
+		res[code] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     base,
+			TargetCurrency:   code,
+			RateTargetToBase: rate,
+			Inverse:          true,
+		}
+	}
+	return res, nil
+}
+
+// baseCurrency returns the currency the central bank sets the rates against
+func baseCurrency(rates map[string]entity.Rate) string {
+	for _, r := range rates {
+		if r.BaseCurrency != "" {
+			return r.BaseCurrency
+		}
+	}
+	return ""
+}
+
+// requestDate validates optional date provided in the external API request.
+// Nil date is mapped to the empty string meaning the current rates are requested.
+func requestDate(d *string) (string, error) {
+	if d == nil {
+		return "", nil
+	}
+	if _, err := time.Parse(entity.DateLayout, *d); err != nil {
+		return "", fmt.Errorf("bad date %s, expected format %s", *d, entity.DateLayout)
+	}
+	return *d, nil
+}
@@ -1,8 +1,10 @@
 package mapper
 
 import (
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
+	"my_go/utils"
 	"testing"
 	"time"
 )
@@ -30,6 +32,31 @@ func TestGetExchangeRatesRequestToGetCBRatesRequest(t *testing.T) {
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "Happy path, date provided",
+			args: args{
+				r: &entity.GetExchangeRatesRequest{
+					Country: "russia",
+					Date:    utils.ToPointer("2023-04-17"),
+				},
+			},
+			want: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-17",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "bad date format",
+			args: args{
+				r: &entity.GetExchangeRatesRequest{
+					Country: "russia",
+					Date:    utils.ToPointer("yesterday"),
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
 		{
 			name: "nil request",
 			args: args{
@@ -60,13 +87,13 @@ func TestGetCBRRatesResponseToGetExchangeRatesResponse(t *testing.T) {
 					Nominal:          100,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "USD",
-					RateTargetToBase: 123.56,
+					RateTargetToBase: decimal.RequireFromString("123.56"),
 				},
 				"JPY": {
 					Nominal:          10,
 					BaseCurrency:     "RUB",
 					TargetCurrency:   "JPY",
-					RateTargetToBase: 987.65,
+					RateTargetToBase: decimal.RequireFromString("987.65"),
 				},
 			},
 		},
@@ -86,21 +113,60 @@ func TestGetCBRRatesResponseToGetExchangeRatesResponse(t *testing.T) {
 				r: response,
 			},
 			want: &entity.GetExchangeRatesResponse{
+				Date: "2023-01-01",
 				Rates: map[string]entity.Rate{
 					"USD": {
 						Nominal:          100,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "USD",
-						RateTargetToBase: 123.56,
+						RateTargetToBase: decimal.RequireFromString("123.56"),
 					},
 					"JPY": {
 						Nominal:          10,
 						BaseCurrency:     "RUB",
 						TargetCurrency:   "JPY",
-						RateTargetToBase: 987.65,
+						RateTargetToBase: decimal.RequireFromString("987.65"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, stale rates",
+			args: args{
+				r: &entity.GetCBRatesResponse{
+					Rates: response.Rates,
+					Stale: true,
+				},
+			},
+			want: &entity.GetExchangeRatesResponse{
+				Date:  "2023-01-01",
+				Stale: true,
+				Rates: response.Rates.Rates,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, official date and publication timestamp",
+			args: args{
+				r: &entity.GetCBRatesResponse{
+					Rates: &entity.ExchangeRates{
+						Country:       "russia",
+						DateLoaded:    "2023-01-01",
+						EffectiveDate: "2023-01-02",
+						PublishedAt:   time.Date(2023, 1, 1, 15, 30, 0, 0, time.UTC),
+						Reference:     "075/A/NBP/2023",
+						TimeZone:      ruLoc,
+						Rates:         response.Rates.Rates,
 					},
 				},
 			},
+			want: &entity.GetExchangeRatesResponse{
+				Date:        "2023-01-02",
+				PublishedAt: "2023-01-01T15:30:00Z",
+				Reference:   "075/A/NBP/2023",
+				Rates:       response.Rates.Rates,
+			},
 			assertion: assert.NoError,
 		},
 		{
@@ -120,3 +186,169 @@ func TestGetCBRRatesResponseToGetExchangeRatesResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestRebaseRates(t *testing.T) {
+	rates := map[string]entity.Rate{
+		"RUB": {
+			Nominal:          1,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   "RUB",
+			RateTargetToBase: decimal.RequireFromString("1"),
+		},
+		"USD": {
+			Nominal:          1,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   "USD",
+			RateTargetToBase: decimal.RequireFromString("81.5556"),
+		},
+		"JPY": {
+			Nominal:          100,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   "JPY",
+			RateTargetToBase: decimal.RequireFromString("60.6689"),
+		},
+		"EUR": {
+			Nominal:          1,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   "EUR",
+			RateTargetToBase: decimal.RequireFromString("89.3699"),
+			Bid:              utils.ToPointer(decimal.RequireFromString("89")),
+		},
+		"VND": {
+			Nominal:          10000,
+			BaseCurrency:     "RUB",
+			TargetCurrency:   "VND",
+			RateTargetToBase: decimal.RequireFromString("34.6791"),
+		},
+	}
+	rate := func(base string, target string, value string) entity.Rate {
+		return entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     base,
+			TargetCurrency:   target,
+			RateTargetToBase: decimal.RequireFromString(value),
+			Inverse:          true,
+		}
+	}
+	tests := []struct {
+		name       string
+		rates      map[string]entity.Rate
+		base       string
+		currencies []string
+		want       map[string]entity.Rate
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:  "Happy path",
+			rates: rates,
+			base:  "USD",
+			want: map[string]entity.Rate{
+				"RUB": rate("USD", "RUB", "81.5556"),
+				"USD": rate("USD", "USD", "1"),
+				"JPY": rate("USD", "JPY", "134.4274"),
+				"EUR": rate("USD", "EUR", "0.912562"),
+				"VND": rate("USD", "VND", "23517.2193"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Happy path, currencies filtered",
+			rates:      rates,
+			base:       "JPY",
+			currencies: []string{"USD", "EUR"},
+			want: map[string]entity.Rate{
+				"USD": rate("JPY", "USD", "0.00743896"),
+				"EUR": rate("JPY", "EUR", "0.00678852"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Happy path, small unit base currency keeps significant digits",
+			rates:      rates,
+			base:       "VND",
+			currencies: []string{"USD", "EUR", "JPY"},
+			want: map[string]entity.Rate{
+				"USD": rate("VND", "USD", "0.000042522"),
+				"EUR": rate("VND", "EUR", "0.000038804"),
+				"JPY": rate("VND", "JPY", "0.00571612"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Happy path, normalized against the bank base currency",
+			rates:      rates,
+			currencies: []string{"JPY", "RUB"},
+			want: map[string]entity.Rate{
+				"JPY": rate("RUB", "JPY", "1.64829"),
+				"RUB": rate("RUB", "RUB", "1"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, inverse rates",
+			rates: map[string]entity.Rate{
+				"EUR": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "EUR",
+					RateTargetToBase: decimal.RequireFromString("1"),
+				},
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "EUR",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("1.0958"),
+					Inverse:          true,
+				},
+			},
+			base: "USD",
+			want: map[string]entity.Rate{
+				"EUR": rate("USD", "EUR", "0.912575"),
+				"USD": rate("USD", "USD", "1"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "base currency not supported",
+			rates:     rates,
+			base:      "GBP",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:       "currency not supported",
+			rates:      rates,
+			base:       "USD",
+			currencies: []string{"EUR", "GBP"},
+			want:       nil,
+			assertion:  assert.Error,
+		},
+		{
+			name: "zero rate",
+			rates: map[string]entity.Rate{
+				"RUB": rates["RUB"],
+				"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD"},
+			},
+			base:      "RUB",
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "zero nominal",
+			rates: map[string]entity.Rate{
+				"RUB": rates["RUB"],
+				"USD": {BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.5556")},
+			},
+			base:      "RUB",
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RebaseRates(tt.rates, tt.base, tt.currencies)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -12,6 +12,7 @@ func BodyToGetExchangeRatesRequest(body []byte) (*entity.GetExchangeRatesRequest
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal: %s", err)
 	}
+	r.Currencies = unique(r.Currencies)
 	return &r, nil
 }
 
@@ -2,6 +2,7 @@ package mapper
 
 import (
 	"encoding/json"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"my_go/entity"
 	"testing"
@@ -28,6 +29,18 @@ func TestBodyToGetExchangeRateRequest(t *testing.T) {
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "Happy path, duplicated currencies dropped",
+			args: args{
+				body: []byte(`{"country":"russia","base":"USD","currencies":["EUR","JPY","EUR"]}`),
+			},
+			want: &entity.GetExchangeRatesRequest{
+				Country:    "russia",
+				Base:       "USD",
+				Currencies: []string{"EUR", "JPY"},
+			},
+			assertion: assert.NoError,
+		},
 		{
 			name: "bad json",
 			args: args{
@@ -53,13 +66,13 @@ func TestGetCBRateResponseToBytes(t *testing.T) {
 				Nominal:          100,
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "USD",
-				RateTargetToBase: 123.56,
+				RateTargetToBase: decimal.RequireFromString("123.56"),
 			},
 			"JPY": {
 				Nominal:          10,
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "JPY",
-				RateTargetToBase: 987.65,
+				RateTargetToBase: decimal.RequireFromString("987.65"),
 			},
 		},
 	}
@@ -0,0 +1,102 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my_go/entity"
+	"my_go/money"
+)
+
+// BodyToGetMetalsRequest converts the http request body to internal entity.GetMetalsRequest
+func BodyToGetMetalsRequest(body []byte) (*entity.GetMetalsRequest, error) {
+	var r entity.GetMetalsRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	return &r, nil
+}
+
+// GetMetalsResponseToBytes converts internal entity.GetMetalsResponse to http response body
+func GetMetalsResponseToBytes(r *entity.GetMetalsResponse) ([]byte, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// GetMetalsRequestToGetCBMetalsRequest converts incoming controller request to request consumed by repository
+func GetMetalsRequestToGetCBMetalsRequest(r *entity.GetMetalsRequest) (*entity.GetCBMetalsRequest, error) {
+	if r == nil {
+		return nil, errors.New("nil GetMetalsRequest")
+	}
+	date, err := requestDate(r.Date)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.GetCBMetalsRequest{
+		Country: r.Country,
+		Date:    date,
+	}, nil
+}
+
+// GetCBMetalsResponseToGetMetalsResponse converts repository response to GetMetalsResponse
+// that will be marshalled and used for external API.
+// Official date the prices are set for is used as response date, if it's unknown
+// the date prices were loaded for is used instead.
+func GetCBMetalsResponseToGetMetalsResponse(r *entity.GetCBMetalsResponse) (*entity.GetMetalsResponse, error) {
+	if r == nil || r.Prices == nil {
+		return nil, errors.New("nil GetCBMetalsResponse")
+	}
+	date := r.Prices.EffectiveDate
+	if date == "" {
+		date = r.Prices.DateLoaded
+	}
+	return &entity.GetMetalsResponse{
+		Date:   date,
+		Prices: r.Prices.Prices,
+	}, nil
+}
+
+// ExchangeRatesWithMetalPrices returns the copy of the rates with precious metals added as pseudo-currencies
+// identified by ISO 4217 codes, so the metals can be converted to any currency the central bank quotes.
+// The unit of the metal is one troy ounce, the prices of the central bank are set per gram.
+// Buying and selling prices are kept as Bid and Ask and the rate is taken as average between them.
+// Prices must be set in the base currency of the rates.
+func ExchangeRatesWithMetalPrices(r *entity.ExchangeRates, p *entity.MetalPrices) (*entity.ExchangeRates, error) {
+	if r == nil {
+		return nil, errors.New("nil ExchangeRates")
+	}
+	if p == nil {
+		return nil, errors.New("nil MetalPrices")
+	}
+	rates := make(map[string]entity.Rate, len(r.Rates)+len(p.Prices))
+	for currency, rate := range r.Rates {
+		rates[currency] = rate
+	}
+	for metal, price := range p.Prices {
+		base, ok := r.Rates[price.Currency]
+		if !ok || base.BaseCurrency != base.TargetCurrency {
+			return nil, fmt.Errorf(
+				"price of %s is set in %s that is not the base currency of CB %s", metal, price.Currency, r.Country,
+			)
+		}
+		bid := money.Normalize(price.Buy.Mul(entity.GramsPerTroyOunce))
+		ask := money.Normalize(price.Sell.Mul(entity.GramsPerTroyOunce))
+		mid := money.Average(bid, ask)
+		rates[metal] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     price.Currency,
+			TargetCurrency:   metal,
+			RateTargetToBase: mid,
+			Bid:              &bid,
+			Ask:              &ask,
+			Mid:              &mid,
+		}
+	}
+	res := *r
+	res.Rates = rates
+	return &res, nil
+}
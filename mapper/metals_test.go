@@ -0,0 +1,233 @@
+package mapper
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"my_go/utils"
+	"testing"
+)
+
+func TestBodyToGetMetalsRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		want      *entity.GetMetalsRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			body:      []byte(`{"country":"russia","date":"2023-04-19"}`),
+			want:      &entity.GetMetalsRequest{Country: "russia", Date: utils.ToPointer("2023-04-19")},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad json",
+			body:      []byte(`{"country"`),
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToGetMetalsRequest(tt.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetMetalsRequestToGetCBMetalsRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         *entity.GetMetalsRequest
+		want      *entity.GetCBMetalsRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			r:         &entity.GetMetalsRequest{Country: "russia"},
+			want:      &entity.GetCBMetalsRequest{Country: "russia"},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, date provided",
+			r:         &entity.GetMetalsRequest{Country: "russia", Date: utils.ToPointer("2023-04-19")},
+			want:      &entity.GetCBMetalsRequest{Country: "russia", Date: "2023-04-19"},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad date",
+			r:         &entity.GetMetalsRequest{Country: "russia", Date: utils.ToPointer("19.04.2023")},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			r:         nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMetalsRequestToGetCBMetalsRequest(tt.r)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetCBMetalsResponseToGetMetalsResponse(t *testing.T) {
+	prices := map[string]entity.MetalPrice{
+		"XAG": {
+			Metal:    "XAG",
+			Name:     "silver",
+			Currency: "RUB",
+			Buy:      decimal.RequireFromString("63.67"),
+			Sell:     decimal.RequireFromString("63.67"),
+		},
+	}
+	tests := []struct {
+		name      string
+		r         *entity.GetCBMetalsResponse
+		want      *entity.GetMetalsResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, effective date",
+			r: &entity.GetCBMetalsResponse{
+				Prices: &entity.MetalPrices{DateLoaded: "2023-04-20", EffectiveDate: "2023-04-19", Prices: prices},
+			},
+			want:      &entity.GetMetalsResponse{Date: "2023-04-19", Prices: prices},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, no effective date",
+			r: &entity.GetCBMetalsResponse{
+				Prices: &entity.MetalPrices{DateLoaded: "2023-04-20", Prices: prices},
+			},
+			want:      &entity.GetMetalsResponse{Date: "2023-04-20", Prices: prices},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil prices",
+			r:         &entity.GetCBMetalsResponse{},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil response",
+			r:         nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetCBMetalsResponseToGetMetalsResponse(tt.r)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExchangeRatesWithMetalPrices(t *testing.T) {
+	rub := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "RUB",
+		TargetCurrency:   "RUB",
+		RateTargetToBase: decimal.RequireFromString("1"),
+	}
+	usd := entity.Rate{
+		Nominal:          1,
+		BaseCurrency:     "RUB",
+		TargetCurrency:   "USD",
+		RateTargetToBase: decimal.RequireFromString("81.5"),
+	}
+	rates := &entity.ExchangeRates{
+		Country:       "russia",
+		DateLoaded:    "2023-04-19",
+		EffectiveDate: "2023-04-19",
+		Rates:         map[string]entity.Rate{"RUB": rub, "USD": usd},
+	}
+	gold := entity.MetalPrice{
+		Metal:    "XAU",
+		Name:     "gold",
+		Currency: "RUB",
+		Buy:      decimal.RequireFromString("5000"),
+		Sell:     decimal.RequireFromString("5010"),
+	}
+	tests := []struct {
+		name      string
+		r         *entity.ExchangeRates
+		p         *entity.MetalPrices
+		want      *entity.ExchangeRates
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, prices per troy ounce",
+			r:    rates,
+			p:    &entity.MetalPrices{Country: "russia", Prices: map[string]entity.MetalPrice{"XAU": gold}},
+			want: &entity.ExchangeRates{
+				Country:       "russia",
+				DateLoaded:    "2023-04-19",
+				EffectiveDate: "2023-04-19",
+				Rates: map[string]entity.Rate{
+					"RUB": rub,
+					"USD": usd,
+					"XAU": {
+						Nominal:          1,
+						BaseCurrency:     "RUB",
+						TargetCurrency:   "XAU",
+						RateTargetToBase: decimal.RequireFromString("155672.901384"),
+						Bid:              decPtr("155517.384"),
+						Ask:              decPtr("155828.418768"),
+						Mid:              decPtr("155672.901384"),
+					},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "prices not in the base currency of the bank",
+			r:    rates,
+			p: &entity.MetalPrices{Country: "russia", Prices: map[string]entity.MetalPrice{
+				"XAU": {Metal: "XAU", Currency: "USD", Buy: decimal.RequireFromString("62")},
+			}},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "prices in the currency the bank doesn't quote",
+			r:    rates,
+			p: &entity.MetalPrices{Country: "russia", Prices: map[string]entity.MetalPrice{
+				"XAU": {Metal: "XAU", Currency: "THB", Buy: decimal.RequireFromString("2100")},
+			}},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil rates",
+			r:         nil,
+			p:         &entity.MetalPrices{},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil prices",
+			r:         rates,
+			p:         nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExchangeRatesWithMetalPrices(tt.r, tt.p)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Len(t, rates.Rates, 2, "source rates must not be modified")
+}
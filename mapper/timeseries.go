@@ -0,0 +1,114 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my_go/entity"
+	"time"
+)
+
+// maxTimeSeriesDays limits the range of the time series request
+const maxTimeSeriesDays = 366
+
+// BodyToGetTimeSeriesRequest converts the http request body to internal entity.GetTimeSeriesRequest
+func BodyToGetTimeSeriesRequest(body []byte) (*entity.GetTimeSeriesRequest, error) {
+	var r entity.GetTimeSeriesRequest
+	err := json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %s", err)
+	}
+	return &r, nil
+}
+
+// GetTimeSeriesResponseToBytes converts internal entity.GetTimeSeriesResponse to http response body
+func GetTimeSeriesResponseToBytes(r *entity.GetTimeSeriesResponse) ([]byte, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	return b, nil
+}
+
+// GetTimeSeriesRequestToGetCBRatesTimeSeriesRequest converts entity.GetTimeSeriesRequest
+// to entity.GetCBRatesTimeSeriesRequest. defaultCB is used as a fallback if country if not provided.
+// Range is validated to be not empty and not longer than maxTimeSeriesDays.
+func GetTimeSeriesRequestToGetCBRatesTimeSeriesRequest(
+	req *entity.GetTimeSeriesRequest,
+	defaultCB string,
+) (*entity.GetCBRatesTimeSeriesRequest, error) {
+	if req == nil {
+		return nil, errors.New("nil GetTimeSeriesRequest")
+	}
+	if req.BaseCurrency == "" || req.TargetCurrency == "" {
+		return nil, errors.New("base_currency and target_currency are required")
+	}
+	start, err := time.Parse(entity.DateLayout, req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad start_date %s, expected format %s", req.StartDate, entity.DateLayout)
+	}
+	end, err := time.Parse(entity.DateLayout, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad end_date %s, expected format %s", req.EndDate, entity.DateLayout)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end_date %s is before start_date %s", req.EndDate, req.StartDate)
+	}
+	if end.Sub(start) > maxTimeSeriesDays*24*time.Hour {
+		return nil, fmt.Errorf("date range is longer than %d days", maxTimeSeriesDays)
+	}
+	country := defaultCB
+	if req.Country != nil {
+		country = *req.Country
+	}
+	return &entity.GetCBRatesTimeSeriesRequest{
+		Country:    country,
+		Currencies: []string{req.BaseCurrency, req.TargetCurrency},
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+	}, nil
+}
+
+// GetCBRatesTimeSeriesResponseToGetTimeSeriesResponse converts the rates loaded for the range to
+// entity.GetTimeSeriesResponse. Rate for every date is calculated for 1 unit of base currency the same way
+// as for a single conversion. Dates where central bank didn't set the rate for any of the currencies are skipped.
+func GetCBRatesTimeSeriesResponseToGetTimeSeriesResponse(
+	r *entity.GetCBRatesTimeSeriesResponse,
+	req *entity.GetTimeSeriesRequest,
+	country string,
+) (*entity.GetTimeSeriesResponse, error) {
+	if r == nil {
+		return nil, errors.New("nil GetCBRatesTimeSeriesResponse")
+	}
+	if req == nil {
+		return nil, errors.New("nil GetTimeSeriesRequest")
+	}
+	points := make([]entity.TimeSeriesPoint, 0, len(r.Rates))
+	for i := range r.Rates {
+		rate, err := CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(
+			&r.Rates[i],
+			&entity.GetExchangeRateRequest{
+				Country:          country,
+				BaseCurrencyID:   req.BaseCurrency,
+				TargetCurrencyID: req.TargetCurrency,
+			},
+		)
+		if err != nil {
+			continue
+		}
+		points = append(points, entity.TimeSeriesPoint{
+			Date: r.Rates[i].DateLoaded,
+			Rate: rate.Rate.RateTargetToBase,
+		})
+	}
+	if len(r.Rates) > 0 && len(points) == 0 {
+		return nil, fmt.Errorf(
+			"pair %s/%s is not supported by CB %s", req.BaseCurrency, req.TargetCurrency, country,
+		)
+	}
+	return &entity.GetTimeSeriesResponse{
+		BaseCurrency:   req.BaseCurrency,
+		TargetCurrency: req.TargetCurrency,
+		Rates:          points,
+	}, nil
+}
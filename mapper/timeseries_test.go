@@ -0,0 +1,306 @@
+package mapper
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"my_go/utils"
+	"testing"
+	"time"
+)
+
+func TestBodyToGetTimeSeriesRequest(t *testing.T) {
+	type args struct {
+		body []byte
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      *entity.GetTimeSeriesRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				body: []byte(`{"country":"russia","base_currency":"USD","target_currency":"EUR",` +
+					`"start_date":"2023-04-17","end_date":"2023-04-21"}`),
+			},
+			want: &entity.GetTimeSeriesRequest{
+				Country:        utils.ToPointer("russia"),
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				StartDate:      "2023-04-17",
+				EndDate:        "2023-04-21",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "bad json",
+			args: args{
+				body: []byte(`{"country":`),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BodyToGetTimeSeriesRequest(tt.args.body)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTimeSeriesRequestToGetCBRatesTimeSeriesRequest(t *testing.T) {
+	type args struct {
+		req       *entity.GetTimeSeriesRequest
+		defaultCB string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      *entity.GetCBRatesTimeSeriesRequest
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					Country:        utils.ToPointer("thailand"),
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "2023-04-17",
+					EndDate:        "2023-04-21",
+				},
+				defaultCB: "russia",
+			},
+			want: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "thailand",
+				Currencies: []string{"USD", "EUR"},
+				StartDate:  "2023-04-17",
+				EndDate:    "2023-04-21",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, no country, fallback",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "2023-04-17",
+					EndDate:        "2023-04-17",
+				},
+				defaultCB: "russia",
+			},
+			want: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "russia",
+				Currencies: []string{"USD", "EUR"},
+				StartDate:  "2023-04-17",
+				EndDate:    "2023-04-17",
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "nil request",
+			args: args{
+				req: nil,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "missing currency",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency: "USD",
+					StartDate:    "2023-04-17",
+					EndDate:      "2023-04-21",
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad start date",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "17.04.2023",
+					EndDate:        "2023-04-21",
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "bad end date",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "2023-04-17",
+					EndDate:        "21.04.2023",
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "end date before start date",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "2023-04-21",
+					EndDate:        "2023-04-17",
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "range is too long",
+			args: args{
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "EUR",
+					StartDate:      "2021-04-17",
+					EndDate:        "2023-04-17",
+				},
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTimeSeriesRequestToGetCBRatesTimeSeriesRequest(tt.args.req, tt.args.defaultCB)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetCBRatesTimeSeriesResponseToGetTimeSeriesResponse(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	req := &entity.GetTimeSeriesRequest{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		StartDate:      "2023-04-18",
+		EndDate:        "2023-04-20",
+	}
+	timeSeries := &entity.GetCBRatesTimeSeriesResponse{
+		Rates: []entity.ExchangeRates{
+			{
+				Country:    "russia",
+				DateLoaded: "2023-04-18",
+				TimeZone:   ruTZ,
+				Rates: map[string]entity.Rate{
+					"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.6101")},
+					"EUR": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("89.5541")},
+				},
+			},
+			{
+				Country:    "russia",
+				DateLoaded: "2023-04-19",
+				TimeZone:   ruTZ,
+				Rates: map[string]entity.Rate{
+					"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.6101")},
+				},
+			},
+			{
+				Country:    "russia",
+				DateLoaded: "2023-04-20",
+				TimeZone:   ruTZ,
+				Rates: map[string]entity.Rate{
+					"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("81.6101")},
+					"EUR": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("81.6101")},
+				},
+			},
+		},
+	}
+	type args struct {
+		r       *entity.GetCBRatesTimeSeriesResponse
+		req     *entity.GetTimeSeriesRequest
+		country string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      *entity.GetTimeSeriesResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, date with missing currency is skipped",
+			args: args{
+				r:       timeSeries,
+				req:     req,
+				country: "russia",
+			},
+			want: &entity.GetTimeSeriesResponse{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Rates: []entity.TimeSeriesPoint{
+					{Date: "2023-04-18", Rate: decimal.RequireFromString("0.911294")},
+					{Date: "2023-04-20", Rate: decimal.RequireFromString("1")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, empty range",
+			args: args{
+				r:       &entity.GetCBRatesTimeSeriesResponse{},
+				req:     req,
+				country: "russia",
+			},
+			want: &entity.GetTimeSeriesResponse{
+				BaseCurrency:   "USD",
+				TargetCurrency: "EUR",
+				Rates:          []entity.TimeSeriesPoint{},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "pair is not supported on any date",
+			args: args{
+				r: timeSeries,
+				req: &entity.GetTimeSeriesRequest{
+					BaseCurrency:   "USD",
+					TargetCurrency: "XXX",
+				},
+				country: "russia",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "nil rates",
+			args: args{
+				r:   nil,
+				req: req,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "nil request",
+			args: args{
+				r:   timeSeries,
+				req: nil,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetCBRatesTimeSeriesResponseToGetTimeSeriesResponse(tt.args.r, tt.args.req, tt.args.country)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -50,6 +50,21 @@ func (mr *MockControllerMockRecorder) GetCBRates(ctx, req interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRates", reflect.TypeOf((*MockController)(nil).GetCBRates), ctx, req)
 }
 
+// GetCBRatesTimeSeries mocks base method.
+func (m *MockController) GetCBRatesTimeSeries(ctx context.Context, req *entity.GetCBRatesTimeSeriesRequest) (*entity.GetCBRatesTimeSeriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRatesTimeSeries", ctx, req)
+	ret0, _ := ret[0].(*entity.GetCBRatesTimeSeriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRatesTimeSeries indicates an expected call of GetCBRatesTimeSeries.
+func (mr *MockControllerMockRecorder) GetCBRatesTimeSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRatesTimeSeries", reflect.TypeOf((*MockController)(nil).GetCBRatesTimeSeries), ctx, req)
+}
+
 // GetExchangeRate mocks base method.
 func (m *MockController) GetExchangeRate(ctx context.Context, req *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error) {
 	m.ctrl.T.Helper()
@@ -64,3 +79,62 @@ func (mr *MockControllerMockRecorder) GetExchangeRate(ctx, req interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRate", reflect.TypeOf((*MockController)(nil).GetExchangeRate), ctx, req)
 }
+
+// GetExchangeRateBatch mocks base method.
+func (m *MockController) GetExchangeRateBatch(ctx context.Context, reqs []*entity.GetExchangeRateRequest) []entity.GetExchangeRateResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRateBatch", ctx, reqs)
+	ret0, _ := ret[0].([]entity.GetExchangeRateResult)
+	return ret0
+}
+
+// GetExchangeRateBatch indicates an expected call of GetExchangeRateBatch.
+func (mr *MockControllerMockRecorder) GetExchangeRateBatch(ctx, reqs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRateBatch", reflect.TypeOf((*MockController)(nil).GetExchangeRateBatch), ctx, reqs)
+}
+
+// GetMetals mocks base method.
+func (m *MockController) GetMetals(ctx context.Context, req *entity.GetMetalsRequest) (*entity.GetMetalsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetals", ctx, req)
+	ret0, _ := ret[0].(*entity.GetMetalsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetals indicates an expected call of GetMetals.
+func (mr *MockControllerMockRecorder) GetMetals(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetals", reflect.TypeOf((*MockController)(nil).GetMetals), ctx, req)
+}
+
+// ListBanks mocks base method.
+func (m *MockController) ListBanks(ctx context.Context) (*entity.ListBanksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBanks", ctx)
+	ret0, _ := ret[0].(*entity.ListBanksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBanks indicates an expected call of ListBanks.
+func (mr *MockControllerMockRecorder) ListBanks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBanks", reflect.TypeOf((*MockController)(nil).ListBanks), ctx)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockController) ListCurrencies(ctx context.Context) (*entity.ListCurrenciesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", ctx)
+	ret0, _ := ret[0].(*entity.ListCurrenciesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockControllerMockRecorder) ListCurrencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockController)(nil).ListCurrencies), ctx)
+}
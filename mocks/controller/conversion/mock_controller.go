@@ -35,6 +35,21 @@ func (m *MockController) EXPECT() *MockControllerMockRecorder {
 	return m.recorder
 }
 
+// Compare mocks base method.
+func (m *MockController) Compare(ctx context.Context, req *entity.ConvertCompareRequest) (*entity.ConvertCompareResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", ctx, req)
+	ret0, _ := ret[0].(*entity.ConvertCompareResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compare indicates an expected call of Compare.
+func (mr *MockControllerMockRecorder) Compare(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockController)(nil).Compare), ctx, req)
+}
+
 // Convert mocks base method.
 func (m *MockController) Convert(ctx context.Context, req *entity.ConvertCurrencyRequest) (*entity.ConvertCurrencyResponse, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +64,63 @@ func (mr *MockControllerMockRecorder) Convert(ctx, req interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockController)(nil).Convert), ctx, req)
 }
+
+// ConvertBatch mocks base method.
+func (m *MockController) ConvertBatch(ctx context.Context, req *entity.ConvertBatchRequest) (*entity.ConvertBatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertBatch", ctx, req)
+	ret0, _ := ret[0].(*entity.ConvertBatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertBatch indicates an expected call of ConvertBatch.
+func (mr *MockControllerMockRecorder) ConvertBatch(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertBatch", reflect.TypeOf((*MockController)(nil).ConvertBatch), ctx, req)
+}
+
+// ConvertMulti mocks base method.
+func (m *MockController) ConvertMulti(ctx context.Context, req *entity.ConvertMultiRequest) (*entity.ConvertMultiResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertMulti", ctx, req)
+	ret0, _ := ret[0].(*entity.ConvertMultiResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertMulti indicates an expected call of ConvertMulti.
+func (mr *MockControllerMockRecorder) ConvertMulti(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertMulti", reflect.TypeOf((*MockController)(nil).ConvertMulti), ctx, req)
+}
+
+// GetRateMatrix mocks base method.
+func (m *MockController) GetRateMatrix(ctx context.Context, req *entity.GetRateMatrixRequest) (*entity.GetRateMatrixResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRateMatrix", ctx, req)
+	ret0, _ := ret[0].(*entity.GetRateMatrixResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRateMatrix indicates an expected call of GetRateMatrix.
+func (mr *MockControllerMockRecorder) GetRateMatrix(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRateMatrix", reflect.TypeOf((*MockController)(nil).GetRateMatrix), ctx, req)
+}
+
+// GetTimeSeries mocks base method.
+func (m *MockController) GetTimeSeries(ctx context.Context, req *entity.GetTimeSeriesRequest) (*entity.GetTimeSeriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeSeries", ctx, req)
+	ret0, _ := ret[0].(*entity.GetTimeSeriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeSeries indicates an expected call of GetTimeSeries.
+func (mr *MockControllerMockRecorder) GetTimeSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeSeries", reflect.TypeOf((*MockController)(nil).GetTimeSeries), ctx, req)
+}
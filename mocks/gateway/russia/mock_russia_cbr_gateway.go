@@ -35,6 +35,51 @@ func (m *MockGateway) EXPECT() *MockGatewayMockRecorder {
 	return m.recorder
 }
 
+// GetCBRCurrencies mocks base method.
+func (m *MockGateway) GetCBRCurrencies(ctx context.Context) (map[string]entity.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRCurrencies", ctx)
+	ret0, _ := ret[0].(map[string]entity.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRCurrencies indicates an expected call of GetCBRCurrencies.
+func (mr *MockGatewayMockRecorder) GetCBRCurrencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRCurrencies", reflect.TypeOf((*MockGateway)(nil).GetCBRCurrencies), ctx)
+}
+
+// GetCBRMetals mocks base method.
+func (m *MockGateway) GetCBRMetals(ctx context.Context) (*entity.MetalPrices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRMetals", ctx)
+	ret0, _ := ret[0].(*entity.MetalPrices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRMetals indicates an expected call of GetCBRMetals.
+func (mr *MockGatewayMockRecorder) GetCBRMetals(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRMetals", reflect.TypeOf((*MockGateway)(nil).GetCBRMetals), ctx)
+}
+
+// GetCBRMetalsForDate mocks base method.
+func (m *MockGateway) GetCBRMetalsForDate(ctx context.Context, date string) (*entity.MetalPrices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRMetalsForDate", ctx, date)
+	ret0, _ := ret[0].(*entity.MetalPrices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRMetalsForDate indicates an expected call of GetCBRMetalsForDate.
+func (mr *MockGatewayMockRecorder) GetCBRMetalsForDate(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRMetalsForDate", reflect.TypeOf((*MockGateway)(nil).GetCBRMetalsForDate), ctx, date)
+}
+
 // GetCBRRates mocks base method.
 func (m *MockGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +94,33 @@ func (mr *MockGatewayMockRecorder) GetCBRRates(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRates", reflect.TypeOf((*MockGateway)(nil).GetCBRRates), ctx)
 }
+
+// GetCBRRatesForDate mocks base method.
+func (m *MockGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRRatesForDate", ctx, date)
+	ret0, _ := ret[0].(*entity.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRRatesForDate indicates an expected call of GetCBRRatesForDate.
+func (mr *MockGatewayMockRecorder) GetCBRRatesForDate(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRatesForDate", reflect.TypeOf((*MockGateway)(nil).GetCBRRatesForDate), ctx, date)
+}
+
+// GetCBRRatesTimeSeries mocks base method.
+func (m *MockGateway) GetCBRRatesTimeSeries(ctx context.Context, currencies []string, startDate, endDate string) ([]entity.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRRatesTimeSeries", ctx, currencies, startDate, endDate)
+	ret0, _ := ret[0].([]entity.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRRatesTimeSeries indicates an expected call of GetCBRRatesTimeSeries.
+func (mr *MockGatewayMockRecorder) GetCBRRatesTimeSeries(ctx, currencies, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRatesTimeSeries", reflect.TypeOf((*MockGateway)(nil).GetCBRRatesTimeSeries), ctx, currencies, startDate, endDate)
+}
@@ -49,3 +49,33 @@ func (mr *MockGatewayMockRecorder) GetCBRRates(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRates", reflect.TypeOf((*MockGateway)(nil).GetCBRRates), ctx)
 }
+
+// GetCBRRatesForDate mocks base method.
+func (m *MockGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRRatesForDate", ctx, date)
+	ret0, _ := ret[0].(*entity.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRRatesForDate indicates an expected call of GetCBRRatesForDate.
+func (mr *MockGatewayMockRecorder) GetCBRRatesForDate(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRatesForDate", reflect.TypeOf((*MockGateway)(nil).GetCBRRatesForDate), ctx, date)
+}
+
+// GetCBRRatesTimeSeries mocks base method.
+func (m *MockGateway) GetCBRRatesTimeSeries(ctx context.Context, currencies []string, startDate, endDate string) ([]entity.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRRatesTimeSeries", ctx, currencies, startDate, endDate)
+	ret0, _ := ret[0].([]entity.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRRatesTimeSeries indicates an expected call of GetCBRRatesTimeSeries.
+func (mr *MockGatewayMockRecorder) GetCBRRatesTimeSeries(ctx, currencies, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRRatesTimeSeries", reflect.TypeOf((*MockGateway)(nil).GetCBRRatesTimeSeries), ctx, currencies, startDate, endDate)
+}
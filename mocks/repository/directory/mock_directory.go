@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/directory/directory.go
+
+// Package mock_directory is a generated GoMock package.
+package mock_directory
+
+import (
+	context "context"
+	entity "my_go/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDirectory is a mock of Directory interface.
+type MockDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockDirectoryMockRecorder
+}
+
+// MockDirectoryMockRecorder is the mock recorder for MockDirectory.
+type MockDirectoryMockRecorder struct {
+	mock *MockDirectory
+}
+
+// NewMockDirectory creates a new mock instance.
+func NewMockDirectory(ctrl *gomock.Controller) *MockDirectory {
+	mock := &MockDirectory{ctrl: ctrl}
+	mock.recorder = &MockDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDirectory) EXPECT() *MockDirectoryMockRecorder {
+	return m.recorder
+}
+
+// Currencies mocks base method.
+func (m *MockDirectory) Currencies(ctx context.Context) map[string]entity.Currency {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Currencies", ctx)
+	ret0, _ := ret[0].(map[string]entity.Currency)
+	return ret0
+}
+
+// Currencies indicates an expected call of Currencies.
+func (mr *MockDirectoryMockRecorder) Currencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Currencies", reflect.TypeOf((*MockDirectory)(nil).Currencies), ctx)
+}
@@ -35,6 +35,21 @@ func (m *MockCBR) EXPECT() *MockCBRMockRecorder {
 	return m.recorder
 }
 
+// GetCBMetals mocks base method.
+func (m *MockCBR) GetCBMetals(ctx context.Context, req *entity.GetCBMetalsRequest) (*entity.GetCBMetalsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBMetals", ctx, req)
+	ret0, _ := ret[0].(*entity.GetCBMetalsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBMetals indicates an expected call of GetCBMetals.
+func (mr *MockCBRMockRecorder) GetCBMetals(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBMetals", reflect.TypeOf((*MockCBR)(nil).GetCBMetals), ctx, req)
+}
+
 // GetCBRates mocks base method.
 func (m *MockCBR) GetCBRates(ctx context.Context, req *entity.GetCBRatesRequest) (*entity.GetCBRatesResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,21 @@ func (mr *MockCBRMockRecorder) GetCBRates(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRates", reflect.TypeOf((*MockCBR)(nil).GetCBRates), ctx, req)
 }
 
+// GetCBRatesTimeSeries mocks base method.
+func (m *MockCBR) GetCBRatesTimeSeries(ctx context.Context, req *entity.GetCBRatesTimeSeriesRequest) (*entity.GetCBRatesTimeSeriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCBRatesTimeSeries", ctx, req)
+	ret0, _ := ret[0].(*entity.GetCBRatesTimeSeriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCBRatesTimeSeries indicates an expected call of GetCBRatesTimeSeries.
+func (mr *MockCBRMockRecorder) GetCBRatesTimeSeries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCBRatesTimeSeries", reflect.TypeOf((*MockCBR)(nil).GetCBRatesTimeSeries), ctx, req)
+}
+
 // GetExchangeRate mocks base method.
 func (m *MockCBR) GetExchangeRate(ctx context.Context, req *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error) {
 	m.ctrl.T.Helper()
@@ -64,3 +94,31 @@ func (mr *MockCBRMockRecorder) GetExchangeRate(ctx, req interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRate", reflect.TypeOf((*MockCBR)(nil).GetExchangeRate), ctx, req)
 }
+
+// GetExchangeRateBatch mocks base method.
+func (m *MockCBR) GetExchangeRateBatch(ctx context.Context, reqs []*entity.GetExchangeRateRequest) []entity.GetExchangeRateResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRateBatch", ctx, reqs)
+	ret0, _ := ret[0].([]entity.GetExchangeRateResult)
+	return ret0
+}
+
+// GetExchangeRateBatch indicates an expected call of GetExchangeRateBatch.
+func (mr *MockCBRMockRecorder) GetExchangeRateBatch(ctx, reqs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRateBatch", reflect.TypeOf((*MockCBR)(nil).GetExchangeRateBatch), ctx, reqs)
+}
+
+// RefreshCBRates mocks base method.
+func (m *MockCBR) RefreshCBRates(ctx context.Context, country string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCBRates", ctx, country)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshCBRates indicates an expected call of RefreshCBRates.
+func (mr *MockCBRMockRecorder) RefreshCBRates(ctx, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCBRates", reflect.TypeOf((*MockCBR)(nil).RefreshCBRates), ctx, country)
+}
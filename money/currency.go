@@ -0,0 +1,94 @@
+package money
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math/big"
+)
+
+const (
+	// MaxAmountDigits is the maximum number of digits of the amount
+	MaxAmountDigits = 38
+	// MaxAmountScale is the maximum number of decimal places of the amount including trailing zeros
+	MaxAmountScale = 18
+)
+
+// defaultMinorUnits is the number of decimal places of most of the currencies
+const defaultMinorUnits int32 = 2
+
+// minorUnits contains ISO 4217 currencies which number of minor units differs from defaultMinorUnits
+var minorUnits = map[string]int32{
+	"BHD": 3,
+	"BIF": 0,
+	"CLF": 4,
+	"CLP": 0,
+	"DJF": 0,
+	"GNF": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KMF": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"PYG": 0,
+	"RWF": 0,
+	"TND": 3,
+	"UGX": 0,
+	"UYI": 0,
+	"UYW": 4,
+	"VND": 0,
+	"VUV": 0,
+	"XAF": 0,
+	"XAG": 4, // precious metals have no minor units, troy ounces are accounted to 4 decimal places
+	"XAU": 4,
+	"XOF": 0,
+	"XPD": 4,
+	"XPF": 0,
+	"XPT": 4,
+}
+
+// MinorUnits returns the number of decimal places of the currency as defined by ISO 4217
+func MinorUnits(currency string) int32 {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+	return defaultMinorUnits
+}
+
+// CheckAmount checks that the amount has no more than MaxAmountDigits digits and MaxAmountScale decimal places,
+// so the amount can't make the decimal arithmetic arbitrarily expensive (e.g. 1e5000000 has 5000001 digits).
+// Only the exponent and the coefficient are inspected, the amount is never formatted.
+func CheckAmount(amount decimal.Decimal) error {
+	exp := amount.Exponent()
+	if exp < -MaxAmountScale {
+		return fmt.Errorf("amount has more than %d decimal places", MaxAmountScale)
+	}
+	if exp > MaxAmountDigits {
+		return fmt.Errorf("amount has more than %d digits", MaxAmountDigits)
+	}
+	// the amount has coefficient digits followed by exp zeros
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MaxAmountDigits-max32(exp, 0))), nil)
+	if amount.Coefficient().CmpAbs(limit) >= 0 {
+		return fmt.Errorf("amount has more than %d digits", MaxAmountDigits)
+	}
+	return nil
+}
+
+func max32(a int32, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// CheckMinorUnits checks that the amount of the currency has no more decimal places than the currency allows
+func CheckMinorUnits(amount decimal.Decimal, currency string) error {
+	units := MinorUnits(currency)
+	if !amount.Equal(amount.Truncate(units)) {
+		return fmt.Errorf("amount %s has more than %d decimal places allowed for %s", amount, units, currency)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package money
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, int32(2), MinorUnits("USD"))
+	assert.Equal(t, int32(0), MinorUnits("JPY"))
+	assert.Equal(t, int32(3), MinorUnits("KWD"))
+	assert.Equal(t, int32(2), MinorUnits("unknown"))
+}
+
+func TestCheckMinorUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		currency  string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			amount:    "19.99",
+			currency:  "USD",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, trailing zeros",
+			amount:    "1500.000",
+			currency:  "JPY",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, large amount",
+			amount:    "123456789012345678901234567890.125",
+			currency:  "KWD",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "too many decimal places",
+			amount:    "19.999",
+			currency:  "USD",
+			assertion: assert.Error,
+		},
+		{
+			name:      "fractional amount of currency without minor units",
+			amount:    "0.5",
+			currency:  "JPY",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion(t, CheckMinorUnits(decimal.RequireFromString(tt.amount), tt.currency))
+		})
+	}
+}
+
+func TestCheckAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			amount:    "19.99",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, max digits",
+			amount:    "-" + strings.Repeat("9", MaxAmountDigits-2) + ".99",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, max digits with exponent",
+			amount:    "1e37",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "too many digits",
+			amount:    strings.Repeat("9", MaxAmountDigits+1),
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many digits with exponent",
+			amount:    "1e38",
+			assertion: assert.Error,
+		},
+		{
+			name:      "huge exponent",
+			amount:    "1e5000000",
+			assertion: assert.Error,
+		},
+		{
+			name:      "huge negative exponent",
+			amount:    "1e-5000000",
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many trailing zeros",
+			amount:    "1." + strings.Repeat("0", MaxAmountScale+1),
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion(t, CheckAmount(decimal.RequireFromString(tt.amount)))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package money contains the helpers for exact decimal arithmetic of currency amounts and exchange rates.
+// All the decimals produced by the package are in canonical form (no trailing zeros, zero value for zero),
+// so equal values have equal representation.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+// RatePrecision is the number of decimal places the calculated rates and amounts are rounded to
+const RatePrecision int32 = 4
+
+// RateSignificantDigits is the minimum number of significant digits the calculated rates are rounded to,
+// so the rates of the currencies with small unit value (e.g. USD for 1 VND) keep their precision
+const RateSignificantDigits int32 = 6
+
+var two = decimal.NewFromInt(2)
+
+// Parse converts the decimal string to decimal. Both dot and comma are accepted as decimal separator
+// as central banks use both.
+func Parse(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(strings.Replace(strings.TrimSpace(s), ",", ".", 1))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("bad decimal %q: %s", s, err)
+	}
+	return Normalize(d), nil
+}
+
+// Normalize returns the canonical form of the decimal
+func Normalize(d decimal.Decimal) decimal.Decimal {
+	if d.IsZero() {
+		return decimal.Decimal{}
+	}
+	return decimal.RequireFromString(d.String())
+}
+
+// Quo returns num / den rounded half to even to the provided number of decimal places.
+// Quotient is calculated exactly, so the result doesn't depend on any intermediate precision.
+func Quo(num decimal.Decimal, den decimal.Decimal, places int32) (decimal.Decimal, error) {
+	return QuoRound(num, den, places, HalfEven)
+}
+
+// QuoRate returns the exchange rate num / den rounded half to even to RatePrecision decimal places
+// or to RateSignificantDigits significant digits, whichever keeps more digits.
+func QuoRate(num decimal.Decimal, den decimal.Decimal) (decimal.Decimal, error) {
+	if den.IsZero() {
+		return decimal.Decimal{}, errors.New("division by zero")
+	}
+	if num.IsZero() {
+		return decimal.Decimal{}, nil
+	}
+	return Quo(num, den, max32(RatePrecision, RateSignificantDigits-1-quotientMagnitude(num, den)))
+}
+
+// QuoRound returns num / den rounded to the provided number of decimal places with the rounding mode provided.
+// Quotient is calculated exactly, so the result is rounded only once.
+func QuoRound(num decimal.Decimal, den decimal.Decimal, places int32, mode RoundingMode) (decimal.Decimal, error) {
+	if den.IsZero() {
+		return decimal.Decimal{}, errors.New("division by zero")
+	}
+	q, r := num.QuoRem(den, places)
+	if !r.IsZero() && mode != Down {
+		// compare the remainder with the half of the last digit unit of the quotient
+		unit := decimal.New(1, -places)
+		switch r.Abs().Mul(two).Cmp(den.Abs().Mul(unit)) {
+		case 1:
+			q = q.Add(unit.Mul(quotientSign(num, den)))
+		case 0:
+			if mode == HalfUp || q.Shift(places).BigInt().Bit(0) == 1 {
+				q = q.Add(unit.Mul(quotientSign(num, den)))
+			}
+		}
+	}
+	return Normalize(q), nil
+}
+
+// quotientMagnitude returns the exponent of the leading digit of num / den, i.e. floor(log10(|num / den|))
+func quotientMagnitude(num decimal.Decimal, den decimal.Decimal) int32 {
+	num, den = num.Abs(), den.Abs()
+	m := magnitude(num) - magnitude(den)
+	// the leading digits of num may be less than the ones of den
+	if num.Cmp(den.Shift(m)) < 0 {
+		m--
+	}
+	return m
+}
+
+// magnitude returns the exponent of the leading digit of non-zero d
+func magnitude(d decimal.Decimal) int32 {
+	return int32(d.NumDigits()) - 1 + d.Exponent()
+}
+
+func quotientSign(num decimal.Decimal, den decimal.Decimal) decimal.Decimal {
+	return decimal.NewFromInt(int64(num.Sign() * den.Sign()))
+}
+
+var half = decimal.New(5, -1)
+
+// Average returns the exact arithmetic mean of a and b
+func Average(a decimal.Decimal, b decimal.Decimal) decimal.Decimal {
+	return Normalize(a.Add(b).Mul(half))
+}
@@ -0,0 +1,286 @@
+package money
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		want      decimal.Decimal
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, dot",
+			s:         "34.0625",
+			want:      decimal.RequireFromString("34.0625"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, comma and trailing zeros",
+			s:         " 48,0160 ",
+			want:      decimal.RequireFromString("48.016"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "zero",
+			s:         "0.000",
+			want:      decimal.Decimal{},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "bad decimal",
+			s:         "12,3,4",
+			want:      decimal.Decimal{},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, decimal.RequireFromString("42.5171"), Normalize(decimal.RequireFromString("42.51710000")))
+	assert.Equal(t, decimal.RequireFromString("100"), Normalize(decimal.New(1, 2)))
+	assert.Equal(t, decimal.Decimal{}, Normalize(decimal.Zero))
+}
+
+func TestQuo(t *testing.T) {
+	tests := []struct {
+		name      string
+		num       string
+		den       string
+		places    int32
+		want      decimal.Decimal
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "exact",
+			num:       "10",
+			den:       "4",
+			places:    4,
+			want:      decimal.RequireFromString("2.5"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "rounded down",
+			num:       "1",
+			den:       "3",
+			places:    4,
+			want:      decimal.RequireFromString("0.3333"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "rounded up",
+			num:       "2",
+			den:       "3",
+			places:    4,
+			want:      decimal.RequireFromString("0.6667"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "half to even, down",
+			num:       "0.00125",
+			den:       "1",
+			places:    4,
+			want:      decimal.RequireFromString("0.0012"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "half to even, up",
+			num:       "0.00135",
+			den:       "1",
+			places:    4,
+			want:      decimal.RequireFromString("0.0014"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "negative half to even",
+			num:       "-0.00135",
+			den:       "1",
+			places:    4,
+			want:      decimal.RequireFromString("-0.0014"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "negative rounded",
+			num:       "2",
+			den:       "-3",
+			places:    4,
+			want:      decimal.RequireFromString("-0.6667"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "large values",
+			num:       "123456789012345678901234567890",
+			den:       "7",
+			places:    2,
+			want:      decimal.RequireFromString("17636684144620811271604938270"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "division by zero",
+			num:       "1",
+			den:       "0",
+			places:    4,
+			want:      decimal.Decimal{},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Quo(decimal.RequireFromString(tt.num), decimal.RequireFromString(tt.den), tt.places)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQuoRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		num       string
+		den       string
+		want      decimal.Decimal
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "large rate rounded to decimal places",
+			num:       "24000",
+			den:       "0.97",
+			want:      decimal.RequireFromString("24742.268"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "rate above 1",
+			num:       "81.5556",
+			den:       "0.6067",
+			want:      decimal.RequireFromString("134.4249"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "rate below 1",
+			num:       "1",
+			den:       "3",
+			want:      decimal.RequireFromString("0.333333"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "small rate rounded to significant digits",
+			num:       "1",
+			den:       "81.5556",
+			want:      decimal.RequireFromString("0.0122616"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "tiny rate",
+			num:       "0.97",
+			den:       "24000",
+			want:      decimal.RequireFromString("0.0000404167"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "leading digits of numerator less than the ones of denominator",
+			num:       "-1",
+			den:       "0.2",
+			want:      decimal.RequireFromString("-5"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "exact power of ten",
+			num:       "1",
+			den:       "1000",
+			want:      decimal.RequireFromString("0.001"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "zero",
+			num:       "0",
+			den:       "3",
+			want:      decimal.Decimal{},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "division by zero",
+			num:       "1",
+			den:       "0",
+			want:      decimal.Decimal{},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QuoRate(decimal.RequireFromString(tt.num), decimal.RequireFromString(tt.den))
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQuoRound(t *testing.T) {
+	tests := []struct {
+		name string
+		num  string
+		den  string
+		mode RoundingMode
+		want decimal.Decimal
+	}{
+		{
+			name: "half even, tie",
+			num:  "0.125",
+			den:  "1",
+			mode: HalfEven,
+			want: decimal.RequireFromString("0.12"),
+		},
+		{
+			name: "half up, tie",
+			num:  "0.125",
+			den:  "1",
+			mode: HalfUp,
+			want: decimal.RequireFromString("0.13"),
+		},
+		{
+			name: "half up, negative tie",
+			num:  "-0.125",
+			den:  "1",
+			mode: HalfUp,
+			want: decimal.RequireFromString("-0.13"),
+		},
+		{
+			name: "half up, below half",
+			num:  "0.1249",
+			den:  "1",
+			mode: HalfUp,
+			want: decimal.RequireFromString("0.12"),
+		},
+		{
+			name: "down",
+			num:  "2",
+			den:  "3",
+			mode: Down,
+			want: decimal.RequireFromString("0.66"),
+		},
+		{
+			name: "down, negative",
+			num:  "-2",
+			den:  "3",
+			mode: Down,
+			want: decimal.RequireFromString("-0.66"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QuoRound(decimal.RequireFromString(tt.num), decimal.RequireFromString(tt.den), 2, tt.mode)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package money
+
+import "fmt"
+
+// RoundingMode defines how the amounts are rounded to the required number of decimal places
+type RoundingMode string
+
+const (
+	// HalfEven rounds to the nearest value, ties are rounded to the even digit (banker's rounding)
+	HalfEven RoundingMode = "half_even"
+	// HalfUp rounds to the nearest value, ties are rounded away from zero
+	HalfUp RoundingMode = "half_up"
+	// Down truncates the extra digits (rounds towards zero)
+	Down RoundingMode = "down"
+)
+
+// ParseRoundingMode converts the rounding mode name to RoundingMode. Empty name means HalfEven.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch RoundingMode(s) {
+	case "":
+		return HalfEven, nil
+	case HalfEven, HalfUp, Down:
+		return RoundingMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q, expected one of %s, %s, %s", s, HalfEven, HalfUp, Down)
+	}
+}
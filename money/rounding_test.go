@@ -0,0 +1,47 @@
+package money
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRoundingMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		want      RoundingMode
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "default",
+			s:         "",
+			want:      HalfEven,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "half up",
+			s:         "half_up",
+			want:      HalfUp,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "down",
+			s:         "down",
+			want:      Down,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "unknown",
+			s:         "ceiling",
+			want:      "",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoundingMode(tt.s)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -4,19 +4,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"go.uber.org/config"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+	internalconfig "my_go/config"
 	"my_go/entity"
 	"my_go/gateway"
-	"my_go/gateway/russia"
-	"my_go/gateway/thailand"
-	"my_go/mapper"
+	"my_go/repository/storage"
 	"sync"
 	"time"
 )
 
+const staleRatesConfigKey = "stale_rates"
+
 type CBR interface {
 	GetCBRates(ctx context.Context, req *entity.GetCBRatesRequest) (*entity.GetCBRatesResponse, error)
 	GetExchangeRate(ctx context.Context, req *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error)
+	GetExchangeRateBatch(ctx context.Context, reqs []*entity.GetExchangeRateRequest) []entity.GetExchangeRateResult
+	GetCBRatesTimeSeries(
+		ctx context.Context,
+		req *entity.GetCBRatesTimeSeriesRequest,
+	) (*entity.GetCBRatesTimeSeriesResponse, error)
+	RefreshCBRates(ctx context.Context, country string) error
+	GetCBMetals(ctx context.Context, req *entity.GetCBMetalsRequest) (*entity.GetCBMetalsResponse, error)
 }
 
 // Compile time check that cbr implements CBR interface
@@ -26,49 +36,121 @@ var _ CBR = (*cbr)(nil)
 type Params struct {
 	fx.In
 
-	ThaiGateway   thailand.Gateway
-	RussiaGateway russia.Gateway
+	Lifecycle fx.Lifecycle
+	Config    config.Provider
+	Logger    *zap.Logger
+	Storage   storage.Storage
+	Registry  *gateway.Registry
 }
 
 type cbr struct {
 	sync.RWMutex
 
+	Logger     *zap.Logger
 	TimeNow    func() time.Time
-	Gateways   map[string]gateway.CBGateway    // maps country to respective gateway
-	RatesCache map[string]entity.ExchangeRates // maps country to ExchangeRatesObject
+	After      func(d time.Duration) <-chan time.Time // used to wait between background retries
+	Storage    storage.Storage                        // persists every loaded snapshot of rates by country and date
+	StaleRates internalconfig.StaleRatesConfig        // policy of serving the last known rates
+	Gateways   map[string]gateway.CBGateway           // maps country to respective gateway
+	Countries  []string                               // countries in the registry order, triangulation candidates
+	RatesCache map[string]entity.ExchangeRates        // maps country to ExchangeRatesObject
+
+	MetalsGateways map[string]gateway.MetalsCBGateway // maps country to the gateway of precious metals prices
+	MetalsCache    map[string]entity.MetalPrices      // maps country to the latest precious metals prices
+
+	refreshes  flightGroup     // coalesces concurrent refreshes of the cached rates by country
+	background context.Context // context of background retries, cancelled on stop
+	retryMu    sync.Mutex
+	retrying   map[string]bool // countries with background retry in progress
 }
 
-// New is a constructor for the CBR interface
+// New is a constructor for the CBR interface. Central banks are discovered from the gateway registry.
 // Cache implementation is leveraging the fact that fx module that provides this constructor
 // is called in lazy manner. Meaning once interface was created it will be re-used.
 // Hence in memory cache will be kept in a proper state.
+// On top of in memory cache every loaded snapshot of rates is persisted in the storage,
+// so the rates survive restarts and historical rates are loaded from the central bank only once.
+// If central bank is unreachable the last known rates are served according to the stale rates policy
+// while the rates are reloaded in background until the repository is stopped.
 func New(p Params) (CBR, error) {
-	return &cbr{
-		TimeNow: time.Now,
-		Gateways: map[string]gateway.CBGateway{
-			entity.Russia:   p.RussiaGateway,
-			entity.Thailand: p.ThaiGateway,
-		},
-		RatesCache: map[string]entity.ExchangeRates{
-			entity.Russia:   {},
-			entity.Thailand: {},
+	var staleRates internalconfig.StaleRatesConfig
+	if err := p.Config.Get(staleRatesConfigKey).Populate(&staleRates); err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	background, cancel := context.WithCancel(context.Background())
+	p.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			return nil
 		},
+	})
+	gateways := make(map[string]gateway.CBGateway)
+	countries := make([]string, 0, len(p.Registry.Registrations()))
+	ratesCache := make(map[string]entity.ExchangeRates)
+	metalsGateways := make(map[string]gateway.MetalsCBGateway)
+	for _, reg := range p.Registry.Registrations() {
+		gateways[reg.Country] = reg.Gateway
+		countries = append(countries, reg.Country)
+		ratesCache[reg.Country] = entity.ExchangeRates{}
+		if mgw, ok := reg.Gateway.(gateway.MetalsCBGateway); ok {
+			metalsGateways[reg.Country] = mgw
+		}
+	}
+	return &cbr{
+		Logger:     p.Logger,
+		TimeNow:    time.Now,
+		After:      time.After,
+		Storage:    p.Storage,
+		StaleRates: staleRates,
+		background: background,
+		Gateways:   gateways,
+		Countries:  countries,
+		RatesCache: ratesCache,
+
+		MetalsGateways: metalsGateways,
+		MetalsCache:    make(map[string]entity.MetalPrices),
 	}, nil
 }
 
 // GetCBRates loads central bank rates for the central bank of the country provided in the request
 // if country is not implemented it fails (assuming no central bank = no rates).
+// If date is provided in the request the rates set by central bank for that date are loaded.
 func (c *cbr) GetCBRates(ctx context.Context, req *entity.GetCBRatesRequest) (*entity.GetCBRatesResponse, error) {
 	if req == nil {
 		return nil, errors.New("nil GetCBRatesRequest")
 	}
+	if req.Date != "" {
+		return c.getHistoricalRates(ctx, req.Country, req.Date)
+	}
 	c.RLock()
 	cachedRates, ok := c.RatesCache[req.Country]
 	c.RUnlock()
 
 	if !ok || c.needsRefresh(&cachedRates) {
+		// central bank is known to be unreachable, background retry will reload the rates
+		if c.isRetrying(req.Country) {
+			if stale, ok := c.staleRates(ctx, req.Country); ok {
+				return &entity.GetCBRatesResponse{
+					Rates: stale,
+					Stale: true,
+				}, nil
+			}
+		}
 		if err := c.reloadCache(ctx, req.Country); err != nil {
-			return nil, fmt.Errorf("failed to reload rates: %s", err)
+			if ctx.Err() != nil {
+				// the caller gave up, the central bank is not known to be unreachable
+				return nil, fmt.Errorf("failed to reload rates: %s", err)
+			}
+			stale, ok := c.staleRates(ctx, req.Country)
+			if !ok {
+				return nil, fmt.Errorf("failed to reload rates: %s", err)
+			}
+			c.logger("GetCBRates").Warnf("serving stale %s rates for %s: %s", req.Country, stale.DateLoaded, err)
+			c.retryInBackground(req.Country)
+			return &entity.GetCBRatesResponse{
+				Rates: stale,
+				Stale: true,
+			}, nil
 		}
 		c.RLock()
 		cachedRates, ok = c.RatesCache[req.Country]
@@ -83,35 +165,77 @@ func (c *cbr) GetCBRates(ctx context.Context, req *entity.GetCBRatesRequest) (*e
 	}, nil
 }
 
+// GetExchangeRate calculates the exchange rate of the currency pair with the rates of the central bank.
+// Precious metals (ISO 4217 codes e.g. XAU) are priced with the metal prices of the same central bank.
 func (c *cbr) GetExchangeRate(ctx context.Context, req *entity.GetExchangeRateRequest) (*entity.GetExchangeRateResponse, error) {
-	if req == nil {
-		return nil, errors.New("nil GetExchangeRateRequest")
+	return newSnapshots(c).exchangeRate(ctx, req)
+}
+
+// GetExchangeRateBatch calculates the exchange rates of all the requests, the result of every request is returned
+// at the same index. Rates and metal prices of every central bank and date are loaded once per batch,
+// so all the requests to the same central bank are calculated with the same snapshot of rates.
+// Failure of a request doesn't affect the other requests.
+func (c *cbr) GetExchangeRateBatch(
+	ctx context.Context,
+	reqs []*entity.GetExchangeRateRequest,
+) []entity.GetExchangeRateResult {
+	s := newSnapshots(c)
+	res := make([]entity.GetExchangeRateResult, 0, len(reqs))
+	for _, req := range reqs {
+		resp, err := s.exchangeRate(ctx, req)
+		res = append(res, entity.GetExchangeRateResult{
+			Response: resp,
+			Err:      err,
+		})
 	}
-	rates, err := c.GetCBRates(ctx, &entity.GetCBRatesRequest{
-		Country: req.Country,
+	return res
+}
+
+// reloadCache refreshes the cached rates of the country. Concurrent refreshes of the same country
+// are coalesced so the central bank is requested only once, while the refreshes of different
+// countries never wait for each other. Cache lock is held only to read or replace the cached rates
+// and never for the duration of the network call. The refresh is not cancelled along with ctx,
+// so the caller that gives up doesn't fail the other callers waiting for the same refresh.
+func (c *cbr) reloadCache(ctx context.Context, country string) error {
+	return c.refreshes.Do(ctx, country, func(ctx context.Context) error {
+		return c.refresh(ctx, country)
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to load exchange rates for CB %s, err: %s", req.Country, err)
+}
+
+func (c *cbr) refresh(ctx context.Context, country string) error {
+	c.RLock()
+	cachedRates, ok := c.RatesCache[country]
+	c.RUnlock()
+	if ok && !c.needsRefresh(&cachedRates) {
+		return nil
 	}
-	resp, err := mapper.CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(rates.Rates, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert rates and request to response: %s", err)
+	gw, ok := c.Gateways[country]
+	if !ok {
+		return fmt.Errorf("provided country %s unsupported", country)
+	}
+	// after restart the rates loaded by the previous process might still be actual
+	if stored, err := c.Storage.Latest(ctx, country); err == nil && !c.needsRefresh(stored) {
+		c.setRates(country, stored)
+		return nil
 	}
-	return resp, nil
+	return c.loadRates(ctx, country, gw)
 }
 
-func (c *cbr) reloadCache(ctx context.Context, country string) error {
-	c.Lock()
-	defer c.Unlock()
-	if rates, ok := c.RatesCache[country]; ok {
-		if !c.needsRefresh(&rates) {
-			return nil
-		}
-	}
+// RefreshCBRates loads the rates of the country from the central bank and replaces the cached ones
+// even if the cached rates are still actual. It is used to pick up the rates as soon as they are published.
+// Refresh is coalesced with the concurrent reloads of the cache for the same country.
+func (c *cbr) RefreshCBRates(ctx context.Context, country string) error {
 	gw, ok := c.Gateways[country]
 	if !ok {
 		return fmt.Errorf("provided country %s unsupported", country)
 	}
+	return c.refreshes.Do(ctx, country, func(ctx context.Context) error {
+		return c.loadRates(ctx, country, gw)
+	})
+}
+
+// loadRates loads the rates from the central bank gateway, caches and persists them
+func (c *cbr) loadRates(ctx context.Context, country string, gw gateway.CBGateway) error {
 	rates, err := gw.GetCBRRates(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load %s central bank data: %s", country, err)
@@ -119,19 +243,142 @@ func (c *cbr) reloadCache(ctx context.Context, country string) error {
 	if rates == nil {
 		return fmt.Errorf("nil rates returned from central bank %s with no error", country)
 	}
-	c.RatesCache[country] = *rates
+	c.setRates(country, rates)
+	c.saveRates(ctx, rates)
 	return nil
 }
 
+func (c *cbr) setRates(country string, rates *entity.ExchangeRates) {
+	c.Lock()
+	defer c.Unlock()
+	c.RatesCache[country] = *rates
+}
+
+// GetCBRatesTimeSeries loads central bank rates for every business day of the requested range.
+// If gateway is able to load the whole range at once it is used, otherwise the rates are loaded
+// date by date as historical rates skipping the weekends.
+func (c *cbr) GetCBRatesTimeSeries(
+	ctx context.Context,
+	req *entity.GetCBRatesTimeSeriesRequest,
+) (*entity.GetCBRatesTimeSeriesResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil GetCBRatesTimeSeriesRequest")
+	}
+	gw, ok := c.Gateways[req.Country]
+	if !ok {
+		return nil, fmt.Errorf("provided country %s unsupported", req.Country)
+	}
+	if tsgw, ok := gw.(gateway.TimeSeriesCBGateway); ok {
+		rates, err := tsgw.GetCBRRatesTimeSeries(ctx, req.Currencies, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s central bank time series: %s", req.Country, err)
+		}
+		return &entity.GetCBRatesTimeSeriesResponse{
+			Rates: rates,
+		}, nil
+	}
+
+	start, err := time.Parse(entity.DateLayout, req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad start date %s, err %s", req.StartDate, err)
+	}
+	end, err := time.Parse(entity.DateLayout, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("bad end date %s, err %s", req.EndDate, err)
+	}
+	var rates []entity.ExchangeRates
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		r, err := c.getHistoricalRates(ctx, req.Country, d.Format(entity.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *r.Rates)
+	}
+	return &entity.GetCBRatesTimeSeriesResponse{
+		Rates: rates,
+	}, nil
+}
+
+// getHistoricalRates loads the rates of the central bank for the provided date.
+// Rates for the past dates never change, hence once loaded they are kept in storage forever.
+// Rates for the current (or future) date in the central bank timezone are not stored
+// as central bank might still publish the update for them.
+func (c *cbr) getHistoricalRates(ctx context.Context, country string, date string) (*entity.GetCBRatesResponse, error) {
+	stored, err := c.Storage.Load(ctx, country, date)
+	if err == nil {
+		return &entity.GetCBRatesResponse{
+			Rates: stored,
+		}, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		c.logger("getHistoricalRates").Errorf("failed to load %s rates for %s from storage: %s", country, date, err)
+	}
+
+	gw, ok := c.Gateways[country]
+	if !ok {
+		return nil, fmt.Errorf("provided country %s unsupported", country)
+	}
+	hgw, ok := gw.(gateway.HistoricalCBGateway)
+	if !ok {
+		return nil, fmt.Errorf("historical rates are not supported for country %s", country)
+	}
+	rates, err := hgw.GetCBRRatesForDate(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s central bank data for %s: %s", country, date, err)
+	}
+	if rates == nil {
+		return nil, fmt.Errorf("nil rates returned from central bank %s with no error", country)
+	}
+	if rates.TimeZone != nil && date < c.TimeNow().In(rates.TimeZone).Format(entity.DateLayout) {
+		c.saveRates(ctx, rates)
+	}
+	return &entity.GetCBRatesResponse{
+		Rates: rates,
+	}, nil
+}
+
+// saveRates persists the rates in the storage. Failure to persist the rates doesn't fail the request
+// as the rates are still valid and can be served, hence the error is only logged.
+func (c *cbr) saveRates(ctx context.Context, rates *entity.ExchangeRates) {
+	if err := c.Storage.Save(ctx, rates); err != nil {
+		c.logger("saveRates").Errorf("failed to save %s rates for %s: %s", rates.Country, rates.DateLoaded, err)
+	}
+}
+
+func (c *cbr) logger(function string) *zap.SugaredLogger {
+	return c.Logger.With(
+		zap.String("scope", "repository"),
+		zap.String("function", function),
+	).Sugar()
+}
+
+// needsRefresh reports whether the rates are no longer actual for the current date in the central bank timezone.
 func (c *cbr) needsRefresh(r *entity.ExchangeRates) bool {
 	if r == nil {
 		return true
 	}
-	if r.DateLoaded == "" {
+	return c.outdated(r.DateLoaded, r.EffectiveDate, r.TimeZone)
+}
+
+// outdated reports whether the data of the central bank loaded for dateLoaded and set for effectiveDate
+// is no longer actual for the current date in the central bank timezone.
+func (c *cbr) outdated(dateLoaded string, effectiveDate string, tz *time.Location) bool {
+	if dateLoaded == "" {
 		return true
 	}
-	if c.TimeNow().In(r.TimeZone).Format(entity.DateLayout) == r.DateLoaded {
-		return false
+	return validThrough(dateLoaded, effectiveDate) < c.TimeNow().In(tz).Format(entity.DateLayout)
+}
+
+// validThrough returns the last date the rates are actual for. Rates are actual for the date they were loaded for
+// even if they were set for an earlier date (e.g. on weekend) as no newer rates exist yet.
+// If central bank already set them for a later date (e.g. the next day rates of bank of Russia)
+// they stay actual till that date.
+func validThrough(dateLoaded string, effectiveDate string) string {
+	if effectiveDate > dateLoaded {
+		return effectiveDate
 	}
-	return true
+	return dateLoaded
 }
@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
 	"my_go/entity"
 	"my_go/gateway"
 	russiagatewaymock "my_go/mocks/gateway/russia"
 	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"my_go/repository/storage"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -18,12 +24,29 @@ func TestNew(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
 	mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
+	provider, _ := config.NewYAML(config.Source(strings.NewReader(`{"stale_rates":{"max_staleness":{"russia":"72h"}}}`)))
+	registry, err := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{Bank: gateway.Bank{Country: entity.Russia}, Gateway: mockRussiaCB},
+			{Bank: gateway.Bank{Country: entity.Thailand}, Gateway: mockThailandCB},
+		},
+	})
+	assert.NoError(t, err)
 	c, err := New(Params{
-		ThaiGateway:   mockRussiaCB,
-		RussiaGateway: mockThailandCB,
+		Lifecycle: fxtest.NewLifecycle(t),
+		Config:    provider,
+		Registry:  registry,
 	})
 	assert.NoError(t, err)
-	assert.NotNil(t, c)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, map[string]gateway.CBGateway{
+			entity.Russia:   mockRussiaCB,
+			entity.Thailand: mockThailandCB,
+		}, c.(*cbr).Gateways)
+		assert.Equal(t, map[string]gateway.MetalsCBGateway{
+			entity.Russia: mockRussiaCB,
+		}, c.(*cbr).MetalsGateways)
+	}
 }
 
 func Test_cbr_GetCBRates(t *testing.T) {
@@ -42,13 +65,13 @@ func Test_cbr_GetCBRates(t *testing.T) {
 				Nominal:          100,
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "USD",
-				RateTargetToBase: 123.56,
+				RateTargetToBase: decimal.RequireFromString("123.56"),
 			},
 			"JPY": {
 				Nominal:          10,
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "JPY",
-				RateTargetToBase: 987.65,
+				RateTargetToBase: decimal.RequireFromString("987.65"),
 			},
 		},
 	}
@@ -60,19 +83,19 @@ func Test_cbr_GetCBRates(t *testing.T) {
 		Rates: map[string]entity.Rate{
 			"THB": {
 				Nominal:          1,
-				RateTargetToBase: 1,
+				RateTargetToBase: decimal.RequireFromString("1"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "THB",
 			},
 			"GBP": {
 				Nominal:          1,
-				RateTargetToBase: 42.5171,
+				RateTargetToBase: decimal.RequireFromString("42.5171"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "GBP",
 			},
 			"USD": {
 				Nominal:          1,
-				RateTargetToBase: 34.28235,
+				RateTargetToBase: decimal.RequireFromString("34.28235"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "USD",
 			},
@@ -215,7 +238,7 @@ func Test_cbr_GetCBRates(t *testing.T) {
 			if tt.mockRussiaCBGateway != nil {
 				mockRussiaCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					MaxTimes(tt.expectedNumOfGatewayCalls.RussiaGetCBRates).
 					MinTimes(tt.expectedNumOfGatewayCalls.RussiaGetCBRates).
 					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
@@ -224,7 +247,7 @@ func Test_cbr_GetCBRates(t *testing.T) {
 			if tt.mockThailandCBGateway != nil {
 				mockThailandCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					MaxTimes(tt.expectedNumOfGatewayCalls.ThailandGetCBRates).
 					MinTimes(tt.expectedNumOfGatewayCalls.ThailandGetCBRates).
 					Return(tt.mockThailandCBGateway.res, tt.mockThailandCBGateway.err)
@@ -238,6 +261,8 @@ func Test_cbr_GetCBRates(t *testing.T) {
 					entity.Thailand: mockThailandCB,
 				},
 				RatesCache: tt.fields.RatesCache,
+				Storage:    storage.NewMemory(),
+				Logger:     zap.NewNop(),
 			}
 			got, err := c.GetCBRates(ctx, tt.args.req)
 
@@ -256,19 +281,19 @@ func Test_cbr_needsRefresh(t *testing.T) {
 		Rates: map[string]entity.Rate{
 			"THB": {
 				Nominal:          1,
-				RateTargetToBase: 1,
+				RateTargetToBase: decimal.RequireFromString("1"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "THB",
 			},
 			"GBP": {
 				Nominal:          1,
-				RateTargetToBase: 42.5171,
+				RateTargetToBase: decimal.RequireFromString("42.5171"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "GBP",
 			},
 			"USD": {
 				Nominal:          1,
-				RateTargetToBase: 34.28235,
+				RateTargetToBase: decimal.RequireFromString("34.28235"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "USD",
 			},
@@ -313,6 +338,54 @@ func Test_cbr_needsRefresh(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "Next day rates loaded yesterday do not need refresh",
+			fields: fields{
+				TimeNow: func() time.Time {
+					return time.Date(2023, 4, 19, 9, 0, 0, 0, thTZ)
+				},
+			},
+			args: args{
+				r: &entity.ExchangeRates{
+					DateLoaded:    "2023-04-18",
+					EffectiveDate: "2023-04-19",
+					TimeZone:      thTZ,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Rates set for Saturday loaded on Sunday do not need refresh",
+			fields: fields{
+				TimeNow: func() time.Time {
+					return time.Date(2023, 4, 16, 9, 0, 0, 0, thTZ)
+				},
+			},
+			args: args{
+				r: &entity.ExchangeRates{
+					DateLoaded:    "2023-04-16",
+					EffectiveDate: "2023-04-15",
+					TimeZone:      thTZ,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Rates set for Saturday loaded on Saturday need refresh on Sunday",
+			fields: fields{
+				TimeNow: func() time.Time {
+					return time.Date(2023, 4, 16, 9, 0, 0, 0, thTZ)
+				},
+			},
+			args: args{
+				r: &entity.ExchangeRates{
+					DateLoaded:    "2023-04-15",
+					EffectiveDate: "2023-04-15",
+					TimeZone:      thTZ,
+				},
+			},
+			want: true,
+		},
 		{
 			name: "nil rates",
 			fields: fields{
@@ -350,6 +423,8 @@ func Test_cbr_needsRefresh(t *testing.T) {
 					entity.Thailand: mockThailandCB,
 				},
 				RatesCache: tt.fields.RatesCache,
+				Storage:    storage.NewMemory(),
+				Logger:     zap.NewNop(),
 			}
 			assert.Equal(t, tt.want, c.needsRefresh(tt.args.r))
 		})
@@ -368,19 +443,19 @@ func Test_cbr_reloadCache(t *testing.T) {
 		Rates: map[string]entity.Rate{
 			"RUB": {
 				Nominal:          1,
-				RateTargetToBase: 1,
+				RateTargetToBase: decimal.RequireFromString("1"),
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "RUB",
 			},
 			"GBP": {
 				Nominal:          1,
-				RateTargetToBase: 42.5171,
+				RateTargetToBase: decimal.RequireFromString("42.5171"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "GBP",
 			},
 			"USD": {
 				Nominal:          1,
-				RateTargetToBase: 34.28235,
+				RateTargetToBase: decimal.RequireFromString("34.28235"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "USD",
 			},
@@ -394,19 +469,19 @@ func Test_cbr_reloadCache(t *testing.T) {
 		Rates: map[string]entity.Rate{
 			"RUB": {
 				Nominal:          1,
-				RateTargetToBase: 1,
+				RateTargetToBase: decimal.RequireFromString("1"),
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "RUB",
 			},
 			"GBP": {
 				Nominal:          1,
-				RateTargetToBase: 42.5171,
+				RateTargetToBase: decimal.RequireFromString("42.5171"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "GBP",
 			},
 			"USD": {
 				Nominal:          1,
-				RateTargetToBase: 34.28235,
+				RateTargetToBase: decimal.RequireFromString("34.28235"),
 				BaseCurrency:     "THB",
 				TargetCurrency:   "USD",
 			},
@@ -430,7 +505,9 @@ func Test_cbr_reloadCache(t *testing.T) {
 		args                  args
 		mockRussiaCBGateway   *mockCBGateway
 		mockThailandCBGateway *mockCBGateway
+		stored                *entity.ExchangeRates
 		expectedCache         map[string]entity.ExchangeRates
+		expectedStored        *entity.ExchangeRates
 		assertion             assert.ErrorAssertionFunc
 	}{
 		{
@@ -473,6 +550,46 @@ func Test_cbr_reloadCache(t *testing.T) {
 			},
 			assertion: assert.NoError,
 		},
+		{
+			name: "Happy path. Rates are not in cache, actual rates are in storage",
+			fields: fields{
+				TimeNow: func() time.Time {
+					return time.Unix(100000000, 0)
+				},
+				RatesCache: map[string]entity.ExchangeRates{},
+			},
+			stored: &otherRates,
+			args: args{
+				country: "thailand",
+			},
+			expectedCache: map[string]entity.ExchangeRates{
+				"thailand": otherRates,
+			},
+			expectedStored: &otherRates,
+			assertion:      assert.NoError,
+		},
+		{
+			name: "Happy path. Rates are not in cache, outdated rates are in storage",
+			fields: fields{
+				TimeNow: func() time.Time {
+					return time.Unix(100000000, 0)
+				},
+				RatesCache: map[string]entity.ExchangeRates{},
+			},
+			stored: &rates,
+			mockThailandCBGateway: &mockCBGateway{
+				res: &otherRates,
+				err: nil,
+			},
+			args: args{
+				country: "thailand",
+			},
+			expectedCache: map[string]entity.ExchangeRates{
+				"thailand": otherRates,
+			},
+			expectedStored: &otherRates,
+			assertion:      assert.NoError,
+		},
 		{
 			name: "Happy path. Rates already in cache and need refresh. Thailand",
 			fields: fields{
@@ -565,16 +682,20 @@ func Test_cbr_reloadCache(t *testing.T) {
 			if tt.mockRussiaCBGateway != nil {
 				mockRussiaCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
 			}
 			mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
 			if tt.mockThailandCBGateway != nil {
 				mockThailandCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					Return(tt.mockThailandCBGateway.res, tt.mockThailandCBGateway.err)
 			}
+			st := storage.NewMemory()
+			if tt.stored != nil {
+				assert.NoError(t, st.Save(ctx, tt.stored))
+			}
 			c := &cbr{
 				RWMutex: sync.RWMutex{},
 				TimeNow: tt.fields.TimeNow,
@@ -583,9 +704,16 @@ func Test_cbr_reloadCache(t *testing.T) {
 					entity.Thailand: mockThailandCB,
 				},
 				RatesCache: tt.fields.RatesCache,
+				Storage:    st,
+				Logger:     zap.NewNop(),
 			}
 			tt.assertion(t, c.reloadCache(ctx, tt.args.country))
 			assert.Equal(t, tt.expectedCache, c.RatesCache)
+			if tt.expectedStored != nil {
+				stored, err := st.Latest(ctx, tt.expectedStored.Country)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStored, stored)
+			}
 		})
 	}
 }
@@ -601,19 +729,19 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 		Rates: map[string]entity.Rate{
 			"RUB": {
 				Nominal:          1,
-				RateTargetToBase: 1,
+				RateTargetToBase: decimal.RequireFromString("1"),
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "RUB",
 			},
 			"GBP": {
 				Nominal:          1,
-				RateTargetToBase: 42.5171,
+				RateTargetToBase: decimal.RequireFromString("42.5171"),
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "GBP",
 			},
 			"USD": {
 				Nominal:          1,
-				RateTargetToBase: 34.28235,
+				RateTargetToBase: decimal.RequireFromString("34.28235"),
 				BaseCurrency:     "RUB",
 				TargetCurrency:   "USD",
 			},
@@ -655,16 +783,18 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 					Country:          "russia",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
+					Places:           2,
 				},
 			},
 			want: &entity.GetExchangeRateResponse{
 				Rate: entity.Rate{
-					Nominal:          10,
+					Nominal:          1,
 					BaseCurrency:     "GBP",
 					TargetCurrency:   "USD",
-					RateTargetToBase: 12.402,
+					RateTargetToBase: decimal.RequireFromString("1.2402"),
 				},
+				Amount: decimal.RequireFromString("12.4"),
 			},
 			assertion: assert.NoError,
 		},
@@ -698,7 +828,7 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 					Country:          "some random country",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
 				},
 			},
 			assertion: assert.Error,
@@ -716,7 +846,7 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 					Country:          "russia",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
 				},
 			},
 			mockRussiaCBGateway: &mockCBGateway{
@@ -738,7 +868,7 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 					Country:          "russia",
 					BaseCurrencyID:   "GBP",
 					TargetCurrencyID: "USD",
-					Amount:           10,
+					Amount:           decimal.RequireFromString("10"),
 				},
 			},
 			mockRussiaCBGateway: &mockCBGateway{
@@ -757,14 +887,14 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 			if tt.mockRussiaCBGateway != nil {
 				mockRussiaCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
 			}
 			mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
 			if tt.mockThailandCBGateway != nil {
 				mockThailandCB.
 					EXPECT().
-					GetCBRRates(ctx).
+					GetCBRRates(gomock.Any()).
 					Return(tt.mockThailandCBGateway.res, tt.mockThailandCBGateway.err)
 			}
 			c := &cbr{
@@ -775,6 +905,8 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 					entity.Thailand: mockThailandCB,
 				},
 				RatesCache: tt.fields.RatesCache,
+				Storage:    storage.NewMemory(),
+				Logger:     zap.NewNop(),
 			}
 			got, err := c.GetExchangeRate(ctx, tt.args.req)
 			tt.assertion(t, err)
@@ -782,3 +914,640 @@ func Test_cbr_GetExchangeRate(t *testing.T) {
 		})
 	}
 }
+
+func Test_cbr_GetExchangeRate_firstCallerCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	release := make(chan struct{})
+	started := make(chan struct{})
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).DoAndReturn(func(ctx context.Context) (*entity.ExchangeRates, error) {
+		close(started)
+		<-release
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &entity.ExchangeRates{
+			Country:       "russia",
+			DateLoaded:    "1970-01-01",
+			EffectiveDate: "1970-01-01",
+			TimeZone:      ruTZ,
+			Rates: map[string]entity.Rate{
+				"RUB": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "RUB", RateTargetToBase: decimal.RequireFromString("1")},
+				"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("80")},
+			},
+		}, nil
+	}).Times(1)
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return time.Unix(100, 0)
+		},
+		Gateways: map[string]gateway.CBGateway{
+			entity.Russia: mockRussiaCB,
+		},
+		RatesCache: map[string]entity.ExchangeRates{},
+		Storage:    storage.NewMemory(),
+		Logger:     zap.NewNop(),
+	}
+	req := &entity.GetExchangeRateRequest{
+		Country:          "russia",
+		BaseCurrencyID:   "USD",
+		TargetCurrencyID: "RUB",
+		Amount:           decimal.RequireFromString("10"),
+		Places:           2,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.GetExchangeRate(ctx, req)
+		first <- err
+	}()
+	<-started
+	wg := &sync.WaitGroup{}
+	results := make(chan entity.GetExchangeRateResult, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.GetExchangeRate(context.Background(), req)
+			results <- entity.GetExchangeRateResult{Response: res, Err: err}
+		}()
+	}
+	// the waiting callers join the refresh started by the first caller, which gives up
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	assert.ErrorContains(t, <-first, context.Canceled.Error())
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for r := range results {
+		assert.NoError(t, r.Err)
+		if assert.NotNil(t, r.Response) {
+			assert.Equal(t, "800", r.Response.Amount.String())
+		}
+	}
+}
+
+func Test_cbr_GetExchangeRateBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).Return(&entity.ExchangeRates{
+		Country:       "russia",
+		DateLoaded:    "1970-01-01",
+		EffectiveDate: "1970-01-01",
+		TimeZone:      ruTZ,
+		Rates: map[string]entity.Rate{
+			"RUB": {
+				Nominal:          1,
+				RateTargetToBase: decimal.RequireFromString("1"),
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "RUB",
+			},
+			"USD": {
+				Nominal:          1,
+				RateTargetToBase: decimal.RequireFromString("80"),
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "USD",
+			},
+		},
+	}, nil).Times(1)
+	mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
+	mockThailandCB.EXPECT().GetCBRRates(gomock.Any()).Return(nil, errors.New("timeout")).Times(1)
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return time.Unix(100, 0)
+		},
+		Gateways: map[string]gateway.CBGateway{
+			entity.Russia:   mockRussiaCB,
+			entity.Thailand: mockThailandCB,
+		},
+		RatesCache: map[string]entity.ExchangeRates{},
+		Storage:    storage.NewMemory(),
+		Logger:     zap.NewNop(),
+	}
+	got := c.GetExchangeRateBatch(ctx, []*entity.GetExchangeRateRequest{
+		{Country: "russia", BaseCurrencyID: "USD", TargetCurrencyID: "RUB", Amount: decimal.RequireFromString("10"), Places: 2},
+		{Country: "thailand", BaseCurrencyID: "USD", TargetCurrencyID: "THB", Amount: decimal.RequireFromString("1"), Places: 2},
+		{Country: "russia", BaseCurrencyID: "ABC", TargetCurrencyID: "RUB", Amount: decimal.RequireFromString("1"), Places: 2},
+		nil,
+		{Country: "russia", BaseCurrencyID: "RUB", TargetCurrencyID: "USD", Amount: decimal.RequireFromString("40"), Places: 2},
+		{Country: "thailand", BaseCurrencyID: "THB", TargetCurrencyID: "USD", Amount: decimal.RequireFromString("1"), Places: 2},
+	})
+	if assert.Len(t, got, 6) {
+		assert.NoError(t, got[0].Err)
+		assert.Equal(t, &entity.GetExchangeRateResponse{
+			Rate: entity.Rate{
+				Nominal:          1,
+				BaseCurrency:     "USD",
+				TargetCurrency:   "RUB",
+				RateTargetToBase: decimal.RequireFromString("80"),
+			},
+			Amount:        decimal.RequireFromString("800"),
+			EffectiveDate: "1970-01-01",
+		}, got[0].Response)
+		assert.Error(t, got[1].Err)
+		assert.Nil(t, got[1].Response)
+		assert.Error(t, got[2].Err)
+		assert.Error(t, got[3].Err)
+		assert.NoError(t, got[4].Err)
+		if assert.NotNil(t, got[4].Response) {
+			assert.Equal(t, "0.5", got[4].Response.Amount.String())
+		}
+		// the failure of the bank is not retried within the batch
+		assert.Error(t, got[5].Err)
+	}
+}
+
+func Test_cbr_GetCBRates_historical(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	historicalRates := entity.ExchangeRates{
+		Country:    "russia",
+		DateLoaded: "2023-04-18",
+		TimeZone:   ruTZ,
+		Rates: map[string]entity.Rate{
+			"USD": {
+				Nominal:          1,
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "USD",
+				RateTargetToBase: decimal.RequireFromString("81.6101"),
+			},
+		},
+	}
+	todayRates := historicalRates
+	todayRates.DateLoaded = "2023-04-20"
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 20, 12, 0, 0, 0, ruTZ)
+	}
+
+	type mockCBGateway struct {
+		res *entity.ExchangeRates
+		err error
+	}
+	tests := []struct {
+		name                string
+		req                 *entity.GetCBRatesRequest
+		stored              *entity.ExchangeRates
+		mockRussiaCBGateway *mockCBGateway
+		want                *entity.GetCBRatesResponse
+		expectedStored      *entity.ExchangeRates
+		assertion           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, rates loaded from gateway and stored",
+			req: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-18",
+			},
+			mockRussiaCBGateway: &mockCBGateway{
+				res: &historicalRates,
+			},
+			want: &entity.GetCBRatesResponse{
+				Rates: &historicalRates,
+			},
+			expectedStored: &historicalRates,
+			assertion:      assert.NoError,
+		},
+		{
+			name: "Happy path, rates loaded from storage",
+			req: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-18",
+			},
+			stored: &historicalRates,
+			want: &entity.GetCBRatesResponse{
+				Rates: &historicalRates,
+			},
+			expectedStored: &historicalRates,
+			assertion:      assert.NoError,
+		},
+		{
+			name: "Happy path, rates for the current date are not stored",
+			req: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-20",
+			},
+			mockRussiaCBGateway: &mockCBGateway{
+				res: &todayRates,
+			},
+			want: &entity.GetCBRatesResponse{
+				Rates: &todayRates,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "country not supported",
+			req: &entity.GetCBRatesRequest{
+				Country: "some country",
+				Date:    "2023-04-18",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "gateway fails",
+			req: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-18",
+			},
+			mockRussiaCBGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "gateway returns nil and no error",
+			req: &entity.GetCBRatesRequest{
+				Country: "russia",
+				Date:    "2023-04-18",
+			},
+			mockRussiaCBGateway: &mockCBGateway{},
+			want:                nil,
+			assertion:           assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			if tt.mockRussiaCBGateway != nil {
+				mockRussiaCB.
+					EXPECT().
+					GetCBRRatesForDate(ctx, tt.req.Date).
+					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
+			}
+			st := storage.NewMemory()
+			if tt.stored != nil {
+				assert.NoError(t, st.Save(ctx, tt.stored))
+			}
+			c := &cbr{
+				RWMutex: sync.RWMutex{},
+				TimeNow: timeNow,
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia: mockRussiaCB,
+				},
+				RatesCache: map[string]entity.ExchangeRates{},
+				Storage:    st,
+				Logger:     zap.NewNop(),
+			}
+			got, err := c.GetCBRates(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			stored, _ := st.Load(ctx, tt.req.Country, tt.req.Date)
+			assert.Equal(t, tt.expectedStored, stored)
+		})
+	}
+}
+
+func Test_cbr_GetCBRates_historicalAfterNextDayRatesLoaded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	rates := func(dateLoaded string, effectiveDate string, usd string) *entity.ExchangeRates {
+		return &entity.ExchangeRates{
+			Country:       "russia",
+			DateLoaded:    dateLoaded,
+			EffectiveDate: effectiveDate,
+			TimeZone:      ruTZ,
+			Rates: map[string]entity.Rate{
+				"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString(usd)},
+			},
+		}
+	}
+	// in the afternoon bank of Russia publishes the rates set for the next day
+	nextDayRates := rates("2023-04-19", "2023-04-20", "81.6101")
+	dayRates := rates("2023-04-19", "2023-04-19", "81.5556")
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).Return(nextDayRates, nil).Times(1)
+	mockRussiaCB.EXPECT().GetCBRRatesForDate(ctx, "2023-04-19").Return(dayRates, nil).Times(1)
+	now := time.Date(2023, 4, 19, 16, 1, 0, 0, ruTZ)
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return now
+		},
+		Gateways: map[string]gateway.CBGateway{
+			entity.Russia: mockRussiaCB,
+		},
+		RatesCache: map[string]entity.ExchangeRates{},
+		Storage:    storage.NewMemory(),
+		Logger:     zap.NewNop(),
+	}
+	assert.NoError(t, c.RefreshCBRates(ctx, "russia"))
+	now = time.Date(2023, 4, 21, 12, 0, 0, 0, ruTZ)
+
+	got, err := c.GetCBRates(ctx, &entity.GetCBRatesRequest{Country: "russia", Date: "2023-04-19"})
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.GetCBRatesResponse{Rates: dayRates}, got)
+	// the rates loaded in advance are served for the date they are set for
+	got, err = c.GetCBRates(ctx, &entity.GetCBRatesRequest{Country: "russia", Date: "2023-04-20"})
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.GetCBRatesResponse{Rates: nextDayRates}, got)
+}
+
+// historicalGateway implements gateway.HistoricalCBGateway without time series support
+type historicalGateway struct {
+	rates map[string]*entity.ExchangeRates
+}
+
+func (g *historicalGateway) GetCBRRates(ctx context.Context) (*entity.ExchangeRates, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (g *historicalGateway) GetCBRRatesForDate(ctx context.Context, date string) (*entity.ExchangeRates, error) {
+	r, ok := g.rates[date]
+	if !ok {
+		return nil, errors.New("no rates")
+	}
+	return r, nil
+}
+
+func Test_cbr_GetCBRatesTimeSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	ratesForDate := func(date string) *entity.ExchangeRates {
+		return &entity.ExchangeRates{
+			Country:    "russia",
+			DateLoaded: date,
+			TimeZone:   ruTZ,
+			Rates: map[string]entity.Rate{
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("81.6101"),
+				},
+			},
+		}
+	}
+	type mockCBGateway struct {
+		res []entity.ExchangeRates
+		err error
+	}
+	tests := []struct {
+		name                string
+		req                 *entity.GetCBRatesTimeSeriesRequest
+		historicalOnly      bool
+		mockRussiaCBGateway *mockCBGateway
+		want                *entity.GetCBRatesTimeSeriesResponse
+		assertion           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, gateway supports time series",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "russia",
+				Currencies: []string{"USD", "RUB"},
+				StartDate:  "2023-04-14",
+				EndDate:    "2023-04-17",
+			},
+			mockRussiaCBGateway: &mockCBGateway{
+				res: []entity.ExchangeRates{*ratesForDate("2023-04-14"), *ratesForDate("2023-04-15")},
+			},
+			want: &entity.GetCBRatesTimeSeriesResponse{
+				Rates: []entity.ExchangeRates{*ratesForDate("2023-04-14"), *ratesForDate("2023-04-15")},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, gateway supports only historical rates, weekend is skipped",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "russia",
+				Currencies: []string{"USD", "RUB"},
+				StartDate:  "2023-04-14",
+				EndDate:    "2023-04-17",
+			},
+			historicalOnly: true,
+			want: &entity.GetCBRatesTimeSeriesResponse{
+				Rates: []entity.ExchangeRates{*ratesForDate("2023-04-14"), *ratesForDate("2023-04-17")},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "gateway supports only historical rates, rates for business day are missing",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "russia",
+				Currencies: []string{"USD", "RUB"},
+				StartDate:  "2023-04-14",
+				EndDate:    "2023-04-18",
+			},
+			historicalOnly: true,
+			want:           nil,
+			assertion:      assert.Error,
+		},
+		{
+			name: "gateway supports only historical rates, bad start date",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:   "russia",
+				StartDate: "14.04.2023",
+				EndDate:   "2023-04-18",
+			},
+			historicalOnly: true,
+			want:           nil,
+			assertion:      assert.Error,
+		},
+		{
+			name: "gateway supports only historical rates, bad end date",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:   "russia",
+				StartDate: "2023-04-14",
+				EndDate:   "18.04.2023",
+			},
+			historicalOnly: true,
+			want:           nil,
+			assertion:      assert.Error,
+		},
+		{
+			name: "gateway fails",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country:    "russia",
+				Currencies: []string{"USD", "RUB"},
+				StartDate:  "2023-04-14",
+				EndDate:    "2023-04-17",
+			},
+			mockRussiaCBGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "country not supported",
+			req: &entity.GetCBRatesTimeSeriesRequest{
+				Country: "some country",
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			if tt.mockRussiaCBGateway != nil {
+				mockRussiaCB.
+					EXPECT().
+					GetCBRRatesTimeSeries(ctx, tt.req.Currencies, tt.req.StartDate, tt.req.EndDate).
+					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
+			}
+			var gw gateway.CBGateway = mockRussiaCB
+			if tt.historicalOnly {
+				gw = &historicalGateway{
+					rates: map[string]*entity.ExchangeRates{
+						"2023-04-14": ratesForDate("2023-04-14"),
+						"2023-04-17": ratesForDate("2023-04-17"),
+					},
+				}
+			}
+			c := &cbr{
+				RWMutex: sync.RWMutex{},
+				TimeNow: func() time.Time {
+					return time.Date(2023, 4, 20, 12, 0, 0, 0, ruTZ)
+				},
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia: gw,
+				},
+				RatesCache: map[string]entity.ExchangeRates{},
+				Storage:    storage.NewMemory(),
+				Logger:     zap.NewNop(),
+			}
+			got, err := c.GetCBRatesTimeSeries(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cbr_RefreshCBRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 16, 0, 0, 0, ruTZ)
+	}
+	cached := entity.ExchangeRates{
+		Country:    "russia",
+		DateLoaded: "2023-04-19",
+		TimeZone:   ruTZ,
+		Rates: map[string]entity.Rate{
+			"USD": {
+				Nominal:          1,
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "USD",
+				RateTargetToBase: decimal.RequireFromString("81.6101"),
+			},
+		},
+	}
+	published := &entity.ExchangeRates{
+		Country:    "russia",
+		DateLoaded: "2023-04-19",
+		TimeZone:   ruTZ,
+		Rates: map[string]entity.Rate{
+			"USD": {
+				Nominal:          1,
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "USD",
+				RateTargetToBase: decimal.RequireFromString("81.7371"),
+			},
+		},
+	}
+	type mockCBGateway struct {
+		res *entity.ExchangeRates
+		err error
+	}
+	tests := []struct {
+		name                string
+		country             string
+		mockRussiaCBGateway *mockCBGateway
+		expectedCache       map[string]entity.ExchangeRates
+		expectedStored      *entity.ExchangeRates
+		assertion           assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "Happy path, actual rates are replaced",
+			country: "russia",
+			mockRussiaCBGateway: &mockCBGateway{
+				res: published,
+			},
+			expectedCache: map[string]entity.ExchangeRates{
+				"russia": *published,
+			},
+			expectedStored: published,
+			assertion:      assert.NoError,
+		},
+		{
+			name:    "gateway error, cache is kept",
+			country: "russia",
+			mockRussiaCBGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			expectedCache: map[string]entity.ExchangeRates{
+				"russia": cached,
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:                "nil rates with no error",
+			country:             "russia",
+			mockRussiaCBGateway: &mockCBGateway{},
+			expectedCache: map[string]entity.ExchangeRates{
+				"russia": cached,
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:    "unsupported country",
+			country: "atlantis",
+			expectedCache: map[string]entity.ExchangeRates{
+				"russia": cached,
+			},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			if tt.mockRussiaCBGateway != nil {
+				mockRussiaCB.
+					EXPECT().
+					GetCBRRates(gomock.Any()).
+					Return(tt.mockRussiaCBGateway.res, tt.mockRussiaCBGateway.err)
+			}
+			st := storage.NewMemory()
+			c := &cbr{
+				TimeNow: timeNow,
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia: mockRussiaCB,
+				},
+				RatesCache: map[string]entity.ExchangeRates{
+					"russia": cached,
+				},
+				Storage: st,
+				Logger:  zap.NewNop(),
+			}
+			tt.assertion(t, c.RefreshCBRates(ctx, tt.country))
+			assert.Equal(t, tt.expectedCache, c.RatesCache)
+			if tt.expectedStored != nil {
+				stored, err := st.Latest(ctx, tt.expectedStored.Country)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStored, stored)
+			}
+		})
+	}
+}
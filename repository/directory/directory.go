@@ -0,0 +1,176 @@
+package directory
+
+import (
+	"context"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"my_go/entity"
+	"my_go/gateway"
+	"my_go/money"
+	"sync"
+	"time"
+)
+
+const (
+	// seedTimeout limits the seeding, as it is detached from the contexts of the callers
+	seedTimeout = 30 * time.Second
+	// seedRetryBackoff is the period the partial reference data is served for before the central banks
+	// that failed are requested again
+	seedRetryBackoff = time.Minute
+)
+
+// Directory is an interface of the reference data of currencies
+type Directory interface {
+	// Currencies returns the reference data of all the known currencies by ISO 4217 alphabetic code.
+	// The result is a copy, so it is safe to modify it.
+	Currencies(ctx context.Context) map[string]entity.Currency
+}
+
+// Compile time check that directory implements Directory interface
+var _ Directory = (*directory)(nil)
+
+// Params is a container for all Directory dependencies
+type Params struct {
+	fx.In
+
+	Logger   *zap.Logger
+	Registry *gateway.Registry
+}
+
+type directory struct {
+	sync.RWMutex
+
+	TimeNow  func() time.Time
+	Logger   *zap.Logger
+	Sources  map[string]gateway.CurrenciesCBGateway // maps country to the gateway publishing the reference data
+	Cache    map[string]entity.Currency             // merged reference data, nil until the first seeding is done
+	Complete bool                                   // whether every source was loaded into Cache
+	RetryAt  time.Time                              // the sources are not requested again until then if Cache is partial
+
+	seeding chan struct{} // closed when the running seeding is done, nil if no seeding is running
+}
+
+// New is a constructor for the Directory interface. The directory is based on the bundled ISO 4217 table
+// and is seeded lazily with the reference data of every registered central bank that publishes it.
+func New(p Params) Directory {
+	sources := make(map[string]gateway.CurrenciesCBGateway)
+	for _, reg := range p.Registry.Registrations() {
+		if src, ok := reg.Gateway.(gateway.CurrenciesCBGateway); ok {
+			sources[reg.Country] = src
+		}
+	}
+	return &directory{
+		TimeNow: time.Now,
+		Logger:  p.Logger,
+		Sources: sources,
+	}
+}
+
+// Currencies returns the bundled ISO 4217 table merged with the reference data of central banks.
+// English names and codes of ISO 4217 take precedence, central banks fill the Russian names, nominals and
+// the missing data, currencies unknown to ISO 4217 are added. Concurrent callers share a single seeding that
+// is not cancelled along with ctx, if ctx is done first the reference data loaded so far is returned
+// (the bundled ISO 4217 table before the first seeding). The result is cached forever if every central bank
+// responded, otherwise the partial result is served for seedRetryBackoff before the central banks are requested again.
+func (d *directory) Currencies(ctx context.Context) map[string]entity.Currency {
+	d.Lock()
+	if d.Cache != nil && (d.Complete || d.TimeNow().Before(d.RetryAt)) {
+		defer d.Unlock()
+		return clone(d.Cache)
+	}
+	done := d.seeding
+	if done == nil {
+		done = make(chan struct{})
+		d.seeding = done
+		go d.seed(done)
+	}
+	d.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	d.RLock()
+	defer d.RUnlock()
+	if d.Cache == nil {
+		return isoCurrencies()
+	}
+	return clone(d.Cache)
+}
+
+// seed requests the reference data of every source and caches the merged result, done is closed afterwards
+func (d *directory) seed(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), seedTimeout)
+	defer cancel()
+
+	m := isoCurrencies()
+	complete := true
+	for country, src := range d.Sources {
+		currencies, err := src.GetCBRCurrencies(ctx)
+		if err != nil {
+			d.logger("seed").Warnf("failed to load %s currencies: %s", country, err)
+			complete = false
+			continue
+		}
+		for code, c := range currencies {
+			m[code] = merge(m[code], c)
+		}
+	}
+	for code, c := range m {
+		c.Code = code
+		c.MinorUnits = money.MinorUnits(code)
+		m[code] = c
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.Cache = m
+	d.Complete = complete
+	if !complete {
+		d.RetryAt = d.TimeNow().Add(seedRetryBackoff)
+	}
+	d.seeding = nil
+	close(done)
+}
+
+// isoCurrencies returns a copy of the bundled ISO 4217 table
+func isoCurrencies() map[string]entity.Currency {
+	m := make(map[string]entity.Currency, len(iso4217))
+	for _, c := range iso4217 {
+		c.MinorUnits = money.MinorUnits(c.Code)
+		m[c.Code] = c
+	}
+	return m
+}
+
+func (d *directory) logger(function string) *zap.SugaredLogger {
+	return d.Logger.With(
+		zap.String("scope", "directory"),
+		zap.String("function", function),
+	).Sugar()
+}
+
+// merge fills the empty fields of the base currency with the ones published by the central bank
+func merge(base entity.Currency, c entity.Currency) entity.Currency {
+	if base.NumericCode == "" {
+		base.NumericCode = c.NumericCode
+	}
+	if base.Name == "" {
+		base.Name = c.Name
+	}
+	if base.NameRu == "" {
+		base.NameRu = c.NameRu
+	}
+	if base.Nominal == 0 {
+		base.Nominal = c.Nominal
+	}
+	return base
+}
+
+func clone(m map[string]entity.Currency) map[string]entity.Currency {
+	res := make(map[string]entity.Currency, len(m))
+	for code, c := range m {
+		res[code] = c
+	}
+	return res
+}
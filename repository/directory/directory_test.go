@@ -0,0 +1,193 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"my_go/entity"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
+	registry, err := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{Bank: gateway.Bank{Country: entity.Russia}, Gateway: mockRussiaCB},
+			{Bank: gateway.Bank{Country: entity.Thailand}, Gateway: mockThailandCB},
+		},
+	})
+	assert.NoError(t, err)
+	d := New(Params{
+		Logger:   zap.NewNop(),
+		Registry: registry,
+	})
+	if assert.NotNil(t, d) {
+		assert.Equal(t, map[string]gateway.CurrenciesCBGateway{
+			entity.Russia: mockRussiaCB,
+		}, d.(*directory).Sources)
+	}
+}
+
+func Test_directory_Currencies(t *testing.T) {
+	ctx := context.Background()
+	ruCurrencies := map[string]entity.Currency{
+		"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1},
+		"JPY": {Code: "JPY", NumericCode: "392", Name: "Japanese Yen", NameRu: "Японская иена", Nominal: 100},
+		"XXB": {Code: "XXB", NumericCode: "999", Name: "Test Unit", NameRu: "Тестовая единица", Nominal: 10},
+	}
+	tests := []struct {
+		name        string
+		setup       func(m *russiagatewaymock.MockGateway)
+		calls       int
+		wantCurrent map[string]entity.Currency
+		wantCached  bool
+	}{
+		{
+			name: "Happy path, merged and cached",
+			setup: func(m *russiagatewaymock.MockGateway) {
+				m.EXPECT().GetCBRCurrencies(gomock.Any()).Return(ruCurrencies, nil).Times(1)
+			},
+			calls: 2,
+			wantCurrent: map[string]entity.Currency{
+				"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1, MinorUnits: 2},
+				"JPY": {Code: "JPY", NumericCode: "392", Name: "Yen", NameRu: "Японская иена", Nominal: 100, MinorUnits: 0},
+				"XXB": {Code: "XXB", NumericCode: "999", Name: "Test Unit", NameRu: "Тестовая единица", Nominal: 10, MinorUnits: 2},
+				"THB": {Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2},
+			},
+			wantCached: true,
+		},
+		{
+			name: "central bank is unreachable, ISO 4217 returned and not requested again until backoff elapses",
+			setup: func(m *russiagatewaymock.MockGateway) {
+				m.EXPECT().GetCBRCurrencies(gomock.Any()).Return(nil, errors.New("timeout")).Times(1)
+			},
+			calls: 2,
+			wantCurrent: map[string]entity.Currency{
+				"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2},
+				"JPY": {Code: "JPY", NumericCode: "392", Name: "Yen", MinorUnits: 0},
+				"THB": {Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2},
+			},
+			wantCached: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := russiagatewaymock.NewMockGateway(ctrl)
+			tt.setup(m)
+			d := &directory{
+				TimeNow: func() time.Time {
+					return time.Unix(100, 0)
+				},
+				Logger:  zap.NewNop(),
+				Sources: map[string]gateway.CurrenciesCBGateway{entity.Russia: m},
+			}
+			var got map[string]entity.Currency
+			for i := 0; i < tt.calls; i++ {
+				got = d.Currencies(ctx)
+			}
+			for code, want := range tt.wantCurrent {
+				assert.Equal(t, want, got[code], code)
+			}
+			_, ok := got["XXB"]
+			assert.Equal(t, tt.wantCached, ok)
+			assert.Equal(t, tt.wantCached, d.Complete)
+
+			// the result is a copy, so the cached reference data can't be modified by the caller
+			got["USD"] = entity.Currency{}
+			assert.Equal(t, "840", d.Currencies(ctx)["USD"].NumericCode)
+		})
+	}
+}
+
+func Test_directory_Currencies_retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	m := russiagatewaymock.NewMockGateway(ctrl)
+	gomock.InOrder(
+		m.EXPECT().GetCBRCurrencies(gomock.Any()).Return(nil, errors.New("timeout")),
+		m.EXPECT().GetCBRCurrencies(gomock.Any()).Return(map[string]entity.Currency{
+			"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1},
+		}, nil),
+	)
+	now := time.Unix(100, 0)
+	d := &directory{
+		TimeNow: func() time.Time {
+			return now
+		},
+		Logger:  zap.NewNop(),
+		Sources: map[string]gateway.CurrenciesCBGateway{entity.Russia: m},
+	}
+
+	assert.Empty(t, d.Currencies(ctx)["USD"].NameRu)
+	now = now.Add(seedRetryBackoff - time.Second)
+	assert.Empty(t, d.Currencies(ctx)["USD"].NameRu)
+	now = now.Add(time.Second)
+	assert.Equal(t, "Доллар США", d.Currencies(ctx)["USD"].NameRu)
+	assert.True(t, d.Complete)
+}
+
+func Test_directory_Currencies_concurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	m := russiagatewaymock.NewMockGateway(ctrl)
+	m.EXPECT().GetCBRCurrencies(gomock.Any()).DoAndReturn(func(ctx context.Context) (map[string]entity.Currency, error) {
+		close(started)
+		<-release
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return map[string]entity.Currency{
+			"USD": {Code: "USD", NumericCode: "840", Name: "US Dollar", NameRu: "Доллар США", Nominal: 1},
+		}, nil
+	}).Times(1)
+	d := &directory{
+		TimeNow: func() time.Time {
+			return time.Unix(100, 0)
+		},
+		Logger:  zap.NewNop(),
+		Sources: map[string]gateway.CurrenciesCBGateway{entity.Russia: m},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan map[string]entity.Currency, 1)
+	go func() {
+		first <- d.Currencies(ctx)
+	}()
+	<-started
+	wg := &sync.WaitGroup{}
+	results := make(chan map[string]entity.Currency, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- d.Currencies(context.Background())
+		}()
+	}
+	// the caller that gives up gets the bundled ISO 4217 table without waiting for the central bank
+	cancel()
+	got := <-first
+	assert.Equal(t, entity.Currency{Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2}, got["USD"])
+	// the waiting callers join the seeding started by the first caller
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for got := range results {
+		assert.Equal(t, "Доллар США", got["USD"].NameRu)
+	}
+}
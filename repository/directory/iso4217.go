@@ -0,0 +1,170 @@
+package directory
+
+import "my_go/entity"
+
+// iso4217 contains alphabetic and numeric codes and English names of the active ISO 4217 currencies,
+// including precious metals and special drawing rights. It is the base of the directory the reference data
+// published by central banks is merged into.
+var iso4217 = []entity.Currency{
+	{Code: "AED", NumericCode: "784", Name: "UAE Dirham"},
+	{Code: "AFN", NumericCode: "971", Name: "Afghani"},
+	{Code: "ALL", NumericCode: "008", Name: "Lek"},
+	{Code: "AMD", NumericCode: "051", Name: "Armenian Dram"},
+	{Code: "ANG", NumericCode: "532", Name: "Netherlands Antillean Guilder"},
+	{Code: "AOA", NumericCode: "973", Name: "Kwanza"},
+	{Code: "ARS", NumericCode: "032", Name: "Argentine Peso"},
+	{Code: "AUD", NumericCode: "036", Name: "Australian Dollar"},
+	{Code: "AWG", NumericCode: "533", Name: "Aruban Florin"},
+	{Code: "AZN", NumericCode: "944", Name: "Azerbaijan Manat"},
+	{Code: "BAM", NumericCode: "977", Name: "Convertible Mark"},
+	{Code: "BBD", NumericCode: "052", Name: "Barbados Dollar"},
+	{Code: "BDT", NumericCode: "050", Name: "Taka"},
+	{Code: "BGN", NumericCode: "975", Name: "Bulgarian Lev"},
+	{Code: "BHD", NumericCode: "048", Name: "Bahraini Dinar"},
+	{Code: "BIF", NumericCode: "108", Name: "Burundi Franc"},
+	{Code: "BMD", NumericCode: "060", Name: "Bermudian Dollar"},
+	{Code: "BND", NumericCode: "096", Name: "Brunei Dollar"},
+	{Code: "BOB", NumericCode: "068", Name: "Boliviano"},
+	{Code: "BRL", NumericCode: "986", Name: "Brazilian Real"},
+	{Code: "BSD", NumericCode: "044", Name: "Bahamian Dollar"},
+	{Code: "BTN", NumericCode: "064", Name: "Ngultrum"},
+	{Code: "BWP", NumericCode: "072", Name: "Pula"},
+	{Code: "BYN", NumericCode: "933", Name: "Belarusian Ruble"},
+	{Code: "BZD", NumericCode: "084", Name: "Belize Dollar"},
+	{Code: "CAD", NumericCode: "124", Name: "Canadian Dollar"},
+	{Code: "CDF", NumericCode: "976", Name: "Congolese Franc"},
+	{Code: "CHF", NumericCode: "756", Name: "Swiss Franc"},
+	{Code: "CLF", NumericCode: "990", Name: "Unidad de Fomento"},
+	{Code: "CLP", NumericCode: "152", Name: "Chilean Peso"},
+	{Code: "CNY", NumericCode: "156", Name: "Yuan Renminbi"},
+	{Code: "COP", NumericCode: "170", Name: "Colombian Peso"},
+	{Code: "CRC", NumericCode: "188", Name: "Costa Rican Colon"},
+	{Code: "CUP", NumericCode: "192", Name: "Cuban Peso"},
+	{Code: "CVE", NumericCode: "132", Name: "Cabo Verde Escudo"},
+	{Code: "CZK", NumericCode: "203", Name: "Czech Koruna"},
+	{Code: "DJF", NumericCode: "262", Name: "Djibouti Franc"},
+	{Code: "DKK", NumericCode: "208", Name: "Danish Krone"},
+	{Code: "DOP", NumericCode: "214", Name: "Dominican Peso"},
+	{Code: "DZD", NumericCode: "012", Name: "Algerian Dinar"},
+	{Code: "EGP", NumericCode: "818", Name: "Egyptian Pound"},
+	{Code: "ERN", NumericCode: "232", Name: "Nakfa"},
+	{Code: "ETB", NumericCode: "230", Name: "Ethiopian Birr"},
+	{Code: "EUR", NumericCode: "978", Name: "Euro"},
+	{Code: "FJD", NumericCode: "242", Name: "Fiji Dollar"},
+	{Code: "FKP", NumericCode: "238", Name: "Falkland Islands Pound"},
+	{Code: "GBP", NumericCode: "826", Name: "Pound Sterling"},
+	{Code: "GEL", NumericCode: "981", Name: "Lari"},
+	{Code: "GHS", NumericCode: "936", Name: "Ghana Cedi"},
+	{Code: "GIP", NumericCode: "292", Name: "Gibraltar Pound"},
+	{Code: "GMD", NumericCode: "270", Name: "Dalasi"},
+	{Code: "GNF", NumericCode: "324", Name: "Guinean Franc"},
+	{Code: "GTQ", NumericCode: "320", Name: "Quetzal"},
+	{Code: "GYD", NumericCode: "328", Name: "Guyana Dollar"},
+	{Code: "HKD", NumericCode: "344", Name: "Hong Kong Dollar"},
+	{Code: "HNL", NumericCode: "340", Name: "Lempira"},
+	{Code: "HTG", NumericCode: "332", Name: "Gourde"},
+	{Code: "HUF", NumericCode: "348", Name: "Forint"},
+	{Code: "IDR", NumericCode: "360", Name: "Rupiah"},
+	{Code: "ILS", NumericCode: "376", Name: "New Israeli Sheqel"},
+	{Code: "INR", NumericCode: "356", Name: "Indian Rupee"},
+	{Code: "IQD", NumericCode: "368", Name: "Iraqi Dinar"},
+	{Code: "IRR", NumericCode: "364", Name: "Iranian Rial"},
+	{Code: "ISK", NumericCode: "352", Name: "Iceland Krona"},
+	{Code: "JMD", NumericCode: "388", Name: "Jamaican Dollar"},
+	{Code: "JOD", NumericCode: "400", Name: "Jordanian Dinar"},
+	{Code: "JPY", NumericCode: "392", Name: "Yen"},
+	{Code: "KES", NumericCode: "404", Name: "Kenyan Shilling"},
+	{Code: "KGS", NumericCode: "417", Name: "Som"},
+	{Code: "KHR", NumericCode: "116", Name: "Riel"},
+	{Code: "KMF", NumericCode: "174", Name: "Comorian Franc"},
+	{Code: "KPW", NumericCode: "408", Name: "North Korean Won"},
+	{Code: "KRW", NumericCode: "410", Name: "Won"},
+	{Code: "KWD", NumericCode: "414", Name: "Kuwaiti Dinar"},
+	{Code: "KYD", NumericCode: "136", Name: "Cayman Islands Dollar"},
+	{Code: "KZT", NumericCode: "398", Name: "Tenge"},
+	{Code: "LAK", NumericCode: "418", Name: "Lao Kip"},
+	{Code: "LBP", NumericCode: "422", Name: "Lebanese Pound"},
+	{Code: "LKR", NumericCode: "144", Name: "Sri Lanka Rupee"},
+	{Code: "LRD", NumericCode: "430", Name: "Liberian Dollar"},
+	{Code: "LSL", NumericCode: "426", Name: "Loti"},
+	{Code: "LYD", NumericCode: "434", Name: "Libyan Dinar"},
+	{Code: "MAD", NumericCode: "504", Name: "Moroccan Dirham"},
+	{Code: "MDL", NumericCode: "498", Name: "Moldovan Leu"},
+	{Code: "MGA", NumericCode: "969", Name: "Malagasy Ariary"},
+	{Code: "MKD", NumericCode: "807", Name: "Denar"},
+	{Code: "MMK", NumericCode: "104", Name: "Kyat"},
+	{Code: "MNT", NumericCode: "496", Name: "Tugrik"},
+	{Code: "MOP", NumericCode: "446", Name: "Pataca"},
+	{Code: "MRU", NumericCode: "929", Name: "Ouguiya"},
+	{Code: "MUR", NumericCode: "480", Name: "Mauritius Rupee"},
+	{Code: "MVR", NumericCode: "462", Name: "Rufiyaa"},
+	{Code: "MWK", NumericCode: "454", Name: "Malawi Kwacha"},
+	{Code: "MXN", NumericCode: "484", Name: "Mexican Peso"},
+	{Code: "MYR", NumericCode: "458", Name: "Malaysian Ringgit"},
+	{Code: "MZN", NumericCode: "943", Name: "Mozambique Metical"},
+	{Code: "NAD", NumericCode: "516", Name: "Namibia Dollar"},
+	{Code: "NGN", NumericCode: "566", Name: "Naira"},
+	{Code: "NIO", NumericCode: "558", Name: "Cordoba Oro"},
+	{Code: "NOK", NumericCode: "578", Name: "Norwegian Krone"},
+	{Code: "NPR", NumericCode: "524", Name: "Nepalese Rupee"},
+	{Code: "NZD", NumericCode: "554", Name: "New Zealand Dollar"},
+	{Code: "OMR", NumericCode: "512", Name: "Rial Omani"},
+	{Code: "PAB", NumericCode: "590", Name: "Balboa"},
+	{Code: "PEN", NumericCode: "604", Name: "Sol"},
+	{Code: "PGK", NumericCode: "598", Name: "Kina"},
+	{Code: "PHP", NumericCode: "608", Name: "Philippine Peso"},
+	{Code: "PKR", NumericCode: "586", Name: "Pakistan Rupee"},
+	{Code: "PLN", NumericCode: "985", Name: "Zloty"},
+	{Code: "PYG", NumericCode: "600", Name: "Guarani"},
+	{Code: "QAR", NumericCode: "634", Name: "Qatari Rial"},
+	{Code: "RON", NumericCode: "946", Name: "Romanian Leu"},
+	{Code: "RSD", NumericCode: "941", Name: "Serbian Dinar"},
+	{Code: "RUB", NumericCode: "643", Name: "Russian Ruble"},
+	{Code: "RWF", NumericCode: "646", Name: "Rwanda Franc"},
+	{Code: "SAR", NumericCode: "682", Name: "Saudi Riyal"},
+	{Code: "SBD", NumericCode: "090", Name: "Solomon Islands Dollar"},
+	{Code: "SCR", NumericCode: "690", Name: "Seychelles Rupee"},
+	{Code: "SDG", NumericCode: "938", Name: "Sudanese Pound"},
+	{Code: "SEK", NumericCode: "752", Name: "Swedish Krona"},
+	{Code: "SGD", NumericCode: "702", Name: "Singapore Dollar"},
+	{Code: "SHP", NumericCode: "654", Name: "Saint Helena Pound"},
+	{Code: "SLE", NumericCode: "925", Name: "Leone"},
+	{Code: "SOS", NumericCode: "706", Name: "Somali Shilling"},
+	{Code: "SRD", NumericCode: "968", Name: "Surinam Dollar"},
+	{Code: "SSP", NumericCode: "728", Name: "South Sudanese Pound"},
+	{Code: "STN", NumericCode: "930", Name: "Dobra"},
+	{Code: "SVC", NumericCode: "222", Name: "El Salvador Colon"},
+	{Code: "SYP", NumericCode: "760", Name: "Syrian Pound"},
+	{Code: "SZL", NumericCode: "748", Name: "Lilangeni"},
+	{Code: "THB", NumericCode: "764", Name: "Baht"},
+	{Code: "TJS", NumericCode: "972", Name: "Somoni"},
+	{Code: "TMT", NumericCode: "934", Name: "Turkmenistan New Manat"},
+	{Code: "TND", NumericCode: "788", Name: "Tunisian Dinar"},
+	{Code: "TOP", NumericCode: "776", Name: "Pa'anga"},
+	{Code: "TRY", NumericCode: "949", Name: "Turkish Lira"},
+	{Code: "TTD", NumericCode: "780", Name: "Trinidad and Tobago Dollar"},
+	{Code: "TWD", NumericCode: "901", Name: "New Taiwan Dollar"},
+	{Code: "TZS", NumericCode: "834", Name: "Tanzanian Shilling"},
+	{Code: "UAH", NumericCode: "980", Name: "Hryvnia"},
+	{Code: "UGX", NumericCode: "800", Name: "Uganda Shilling"},
+	{Code: "USD", NumericCode: "840", Name: "US Dollar"},
+	{Code: "UYU", NumericCode: "858", Name: "Peso Uruguayo"},
+	{Code: "UZS", NumericCode: "860", Name: "Uzbekistan Sum"},
+	{Code: "VES", NumericCode: "928", Name: "Bolivar Soberano"},
+	{Code: "VND", NumericCode: "704", Name: "Dong"},
+	{Code: "VUV", NumericCode: "548", Name: "Vatu"},
+	{Code: "WST", NumericCode: "882", Name: "Tala"},
+	{Code: "XAF", NumericCode: "950", Name: "CFA Franc BEAC"},
+	{Code: "XAG", NumericCode: "961", Name: "Silver"},
+	{Code: "XAU", NumericCode: "959", Name: "Gold"},
+	{Code: "XCD", NumericCode: "951", Name: "East Caribbean Dollar"},
+	{Code: "XDR", NumericCode: "960", Name: "SDR (Special Drawing Right)"},
+	{Code: "XOF", NumericCode: "952", Name: "CFA Franc BCEAO"},
+	{Code: "XPD", NumericCode: "964", Name: "Palladium"},
+	{Code: "XPF", NumericCode: "953", Name: "CFP Franc"},
+	{Code: "XPT", NumericCode: "962", Name: "Platinum"},
+	{Code: "YER", NumericCode: "886", Name: "Yemeni Rial"},
+	{Code: "ZAR", NumericCode: "710", Name: "Rand"},
+	{Code: "ZMW", NumericCode: "967", Name: "Zambian Kwacha"},
+	{Code: "ZWL", NumericCode: "932", Name: "Zimbabwe Dollar"},
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_go/entity"
+	"my_go/gateway"
+)
+
+// GetCBMetals loads precious metals prices for the central bank of the country provided in the request,
+// it fails if the central bank doesn't set the prices of precious metals.
+// The latest prices are cached the same way as the rates, prices for the provided date are loaded
+// from the central bank on every request.
+func (c *cbr) GetCBMetals(ctx context.Context, req *entity.GetCBMetalsRequest) (*entity.GetCBMetalsResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil GetCBMetalsRequest")
+	}
+	gw, ok := c.MetalsGateways[req.Country]
+	if !ok {
+		return nil, fmt.Errorf("metal prices are not supported for country %s", req.Country)
+	}
+	if req.Date != "" {
+		prices, err := gw.GetCBRMetalsForDate(ctx, req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s metal prices for %s: %s", req.Country, req.Date, err)
+		}
+		if prices == nil {
+			return nil, fmt.Errorf("nil metal prices returned from central bank %s with no error", req.Country)
+		}
+		return &entity.GetCBMetalsResponse{
+			Prices: prices,
+		}, nil
+	}
+
+	if cached, ok := c.cachedMetals(req.Country); ok {
+		return &entity.GetCBMetalsResponse{
+			Prices: cached,
+		}, nil
+	}
+	// metal prices are refreshed in a flight of their own, so they never wait for the rates of the same country
+	err := c.refreshes.Do(ctx, "metals/"+req.Country, func(ctx context.Context) error {
+		if _, ok := c.cachedMetals(req.Country); ok {
+			return nil
+		}
+		return c.loadMetals(ctx, req.Country, gw)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload metal prices: %s", err)
+	}
+	cached, ok := c.cachedMetals(req.Country)
+	if !ok {
+		// unreachable in tests because if prices were reloaded successfully they are actual
+		return nil, fmt.Errorf("failed to reload metal prices of country %s", req.Country)
+	}
+	return &entity.GetCBMetalsResponse{
+		Prices: cached,
+	}, nil
+}
+
+// cachedMetals returns the cached precious metals prices of the country if they are still actual
+func (c *cbr) cachedMetals(country string) (*entity.MetalPrices, bool) {
+	c.RLock()
+	cached, ok := c.MetalsCache[country]
+	c.RUnlock()
+	if !ok || c.outdated(cached.DateLoaded, cached.EffectiveDate, cached.TimeZone) {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// loadMetals loads the latest precious metals prices from the central bank gateway and caches them
+func (c *cbr) loadMetals(ctx context.Context, country string, gw gateway.MetalsCBGateway) error {
+	prices, err := gw.GetCBRMetals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load %s metal prices: %s", country, err)
+	}
+	if prices == nil {
+		return fmt.Errorf("nil metal prices returned from central bank %s with no error", country)
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.MetalsCache == nil {
+		c.MetalsCache = map[string]entity.MetalPrices{}
+	}
+	c.MetalsCache[country] = *prices
+	return nil
+}
@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"my_go/entity"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	"my_go/repository/storage"
+	"testing"
+	"time"
+)
+
+func Test_cbr_GetCBMetals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 12, 0, 0, 0, time.UTC)
+	}
+	pricesFor := func(date string) *entity.MetalPrices {
+		return &entity.MetalPrices{
+			Country:       "russia",
+			DateLoaded:    date,
+			EffectiveDate: date,
+			TimeZone:      ruTZ,
+			Prices: map[string]entity.MetalPrice{
+				"XAU": {
+					Metal:    "XAU",
+					Name:     "gold",
+					Currency: "RUB",
+					Buy:      decimal.RequireFromString("5061.8"),
+					Sell:     decimal.RequireFromString("5061.8"),
+				},
+			},
+		}
+	}
+	type mockMetalsGateway struct {
+		date string
+		res  *entity.MetalPrices
+		err  error
+	}
+	tests := []struct {
+		name              string
+		req               *entity.GetCBMetalsRequest
+		cache             map[string]entity.MetalPrices
+		mockMetalsGateway *mockMetalsGateway
+		want              *entity.GetCBMetalsResponse
+		wantCache         map[string]entity.MetalPrices
+		assertion         assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, prices in cache",
+			req:       &entity.GetCBMetalsRequest{Country: "russia"},
+			cache:     map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			want:      &entity.GetCBMetalsResponse{Prices: pricesFor("2023-04-19")},
+			wantCache: map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			assertion: assert.NoError,
+		},
+		{
+			name:              "Happy path, outdated prices are reloaded",
+			req:               &entity.GetCBMetalsRequest{Country: "russia"},
+			cache:             map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-18")},
+			mockMetalsGateway: &mockMetalsGateway{res: pricesFor("2023-04-19")},
+			want:              &entity.GetCBMetalsResponse{Prices: pricesFor("2023-04-19")},
+			wantCache:         map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "Happy path, no cache",
+			req:               &entity.GetCBMetalsRequest{Country: "russia"},
+			mockMetalsGateway: &mockMetalsGateway{res: pricesFor("2023-04-19")},
+			want:              &entity.GetCBMetalsResponse{Prices: pricesFor("2023-04-19")},
+			wantCache:         map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "Happy path, date provided, cache is not used",
+			req:               &entity.GetCBMetalsRequest{Country: "russia", Date: "2023-04-18"},
+			cache:             map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			mockMetalsGateway: &mockMetalsGateway{date: "2023-04-18", res: pricesFor("2023-04-18")},
+			want:              &entity.GetCBMetalsResponse{Prices: pricesFor("2023-04-18")},
+			wantCache:         map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-19")},
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "gateway fails",
+			req:               &entity.GetCBMetalsRequest{Country: "russia"},
+			cache:             map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-18")},
+			mockMetalsGateway: &mockMetalsGateway{err: errors.New("some error")},
+			want:              nil,
+			wantCache:         map[string]entity.MetalPrices{"russia": *pricesFor("2023-04-18")},
+			assertion:         assert.Error,
+		},
+		{
+			name:              "gateway returns nil and no error",
+			req:               &entity.GetCBMetalsRequest{Country: "russia"},
+			mockMetalsGateway: &mockMetalsGateway{},
+			want:              nil,
+			assertion:         assert.Error,
+		},
+		{
+			name:              "date provided, gateway fails",
+			req:               &entity.GetCBMetalsRequest{Country: "russia", Date: "2023-04-18"},
+			mockMetalsGateway: &mockMetalsGateway{date: "2023-04-18", err: errors.New("some error")},
+			want:              nil,
+			assertion:         assert.Error,
+		},
+		{
+			name:              "date provided, gateway returns nil and no error",
+			req:               &entity.GetCBMetalsRequest{Country: "russia", Date: "2023-04-18"},
+			mockMetalsGateway: &mockMetalsGateway{date: "2023-04-18"},
+			want:              nil,
+			assertion:         assert.Error,
+		},
+		{
+			name:      "metal prices not supported for the country",
+			req:       &entity.GetCBMetalsRequest{Country: "thailand"},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "nil request",
+			req:       nil,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			if m := tt.mockMetalsGateway; m != nil && m.date == "" {
+				mockRussiaCB.EXPECT().GetCBRMetals(gomock.Any()).Return(m.res, m.err)
+			}
+			if m := tt.mockMetalsGateway; m != nil && m.date != "" {
+				mockRussiaCB.EXPECT().GetCBRMetalsForDate(ctx, m.date).Return(m.res, m.err)
+			}
+			c := &cbr{
+				TimeNow:        timeNow,
+				Logger:         zap.NewNop(),
+				MetalsGateways: map[string]gateway.MetalsCBGateway{entity.Russia: mockRussiaCB},
+				MetalsCache:    tt.cache,
+			}
+			got, err := c.GetCBMetals(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCache, c.MetalsCache)
+		})
+	}
+}
+
+func Test_cbr_GetExchangeRate_metals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	thTZ, _ := time.LoadLocation("Asia/Bangkok")
+	timeNow := func() time.Time {
+		return time.Date(2023, 4, 19, 12, 0, 0, 0, time.UTC)
+	}
+	ratesCache := map[string]entity.ExchangeRates{
+		"russia": {
+			Country:    "russia",
+			DateLoaded: "2023-04-19",
+			TimeZone:   ruTZ,
+			Rates: map[string]entity.Rate{
+				"RUB": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "RUB",
+					RateTargetToBase: decimal.RequireFromString("1"),
+				},
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("81.5"),
+				},
+			},
+		},
+		"thailand": {
+			Country:    "thailand",
+			DateLoaded: "2023-04-19",
+			TimeZone:   thTZ,
+			Rates: map[string]entity.Rate{
+				"THB": {
+					Nominal:          1,
+					BaseCurrency:     "THB",
+					TargetCurrency:   "THB",
+					RateTargetToBase: decimal.RequireFromString("1"),
+				},
+			},
+		},
+	}
+	metalsCache := map[string]entity.MetalPrices{
+		"russia": {
+			Country:    "russia",
+			DateLoaded: "2023-04-19",
+			TimeZone:   ruTZ,
+			Prices: map[string]entity.MetalPrice{
+				"XAU": {
+					Metal:    "XAU",
+					Name:     "gold",
+					Currency: "RUB",
+					Buy:      decimal.RequireFromString("5000"),
+					Sell:     decimal.RequireFromString("5010"),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		req       *entity.GetExchangeRateRequest
+		want      *entity.GetExchangeRateResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, metal to currency via RUB",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "XAU",
+				TargetCurrencyID: "USD",
+				Amount:           decimal.RequireFromString("1"),
+				Places:           2,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "XAU",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("1910.0969"),
+				},
+				Amount: decimal.RequireFromString("1910.1"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, metal to base currency with buy rate type",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "XAU",
+				TargetCurrencyID: "RUB",
+				Amount:           decimal.RequireFromString("1"),
+				Places:           2,
+				RateType:         entity.RateTypeBuy,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "XAU",
+					TargetCurrency:   "RUB",
+					RateTargetToBase: decimal.RequireFromString("155517.384"),
+				},
+				Amount: decimal.RequireFromString("155517.38"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, currency to metal",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "USD",
+				TargetCurrencyID: "XAU",
+				Amount:           decimal.RequireFromString("1000"),
+				Places:           4,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate: entity.Rate{
+					Nominal:          1,
+					BaseCurrency:     "USD",
+					TargetCurrency:   "XAU",
+					RateTargetToBase: decimal.RequireFromString("0.000523534"),
+				},
+				Amount: decimal.RequireFromString("0.5235"),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "metal prices not set by the bank",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "thailand",
+				BaseCurrencyID:   "XAU",
+				TargetCurrencyID: "THB",
+				Amount:           decimal.RequireFromString("1"),
+				Places:           2,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "metal not priced by the bank",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "XPD",
+				TargetCurrencyID: "USD",
+				Amount:           decimal.RequireFromString("1"),
+				Places:           2,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := &cbr{
+				TimeNow:    timeNow,
+				Logger:     zap.NewNop(),
+				Storage:    storage.NewMemory(),
+				RatesCache: ratesCache,
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia:   russiagatewaymock.NewMockGateway(ctrl),
+					entity.Thailand: russiagatewaymock.NewMockGateway(ctrl),
+				},
+				MetalsGateways: map[string]gateway.MetalsCBGateway{
+					entity.Russia: russiagatewaymock.NewMockGateway(ctrl),
+				},
+				MetalsCache: metalsCache,
+			}
+			got, err := c.GetExchangeRate(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
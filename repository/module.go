@@ -1,7 +1,13 @@
 package repository
 
-import "go.uber.org/fx"
+import (
+	"go.uber.org/fx"
+	"my_go/repository/directory"
+	"my_go/repository/storage"
+)
 
 var Module = fx.Options(
+	fx.Provide(storage.New),
 	fx.Provide(New),
+	fx.Provide(directory.New),
 )
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightTimeout limits the execution of the flight, as it is detached from the contexts of the callers
+const flightTimeout = 30 * time.Second
+
+// flightGroup coalesces concurrent calls with the same key into a single execution.
+// The execution is started by the first caller and the callers including the first one wait for its result.
+// Zero value is ready to use.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+// Do executes fn unless the execution for the key is already in progress, in which case
+// it waits for the result of the running execution. Waiting is interrupted if ctx is done,
+// the running execution is not affected by that: fn is executed with a context detached from
+// the contexts of the callers (values of the first caller context are kept) limited by flightTimeout,
+// so the caller that gives up never fails the other callers waiting for the same execution.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, ok := g.flights[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		go g.run(ctx, key, f, fn)
+	}
+	g.mu.Unlock()
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(detachedContext{parent: ctx}, flightTimeout)
+	defer cancel()
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.err = fn(ctx)
+}
+
+// detachedContext keeps the values of the parent context but is never cancelled along with it
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key any) any {
+	return d.parent.Value(key)
+}
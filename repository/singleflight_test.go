@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_flightGroup_Do(t *testing.T) {
+	t.Run("concurrent calls with the same key are executed once", func(t *testing.T) {
+		g := &flightGroup{}
+		var calls int32
+		release := make(chan struct{})
+		started := make(chan struct{})
+		fn := func(context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+			}
+			<-release
+			return errors.New("failed")
+		}
+
+		wg := &sync.WaitGroup{}
+		errs := make(chan error, 10)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- g.Do(context.Background(), "russia", fn)
+		}()
+		<-started
+		for i := 0; i < 9; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- g.Do(context.Background(), "russia", fn)
+			}()
+		}
+		// there is no way to observe the waiting goroutines, give the followers time to join the running flight
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for err := range errs {
+			assert.EqualError(t, err, "failed")
+		}
+		assert.Empty(t, g.flights)
+	})
+	t.Run("calls with different keys don't wait for each other", func(t *testing.T) {
+		g := &flightGroup{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go g.Do(context.Background(), "thailand", func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		<-started
+		assert.NoError(t, g.Do(context.Background(), "russia", func(context.Context) error {
+			return nil
+		}))
+		close(release)
+	})
+	t.Run("waiting is interrupted by context", func(t *testing.T) {
+		g := &flightGroup{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go g.Do(context.Background(), "thailand", func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		<-started
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, g.Do(ctx, "thailand", func(context.Context) error {
+			return nil
+		}), context.Canceled)
+		close(release)
+	})
+	t.Run("execution is not cancelled along with the first caller", func(t *testing.T) {
+		g := &flightGroup{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		var calls int32
+		fn := func(ctx context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+			}
+			<-release
+			return ctx.Err()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error, 1)
+		go func() {
+			first <- g.Do(ctx, "russia", fn)
+		}()
+		<-started
+		waiter := make(chan error, 1)
+		go func() {
+			waiter <- g.Do(context.Background(), "russia", fn)
+		}()
+		// give the waiter time to join the running flight
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+		close(release)
+		assert.NoError(t, <-waiter)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+	t.Run("sequential calls are executed each time", func(t *testing.T) {
+		g := &flightGroup{}
+		calls := 0
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, g.Do(context.Background(), "russia", func(context.Context) error {
+				calls++
+				return nil
+			}))
+		}
+		assert.Equal(t, 3, calls)
+	})
+}
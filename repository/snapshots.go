@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_go/entity"
+	"my_go/mapper"
+	"strings"
+)
+
+// snapshotKey identifies the rates of the central bank for the date, empty date means the current rates
+type snapshotKey struct {
+	country string
+	date    string
+}
+
+type ratesResult struct {
+	resp *entity.GetCBRatesResponse
+	err  error
+}
+
+type metalsResult struct {
+	resp *entity.GetCBMetalsResponse
+	err  error
+}
+
+// snapshots memoizes the rates and metal prices loaded from the repository, so the exchange rates calculated
+// with the same snapshots share the rates of every central bank and date. Not safe for concurrent use.
+type snapshots struct {
+	repository *cbr
+	rates      map[snapshotKey]ratesResult
+	metals     map[snapshotKey]metalsResult
+}
+
+func newSnapshots(c *cbr) *snapshots {
+	return &snapshots{
+		repository: c,
+		rates:      make(map[snapshotKey]ratesResult),
+		metals:     make(map[snapshotKey]metalsResult),
+	}
+}
+
+// exchangeRate calculates the exchange rate of the currency pair with the rates of the central bank.
+// Precious metals (ISO 4217 codes e.g. XAU) are priced with the metal prices of the same central bank.
+// If triangulation is requested the pair missing from the rates of the central bank is calculated through
+// one of entity.TriangulationCurrencies with the rates of other central banks.
+func (s *snapshots) exchangeRate(
+	ctx context.Context,
+	req *entity.GetExchangeRateRequest,
+) (*entity.GetExchangeRateResponse, error) {
+	if req == nil {
+		return nil, errors.New("nil GetExchangeRateRequest")
+	}
+	key := snapshotKey{country: req.Country, date: req.Date}
+	rates, err := s.snapshot(ctx, key, req.BaseCurrencyID, req.TargetCurrencyID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Triangulate {
+		return s.triangulate(ctx, req, rates)
+	}
+	resp, err := mapper.CBRRatesAndGetExchangeRateRequestToGetExchangeRateResponse(rates.Rates, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert rates and request to response: %s", err)
+	}
+	resp.Stale = rates.Stale
+	resp.EffectiveDate = rates.Rates.EffectiveDate
+	return resp, nil
+}
+
+// triangulate calculates the exchange rate of the currency pair through the first of entity.TriangulationCurrencies
+// both currencies can be converted to. Every leg of the path is calculated with the rates of the requested
+// central bank if it quotes both currencies of the leg, otherwise with the rates of the first other central bank
+// that quotes them. Other central banks are used with their reference rates. The pair quoted by the requested
+// central bank is calculated directly with the path of a single leg.
+func (s *snapshots) triangulate(
+	ctx context.Context,
+	req *entity.GetExchangeRateRequest,
+	rates *entity.GetCBRatesResponse,
+) (*entity.GetExchangeRateResponse, error) {
+	path := []*entity.GetExchangeRateRequest{req}
+	pathRates := []*entity.GetCBRatesResponse{rates}
+	if !quotes(rates, req.BaseCurrencyID, req.TargetCurrencyID) {
+		path, pathRates = s.path(ctx, req, rates)
+		if len(path) == 0 {
+			return nil, fmt.Errorf(
+				"currency pair %s/%s not supported by CB %s and can't be triangulated through %s",
+				req.BaseCurrencyID, req.TargetCurrencyID, req.Country, strings.Join(entity.TriangulationCurrencies, ", "),
+			)
+		}
+	}
+	resp, err := mapper.CBRRatesAndPathToGetExchangeRateResponse(pathRates, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert rates and path to response: %s", err)
+	}
+	return resp, nil
+}
+
+// path returns the legs of the path of the currency pair through a common currency and the rates they are
+// calculated with, nil if there is no such path
+func (s *snapshots) path(
+	ctx context.Context,
+	req *entity.GetExchangeRateRequest,
+	rates *entity.GetCBRatesResponse,
+) ([]*entity.GetExchangeRateRequest, []*entity.GetCBRatesResponse) {
+	for _, common := range entity.TriangulationCurrencies {
+		var (
+			path      []*entity.GetExchangeRateRequest
+			pathRates []*entity.GetCBRatesResponse
+		)
+		for _, pair := range [][2]string{{req.BaseCurrencyID, common}, {common, req.TargetCurrencyID}} {
+			if pair[0] == pair[1] {
+				continue
+			}
+			leg, legRates := s.leg(ctx, req, rates, pair[0], pair[1])
+			if leg == nil {
+				path = nil
+				break
+			}
+			path = append(path, leg)
+			pathRates = append(pathRates, legRates)
+		}
+		if len(path) > 0 {
+			return path, pathRates
+		}
+	}
+	return nil, nil
+}
+
+// leg returns the request of the conversion of base currency to target currency and the rates of the central bank
+// it's calculated with, the requested central bank is preferred. Nil if no central bank quotes both currencies.
+func (s *snapshots) leg(
+	ctx context.Context,
+	req *entity.GetExchangeRateRequest,
+	rates *entity.GetCBRatesResponse,
+	base string,
+	target string,
+) (*entity.GetExchangeRateRequest, *entity.GetCBRatesResponse) {
+	if quotes(rates, base, target) {
+		return &entity.GetExchangeRateRequest{
+			Country:          req.Country,
+			Date:             req.Date,
+			BaseCurrencyID:   base,
+			TargetCurrencyID: target,
+			RateType:         req.RateType,
+		}, rates
+	}
+	for _, country := range s.repository.Countries {
+		if country == req.Country {
+			continue
+		}
+		other, err := s.snapshot(ctx, snapshotKey{country: country, date: req.Date}, base, target)
+		if err != nil || !quotes(other, base, target) {
+			continue
+		}
+		return &entity.GetExchangeRateRequest{
+			Country:          country,
+			Date:             req.Date,
+			BaseCurrencyID:   base,
+			TargetCurrencyID: target,
+		}, other
+	}
+	return nil, nil
+}
+
+// snapshot returns the rates of the central bank, if any of the currencies is a precious metal
+// the metal prices of the central bank are added to the rates
+func (s *snapshots) snapshot(
+	ctx context.Context,
+	key snapshotKey,
+	currencies ...string,
+) (*entity.GetCBRatesResponse, error) {
+	rates, err := s.getRates(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange rates for CB %s, err: %s", key.country, err)
+	}
+	for _, currency := range currencies {
+		if _, ok := entity.Metals[currency]; !ok {
+			continue
+		}
+		metals, err := s.getMetals(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metal prices for CB %s, err: %s", key.country, err)
+		}
+		snapshot, err := mapper.ExchangeRatesWithMetalPrices(rates.Rates, metals.Prices)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.GetCBRatesResponse{Rates: snapshot, Stale: rates.Stale}, nil
+	}
+	return rates, nil
+}
+
+// quotes reports whether the rates contain all the currencies
+func quotes(rates *entity.GetCBRatesResponse, currencies ...string) bool {
+	for _, currency := range currencies {
+		if _, ok := rates.Rates.Rates[currency]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *snapshots) getRates(ctx context.Context, key snapshotKey) (*entity.GetCBRatesResponse, error) {
+	if r, ok := s.rates[key]; ok {
+		return r.resp, r.err
+	}
+	resp, err := s.repository.GetCBRates(ctx, &entity.GetCBRatesRequest{
+		Country: key.country,
+		Date:    key.date,
+	})
+	s.rates[key] = ratesResult{resp: resp, err: err}
+	return resp, err
+}
+
+func (s *snapshots) getMetals(ctx context.Context, key snapshotKey) (*entity.GetCBMetalsResponse, error) {
+	if r, ok := s.metals[key]; ok {
+		return r.resp, r.err
+	}
+	resp, err := s.repository.GetCBMetals(ctx, &entity.GetCBMetalsRequest{
+		Country: key.country,
+		Date:    key.date,
+	})
+	s.metals[key] = metalsResult{resp: resp, err: err}
+	return resp, err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"my_go/entity"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"my_go/repository/storage"
+	"testing"
+	"time"
+)
+
+func Test_cbr_GetExchangeRate_triangulation(t *testing.T) {
+	ctx := context.Background()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	thTZ, _ := time.LoadLocation("Asia/Bangkok")
+	russiaRates := &entity.ExchangeRates{
+		Country:       "russia",
+		DateLoaded:    "1970-01-01",
+		EffectiveDate: "1970-01-01",
+		TimeZone:      ruTZ,
+		Rates: map[string]entity.Rate{
+			"RUB": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "RUB", RateTargetToBase: decimal.RequireFromString("1")},
+			"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("80")},
+			"EUR": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("88")},
+		},
+	}
+	thailandRates := &entity.ExchangeRates{
+		Country:       "thailand",
+		DateLoaded:    "1970-01-01",
+		EffectiveDate: "1970-01-02",
+		TimeZone:      thTZ,
+		Rates: map[string]entity.Rate{
+			"THB": {Nominal: 1, BaseCurrency: "THB", TargetCurrency: "THB", RateTargetToBase: decimal.RequireFromString("1")},
+			"EUR": {Nominal: 1, BaseCurrency: "THB", TargetCurrency: "EUR", RateTargetToBase: decimal.RequireFromString("37")},
+			"HUF": {Nominal: 100, BaseCurrency: "THB", TargetCurrency: "HUF", RateTargetToBase: decimal.RequireFromString("10")},
+		},
+	}
+	rate := func(base string, target string, value string) entity.Rate {
+		return entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     base,
+			TargetCurrency:   target,
+			RateTargetToBase: decimal.RequireFromString(value),
+		}
+	}
+	tests := []struct {
+		name      string
+		req       *entity.GetExchangeRateRequest
+		want      *entity.GetExchangeRateResponse
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path, triangulated through EUR with the rates of both banks",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "RUB",
+				TargetCurrencyID: "HUF",
+				Amount:           decimal.RequireFromString("1000"),
+				Places:           2,
+				Triangulate:      true,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate:   rate("RUB", "HUF", "4.20455"),
+				Amount: decimal.RequireFromString("4204.55"),
+				Path: []entity.ExchangeRateLeg{
+					{Country: "russia", EffectiveDate: "1970-01-01", Rate: rate("RUB", "EUR", "0.0113636")},
+					{Country: "thailand", EffectiveDate: "1970-01-02", Rate: rate("EUR", "HUF", "370")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, common currency converted with the rates of another bank",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "EUR",
+				TargetCurrencyID: "HUF",
+				Amount:           decimal.RequireFromString("10"),
+				Places:           2,
+				Triangulate:      true,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate:          rate("EUR", "HUF", "370"),
+				Amount:        decimal.RequireFromString("3700"),
+				EffectiveDate: "1970-01-02",
+				Path: []entity.ExchangeRateLeg{
+					{Country: "thailand", EffectiveDate: "1970-01-02", Rate: rate("EUR", "HUF", "370")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, pair quoted by the requested bank",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "USD",
+				TargetCurrencyID: "RUB",
+				Amount:           decimal.RequireFromString("10"),
+				Places:           2,
+				Triangulate:      true,
+			},
+			want: &entity.GetExchangeRateResponse{
+				Rate:          rate("USD", "RUB", "80"),
+				Amount:        decimal.RequireFromString("800"),
+				EffectiveDate: "1970-01-01",
+				Path: []entity.ExchangeRateLeg{
+					{Country: "russia", EffectiveDate: "1970-01-01", Rate: rate("USD", "RUB", "80")},
+				},
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "triangulation not requested",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "RUB",
+				TargetCurrencyID: "HUF",
+				Amount:           decimal.RequireFromString("1000"),
+				Places:           2,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name: "currency not supported by any bank",
+			req: &entity.GetExchangeRateRequest{
+				Country:          "russia",
+				BaseCurrencyID:   "RUB",
+				TargetCurrencyID: "XYZ",
+				Amount:           decimal.RequireFromString("1000"),
+				Places:           2,
+				Triangulate:      true,
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).Return(russiaRates, nil).MaxTimes(1)
+			mockThailandCB := thailandgatewaymock.NewMockGateway(ctrl)
+			mockThailandCB.EXPECT().GetCBRRates(gomock.Any()).Return(thailandRates, nil).MaxTimes(1)
+			c := &cbr{
+				TimeNow: func() time.Time {
+					return time.Unix(100, 0)
+				},
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia:   mockRussiaCB,
+					entity.Thailand: mockThailandCB,
+				},
+				Countries:  []string{entity.Russia, entity.Thailand},
+				RatesCache: map[string]entity.ExchangeRates{},
+				Storage:    storage.NewMemory(),
+				Logger:     zap.NewNop(),
+			}
+			got, err := c.GetExchangeRate(ctx, tt.req)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"my_go/entity"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 30 * time.Second
+	defaultRetryMaxBackoff     = 30 * time.Minute
+)
+
+// staleRates returns the last known rates of the country if serving them is allowed
+// by the configured max staleness for the country. Cached rates are preferred,
+// after restart the latest rates from the storage are used.
+func (c *cbr) staleRates(ctx context.Context, country string) (*entity.ExchangeRates, bool) {
+	maxStaleness := c.StaleRates.MaxStaleness[country]
+	if maxStaleness <= 0 {
+		return nil, false
+	}
+	c.RLock()
+	cachedRates, ok := c.RatesCache[country]
+	c.RUnlock()
+	rates := &cachedRates
+	if !ok || cachedRates.DateLoaded == "" {
+		stored, err := c.Storage.Latest(ctx, country)
+		if err != nil {
+			return nil, false
+		}
+		rates = stored
+	}
+	staleness, err := c.staleness(rates)
+	if err != nil || staleness > maxStaleness {
+		return nil, false
+	}
+	return rates, true
+}
+
+// staleness returns the time passed since the rates stopped being actual,
+// that is the midnight following the last date the rates are valid through in the central bank timezone.
+func (c *cbr) staleness(r *entity.ExchangeRates) (time.Duration, error) {
+	if r.TimeZone == nil {
+		return 0, errors.New("rates without timezone")
+	}
+	loaded, err := time.ParseInLocation(entity.DateLayout, validThrough(r.DateLoaded, r.EffectiveDate), r.TimeZone)
+	if err != nil {
+		return 0, err
+	}
+	return c.TimeNow().Sub(loaded.AddDate(0, 0, 1)), nil
+}
+
+// retryInBackground starts reloading the rates of the country in background until actual rates
+// are loaded. Only one background retry per country is running at a time.
+func (c *cbr) retryInBackground(country string) {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+	if c.retrying == nil {
+		c.retrying = map[string]bool{}
+	}
+	if c.retrying[country] {
+		return
+	}
+	c.retrying[country] = true
+	go c.retry(country)
+}
+
+func (c *cbr) isRetrying(country string) bool {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+	return c.retrying[country]
+}
+
+// retry reloads the rates of the country with exponential backoff until it succeeds
+// or the repository is stopped.
+func (c *cbr) retry(country string) {
+	defer func() {
+		c.retryMu.Lock()
+		delete(c.retrying, country)
+		c.retryMu.Unlock()
+	}()
+	log := c.logger("retry")
+	backoff := c.StaleRates.RetryInitialBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryInitialBackoff
+	}
+	maxBackoff := c.StaleRates.RetryMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-c.background.Done():
+			return
+		case <-c.After(backoff):
+		}
+		err := c.reloadCache(c.background, country)
+		if err == nil {
+			log.Infof("%s rates reloaded after %d attempt(s)", country, attempt)
+			return
+		}
+		log.Warnf("attempt %d to reload %s rates failed: %s", attempt, country, err)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	"my_go/repository/storage"
+	"testing"
+	"time"
+)
+
+func Test_cbr_GetCBRates_stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	timeNow := func() time.Time {
+		return time.Date(2023, 1, 3, 10, 0, 0, 0, ruTZ)
+	}
+	ratesForDate := func(date string) entity.ExchangeRates {
+		return entity.ExchangeRates{
+			Country:    "russia",
+			DateLoaded: date,
+			TimeZone:   ruTZ,
+			Rates: map[string]entity.Rate{
+				"USD": {
+					Nominal:          1,
+					BaseCurrency:     "RUB",
+					TargetCurrency:   "USD",
+					RateTargetToBase: decimal.RequireFromString("70.3375"),
+				},
+			},
+		}
+	}
+	yesterday := ratesForDate("2023-01-02")
+	weekAgo := ratesForDate("2022-12-27")
+	today := ratesForDate("2023-01-03")
+	policy := internalconfig.StaleRatesConfig{
+		MaxStaleness: map[string]time.Duration{
+			"russia": 24 * time.Hour,
+		},
+	}
+	type mockCBGateway struct {
+		res *entity.ExchangeRates
+		err error
+	}
+	tests := []struct {
+		name              string
+		staleRates        internalconfig.StaleRatesConfig
+		cache             map[string]entity.ExchangeRates
+		stored            *entity.ExchangeRates
+		retrying          bool
+		mockRussiaGateway *mockCBGateway
+		want              *entity.GetCBRatesResponse
+		expectedRetrying  bool
+		assertion         assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "central bank unreachable, cached rates are served as stale and retry is started",
+			staleRates: policy,
+			cache: map[string]entity.ExchangeRates{
+				"russia": yesterday,
+			},
+			mockRussiaGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want: &entity.GetCBRatesResponse{
+				Rates: &yesterday,
+				Stale: true,
+			},
+			expectedRetrying: true,
+			assertion:        assert.NoError,
+		},
+		{
+			name:       "central bank unreachable, empty cache, stored rates are served as stale",
+			staleRates: policy,
+			cache:      map[string]entity.ExchangeRates{},
+			stored:     &yesterday,
+			mockRussiaGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want: &entity.GetCBRatesResponse{
+				Rates: &yesterday,
+				Stale: true,
+			},
+			expectedRetrying: true,
+			assertion:        assert.NoError,
+		},
+		{
+			name:       "retry in progress, stale rates are served without calling central bank",
+			staleRates: policy,
+			cache: map[string]entity.ExchangeRates{
+				"russia": yesterday,
+			},
+			retrying: true,
+			want: &entity.GetCBRatesResponse{
+				Rates: &yesterday,
+				Stale: true,
+			},
+			expectedRetrying: true,
+			assertion:        assert.NoError,
+		},
+		{
+			name:       "central bank is back, actual rates are served",
+			staleRates: policy,
+			cache: map[string]entity.ExchangeRates{
+				"russia": yesterday,
+			},
+			mockRussiaGateway: &mockCBGateway{
+				res: &today,
+			},
+			want: &entity.GetCBRatesResponse{
+				Rates: &today,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "rates are too stale",
+			staleRates: policy,
+			cache: map[string]entity.ExchangeRates{
+				"russia": weekAgo,
+			},
+			mockRussiaGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:       "stale rates are not allowed for the country",
+			staleRates: internalconfig.StaleRatesConfig{},
+			cache: map[string]entity.ExchangeRates{
+				"russia": yesterday,
+			},
+			mockRussiaGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:       "no rates known",
+			staleRates: policy,
+			cache:      map[string]entity.ExchangeRates{},
+			mockRussiaGateway: &mockCBGateway{
+				err: errors.New("some error"),
+			},
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+			if tt.mockRussiaGateway != nil {
+				mockRussiaCB.
+					EXPECT().
+					GetCBRRates(gomock.Any()).
+					Return(tt.mockRussiaGateway.res, tt.mockRussiaGateway.err)
+			}
+			st := storage.NewMemory()
+			if tt.stored != nil {
+				assert.NoError(t, st.Save(ctx, tt.stored))
+			}
+			background, cancel := context.WithCancel(ctx)
+			defer cancel()
+			c := &cbr{
+				TimeNow: timeNow,
+				After: func(d time.Duration) <-chan time.Time {
+					return nil // background retry never fires
+				},
+				Gateways: map[string]gateway.CBGateway{
+					entity.Russia:   mockRussiaCB,
+					entity.Thailand: thailandgatewaymock.NewMockGateway(ctrl),
+				},
+				RatesCache: tt.cache,
+				Storage:    st,
+				StaleRates: tt.staleRates,
+				Logger:     zap.NewNop(),
+				background: background,
+				retrying: map[string]bool{
+					"russia": tt.retrying,
+				},
+			}
+			got, err := c.GetCBRates(ctx, &entity.GetCBRatesRequest{
+				Country: "russia",
+			})
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.expectedRetrying, c.isRetrying("russia"))
+		})
+	}
+}
+
+func Test_cbr_GetExchangeRate_stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	ctx := context.Background()
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockRussiaCB.
+		EXPECT().
+		GetCBRRates(gomock.Any()).
+		Return(nil, errors.New("some error"))
+	background, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return time.Date(2023, 1, 3, 10, 0, 0, 0, ruTZ)
+		},
+		After: func(d time.Duration) <-chan time.Time {
+			return nil
+		},
+		Gateways: map[string]gateway.CBGateway{
+			entity.Russia: mockRussiaCB,
+		},
+		RatesCache: map[string]entity.ExchangeRates{
+			"russia": {
+				Country:    "russia",
+				DateLoaded: "2023-01-02",
+				TimeZone:   ruTZ,
+				Rates: map[string]entity.Rate{
+					"RUB": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "RUB", RateTargetToBase: decimal.RequireFromString("1")},
+					"USD": {Nominal: 1, BaseCurrency: "RUB", TargetCurrency: "USD", RateTargetToBase: decimal.RequireFromString("70")},
+				},
+			},
+		},
+		Storage: storage.NewMemory(),
+		StaleRates: internalconfig.StaleRatesConfig{
+			MaxStaleness: map[string]time.Duration{
+				"russia": 24 * time.Hour,
+			},
+		},
+		Logger:     zap.NewNop(),
+		background: background,
+	}
+	got, err := c.GetExchangeRate(ctx, &entity.GetExchangeRateRequest{
+		Country:          "russia",
+		BaseCurrencyID:   "USD",
+		TargetCurrencyID: "RUB",
+		Amount:           decimal.RequireFromString("2"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.GetExchangeRateResponse{
+		Rate: entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     "USD",
+			TargetCurrency:   "RUB",
+			RateTargetToBase: decimal.RequireFromString("70"),
+		},
+		Amount: decimal.RequireFromString("140"),
+		Stale:  true,
+	}, got)
+}
+
+func Test_cbr_GetCBRates_callerCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+	mockRussiaCB.
+		EXPECT().
+		GetCBRRates(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) (*entity.ExchangeRates, error) {
+			defer close(finished)
+			close(started)
+			<-release
+			return nil, errors.New("some error")
+		})
+	background, cancelBackground := context.WithCancel(context.Background())
+	defer cancelBackground()
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return time.Date(2023, 1, 3, 10, 0, 0, 0, ruTZ)
+		},
+		After: func(d time.Duration) <-chan time.Time {
+			return nil
+		},
+		Gateways: map[string]gateway.CBGateway{
+			entity.Russia: mockRussiaCB,
+		},
+		RatesCache: map[string]entity.ExchangeRates{
+			"russia": {
+				Country:    "russia",
+				DateLoaded: "2023-01-02",
+				TimeZone:   ruTZ,
+			},
+		},
+		Storage: storage.NewMemory(),
+		StaleRates: internalconfig.StaleRatesConfig{
+			MaxStaleness: map[string]time.Duration{
+				"russia": 24 * time.Hour,
+			},
+		},
+		Logger:     zap.NewNop(),
+		background: background,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	got, err := c.GetCBRates(ctx, &entity.GetCBRatesRequest{
+		Country: "russia",
+	})
+	// the caller's own cancellation says nothing about the central bank, so neither stale rates
+	// are served nor the background retry is started
+	assert.ErrorContains(t, err, context.Canceled.Error())
+	assert.Nil(t, got)
+	assert.False(t, c.isRetrying("russia"))
+	close(release)
+	<-finished
+}
+
+func Test_cbr_retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	today := &entity.ExchangeRates{
+		Country:    "russia",
+		DateLoaded: "2023-01-03",
+		TimeZone:   ruTZ,
+		Rates:      map[string]entity.Rate{},
+	}
+	t.Run("retries with backoff until rates are loaded", func(t *testing.T) {
+		mockRussiaCB := russiagatewaymock.NewMockGateway(ctrl)
+		gomock.InOrder(
+			mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).Times(3).Return(nil, errors.New("some error")),
+			mockRussiaCB.EXPECT().GetCBRRates(gomock.Any()).Return(today, nil),
+		)
+		var waits []time.Duration
+		c := &cbr{
+			TimeNow: func() time.Time {
+				return time.Date(2023, 1, 3, 10, 0, 0, 0, ruTZ)
+			},
+			After: func(d time.Duration) <-chan time.Time {
+				waits = append(waits, d)
+				ch := make(chan time.Time, 1)
+				ch <- time.Time{}
+				return ch
+			},
+			Gateways: map[string]gateway.CBGateway{
+				entity.Russia: mockRussiaCB,
+			},
+			RatesCache: map[string]entity.ExchangeRates{},
+			Storage:    storage.NewMemory(),
+			StaleRates: internalconfig.StaleRatesConfig{
+				RetryInitialBackoff: time.Second,
+				RetryMaxBackoff:     3 * time.Second,
+			},
+			Logger:     zap.NewNop(),
+			background: context.Background(),
+			retrying: map[string]bool{
+				"russia": true,
+			},
+		}
+		c.retry("russia")
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, waits)
+		assert.Equal(t, *today, c.RatesCache["russia"])
+		assert.False(t, c.isRetrying("russia"))
+	})
+	t.Run("stops when repository is stopped", func(t *testing.T) {
+		background, cancel := context.WithCancel(context.Background())
+		cancel()
+		c := &cbr{
+			After: func(d time.Duration) <-chan time.Time {
+				return nil
+			},
+			Gateways: map[string]gateway.CBGateway{
+				entity.Russia: russiagatewaymock.NewMockGateway(ctrl),
+			},
+			Logger:     zap.NewNop(),
+			background: background,
+		}
+		c.retryInBackground("russia")
+		assert.Eventually(t, func() bool {
+			return !c.isRetrying("russia")
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func Test_cbr_staleness(t *testing.T) {
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	c := &cbr{
+		TimeNow: func() time.Time {
+			return time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC)
+		},
+	}
+	tests := []struct {
+		name      string
+		rates     *entity.ExchangeRates
+		want      time.Duration
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			rates: &entity.ExchangeRates{
+				DateLoaded: "2023-01-02",
+				TimeZone:   ruTZ,
+			},
+			want:      13 * time.Hour,
+			assertion: assert.NoError,
+		},
+		{
+			name: "Happy path, next day rates",
+			rates: &entity.ExchangeRates{
+				DateLoaded:    "2023-01-01",
+				EffectiveDate: "2023-01-02",
+				TimeZone:      ruTZ,
+			},
+			want:      13 * time.Hour,
+			assertion: assert.NoError,
+		},
+		{
+			name: "no timezone",
+			rates: &entity.ExchangeRates{
+				DateLoaded: "2023-01-02",
+			},
+			assertion: assert.Error,
+		},
+		{
+			name: "bad date",
+			rates: &entity.ExchangeRates{
+				DateLoaded: "02.01.2023",
+				TimeZone:   ruTZ,
+			},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.staleness(tt.rates)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
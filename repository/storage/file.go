@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"my_go/entity"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const fileExtension = ".json"
+
+// Compile time check that file implements Storage interface
+var _ Storage = (*file)(nil)
+
+type file struct {
+	sync.RWMutex
+
+	Path string
+}
+
+// NewFile is a constructor for the file based Storage. Every snapshot is stored as
+// a separate json file <path>/<country>/<date>.json, so no external service is required.
+func NewFile(path string) (Storage, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory %s, err %s", path, err)
+	}
+	return &file{
+		Path: path,
+	}, nil
+}
+
+// Save stores the snapshot of rates to the file. File is replaced atomically
+// so the readers never see partially written snapshot.
+func (f *file) Save(ctx context.Context, rates *entity.ExchangeRates) error {
+	if err := validate(rates); err != nil {
+		return err
+	}
+	if err := validateCountry(rates.Country); err != nil {
+		return err
+	}
+	b, err := json.Marshal(toSnapshot(rates))
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %s", err) // unreachable in tests
+	}
+	dir := filepath.Join(f.Path, rates.Country)
+	date := snapshotDate(rates)
+
+	f.Lock()
+	defer f.Unlock()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s, err %s", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, date+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file in %s, err %s", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s, err %s", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file %s, err %s", tmp.Name(), err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, date+fileExtension))
+}
+
+// Load returns the snapshot of rates stored in the file for the country and date
+func (f *file) Load(ctx context.Context, country string, date string) (*entity.ExchangeRates, error) {
+	if err := validateCountry(country); err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(date, `/\.`) {
+		return nil, fmt.Errorf("bad date %s", date)
+	}
+	f.RLock()
+	b, err := os.ReadFile(filepath.Join(f.Path, country, date+fileExtension))
+	f.RUnlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates for %s %s, err %s", country, date, err)
+	}
+	var s snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rates for %s %s, err %s", country, date, err)
+	}
+	return fromSnapshot(s)
+}
+
+// Latest returns the snapshot of rates stored in the file with the latest date for the country.
+// Dates are in entity.DateLayout format, so the latest date is the last one in lexical order.
+func (f *file) Latest(ctx context.Context, country string) (*entity.ExchangeRates, error) {
+	if err := validateCountry(country); err != nil {
+		return nil, err
+	}
+	f.RLock()
+	entries, err := os.ReadDir(filepath.Join(f.Path, country))
+	f.RUnlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates for %s, err %s", country, err)
+	}
+	var dates []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExtension) {
+			continue
+		}
+		dates = append(dates, strings.TrimSuffix(e.Name(), fileExtension))
+	}
+	if len(dates) == 0 {
+		return nil, ErrNotFound
+	}
+	sort.Strings(dates)
+	return f.Load(ctx, country, dates[len(dates)-1])
+}
+
+// validateCountry ensures the country can be safely used as a directory name
+func validateCountry(country string) error {
+	if country == "" || strings.ContainsAny(country, `/\.`) {
+		return fmt.Errorf("bad country %s", country)
+	}
+	return nil
+}
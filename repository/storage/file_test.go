@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testRates(country string, date string) *entity.ExchangeRates {
+	tz, _ := time.LoadLocation("Europe/Moscow")
+	return &entity.ExchangeRates{
+		Country:    country,
+		DateLoaded: date,
+		TimeZone:   tz,
+		Rates: map[string]entity.Rate{
+			"USD": {
+				Nominal:          1,
+				BaseCurrency:     "RUB",
+				TargetCurrency:   "USD",
+				RateTargetToBase: decimal.RequireFromString("81.6101"),
+			},
+		},
+	}
+}
+
+func Test_file_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewFile(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-17")))
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-19")))
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-18")))
+
+	// storage created on the same path after restart sees the same data
+	restarted, err := NewFile(dir)
+	assert.NoError(t, err)
+
+	got, err := restarted.Load(ctx, "russia", "2023-04-18")
+	assert.NoError(t, err)
+	assert.Equal(t, testRates("russia", "2023-04-18"), got)
+
+	got, err = restarted.Latest(ctx, "russia")
+	assert.NoError(t, err)
+	assert.Equal(t, testRates("russia", "2023-04-19"), got)
+
+	_, err = restarted.Load(ctx, "russia", "2023-04-20")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = restarted.Latest(ctx, "thailand")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_file_Save(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		rates     *entity.ExchangeRates
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path",
+			rates:     testRates("russia", "2023-04-17"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "nil rates",
+			rates:     nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "empty country",
+			rates:     testRates("", "2023-04-17"),
+			assertion: assert.Error,
+		},
+		{
+			name:      "country is a path",
+			rates:     testRates("../russia", "2023-04-17"),
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad date",
+			rates:     testRates("russia", "../../2023"),
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewFile(t.TempDir())
+			assert.NoError(t, err)
+			tt.assertion(t, s.Save(ctx, tt.rates))
+		})
+	}
+}
+
+func Test_file_Load(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewFile(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "russia"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "russia", "2023-04-17.json"), []byte(`{"country":`), 0o644))
+	assert.NoError(t, os.WriteFile(
+		filepath.Join(dir, "russia", "2023-04-18.json"),
+		[]byte(`{"country":"russia","date_loaded":"2023-04-18","timezone":"Mars/Olympus"}`),
+		0o644,
+	))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "russia", "notes.txt"), []byte(`notes`), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "thailand", "2023-04-18.json"), 0o755))
+
+	tests := []struct {
+		name      string
+		country   string
+		date      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "broken json",
+			country:   "russia",
+			date:      "2023-04-17",
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad timezone",
+			country:   "russia",
+			date:      "2023-04-18",
+			assertion: assert.Error,
+		},
+		{
+			name:      "country is a path",
+			country:   "../russia",
+			date:      "2023-04-18",
+			assertion: assert.Error,
+		},
+		{
+			name:      "date is a path",
+			country:   "russia",
+			date:      "../2023-04-18",
+			assertion: assert.Error,
+		},
+		{
+			name:      "snapshot is a directory",
+			country:   "thailand",
+			date:      "2023-04-18",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Load(ctx, tt.country, tt.date)
+			tt.assertion(t, err)
+			assert.Nil(t, got)
+		})
+	}
+
+	_, err = s.Latest(ctx, "../russia")
+	assert.Error(t, err)
+	_, err = s.Latest(ctx, "thailand")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_file_SaveAndLoad_publication(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFile(t.TempDir())
+	assert.NoError(t, err)
+	rates := testRates("russia", "2023-04-18")
+	rates.EffectiveDate = "2023-04-19"
+	rates.PublishedAt = time.Date(2023, 4, 18, 12, 30, 0, 0, time.UTC)
+	rates.Reference = "075/A/NBP/2023"
+	assert.NoError(t, s.Save(ctx, rates))
+
+	// the rates set for the next day are stored by the date they are set for, not by the date they were loaded on
+	_, err = s.Load(ctx, "russia", "2023-04-18")
+	assert.ErrorIs(t, err, ErrNotFound)
+	got, err := s.Load(ctx, "russia", "2023-04-19")
+	assert.NoError(t, err)
+	assert.Equal(t, rates.EffectiveDate, got.EffectiveDate)
+	assert.Equal(t, rates.Reference, got.Reference)
+	assert.True(t, rates.PublishedAt.Equal(got.PublishedAt))
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"my_go/entity"
+	"sync"
+)
+
+// Compile time check that memory implements Storage interface
+var _ Storage = (*memory)(nil)
+
+type memory struct {
+	sync.RWMutex
+
+	Snapshots map[string]map[string]snapshot // maps country to date to snapshot
+}
+
+// NewMemory is a constructor for the process local Storage.
+// Snapshots are kept as deep copies so the stored data can't be modified by the caller.
+func NewMemory() Storage {
+	return &memory{
+		Snapshots: map[string]map[string]snapshot{},
+	}
+}
+
+// Save stores the snapshot of rates in memory
+func (m *memory) Save(ctx context.Context, rates *entity.ExchangeRates) error {
+	if err := validate(rates); err != nil {
+		return err
+	}
+	s := toSnapshot(rates)
+	s.Rates = copyRates(rates.Rates)
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.Snapshots[rates.Country]; !ok {
+		m.Snapshots[rates.Country] = map[string]snapshot{}
+	}
+	m.Snapshots[rates.Country][snapshotDate(rates)] = s
+	return nil
+}
+
+// Load returns the snapshot of rates stored in memory for the country and date
+func (m *memory) Load(ctx context.Context, country string, date string) (*entity.ExchangeRates, error) {
+	m.RLock()
+	s, ok := m.Snapshots[country][date]
+	m.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.Rates = copyRates(s.Rates)
+	return fromSnapshot(s)
+}
+
+// Latest returns the snapshot of rates stored in memory with the latest date for the country
+func (m *memory) Latest(ctx context.Context, country string) (*entity.ExchangeRates, error) {
+	m.RLock()
+	latest := ""
+	for date := range m.Snapshots[country] {
+		if date > latest {
+			latest = date
+		}
+	}
+	m.RUnlock()
+	if latest == "" {
+		return nil, ErrNotFound
+	}
+	return m.Load(ctx, country, latest)
+}
+
+// copyRates returns a deep copy of the rates, so Bid, Ask, Mid and Quotes aren't shared with the caller
+func copyRates(rates map[string]entity.Rate) map[string]entity.Rate {
+	if rates == nil {
+		return nil
+	}
+	res := make(map[string]entity.Rate, len(rates))
+	for k, v := range rates {
+		v.Bid = copyDecimal(v.Bid)
+		v.Ask = copyDecimal(v.Ask)
+		v.Mid = copyDecimal(v.Mid)
+		if v.Quotes != nil {
+			quotes := make(map[string]decimal.Decimal, len(v.Quotes))
+			for t, q := range v.Quotes {
+				quotes[t] = q
+			}
+			v.Quotes = quotes
+		}
+		res[k] = v
+	}
+	return res
+}
+
+func copyDecimal(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+	v := *d
+	return &v
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"my_go/entity"
+	"testing"
+	"time"
+)
+
+func Test_memory_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+
+	assert.Error(t, s.Save(ctx, nil))
+	assert.Error(t, s.Save(ctx, testRates("russia", "yesterday")))
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-17")))
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-19")))
+	assert.NoError(t, s.Save(ctx, testRates("russia", "2023-04-18")))
+
+	got, err := s.Load(ctx, "russia", "2023-04-18")
+	assert.NoError(t, err)
+	assert.Equal(t, testRates("russia", "2023-04-18"), got)
+
+	// modification of the loaded rates doesn't affect the stored ones
+	got.Rates["USD"] = entity.Rate{}
+	got, err = s.Latest(ctx, "russia")
+	assert.NoError(t, err)
+	assert.Equal(t, testRates("russia", "2023-04-19"), got)
+
+	_, err = s.Load(ctx, "russia", "2023-04-20")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Latest(ctx, "thailand")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_memory_SaveAndLoad_quotes(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	ratesWithQuotes := func() *entity.ExchangeRates {
+		r := testRates("thailand", "2023-04-18")
+		bid, ask, mid := decimal.RequireFromString("34"), decimal.RequireFromString("35"), decimal.RequireFromString("34.5")
+		r.Rates["USD"] = entity.Rate{
+			Nominal:          1,
+			BaseCurrency:     "THB",
+			TargetCurrency:   "USD",
+			RateTargetToBase: mid,
+			Bid:              &bid,
+			Ask:              &ask,
+			Mid:              &mid,
+			Quotes:           map[string]decimal.Decimal{entity.RateTypeSelling: ask},
+		}
+		return r
+	}
+
+	// modification of the saved rates doesn't affect the stored ones
+	saved := ratesWithQuotes()
+	assert.NoError(t, s.Save(ctx, saved))
+	*saved.Rates["USD"].Bid = decimal.Zero
+	saved.Rates["USD"].Quotes[entity.RateTypeSelling] = decimal.Zero
+
+	got, err := s.Load(ctx, "thailand", "2023-04-18")
+	assert.NoError(t, err)
+	assert.Equal(t, ratesWithQuotes(), got)
+
+	// modification of the loaded rates doesn't affect the stored ones
+	*got.Rates["USD"].Ask = decimal.Zero
+	*got.Rates["USD"].Mid = decimal.Zero
+	got.Rates["USD"].Quotes[entity.RateTypeBuyingSight] = decimal.Zero
+	got, err = s.Load(ctx, "thailand", "2023-04-18")
+	assert.NoError(t, err)
+	assert.Equal(t, ratesWithQuotes(), got)
+}
+
+func Test_memory_SaveAndLoad_publication(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	rates := testRates("russia", "2023-04-18")
+	rates.EffectiveDate = "2023-04-19"
+	rates.PublishedAt = time.Date(2023, 4, 18, 12, 30, 0, 0, time.UTC)
+	assert.NoError(t, s.Save(ctx, rates))
+
+	// the rates set for the next day are stored by the date they are set for, not by the date they were loaded on
+	_, err := s.Load(ctx, "russia", "2023-04-18")
+	assert.ErrorIs(t, err, ErrNotFound)
+	got, err := s.Load(ctx, "russia", "2023-04-19")
+	assert.NoError(t, err)
+	assert.Equal(t, rates, got)
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/config"
+	internalconfig "my_go/config"
+	"my_go/entity"
+	"time"
+)
+
+const (
+	configKey = "storage"
+
+	// BackendFile is the embedded file based storage, used by default
+	BackendFile = "file"
+	// BackendMemory is the process local storage, data is lost on restart
+	BackendMemory = "memory"
+
+	defaultPath = "data/rates"
+)
+
+// ErrNotFound is returned when no rates are stored for the requested country and date
+var ErrNotFound = errors.New("rates not found")
+
+// Storage is an interface of the persistent storage of central bank rates.
+// Every snapshot of entity.ExchangeRates is stored by country and the date the rates are set for by the central bank
+// (EffectiveDate, DateLoaded if the bank provides no effective date). The rates loaded in advance (e.g. the next day
+// rates of bank of Russia) or carried over a weekend never take the place of the rates of the date they were loaded on.
+type Storage interface {
+	// Save stores the snapshot of rates, the snapshot stored for the same country and date is replaced
+	Save(ctx context.Context, rates *entity.ExchangeRates) error
+	// Load returns the snapshot of rates stored for the country and date or ErrNotFound
+	Load(ctx context.Context, country string, date string) (*entity.ExchangeRates, error)
+	// Latest returns the snapshot of rates with the latest date stored for the country or ErrNotFound
+	Latest(ctx context.Context, country string) (*entity.ExchangeRates, error)
+}
+
+// New is a constructor for the Storage interface. Backend is selected based on the config,
+// if no backend is configured the file based one is used.
+func New(c config.Provider) (Storage, error) {
+	var cfg internalconfig.StorageConfig
+	err := c.Get(configKey).Populate(&cfg)
+	if err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	switch cfg.Backend {
+	case "", BackendFile:
+		path := cfg.Path
+		if path == "" {
+			path = defaultPath
+		}
+		return NewFile(path)
+	case BackendMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend %s", cfg.Backend)
+	}
+}
+
+// snapshot is a serializable representation of entity.ExchangeRates
+type snapshot struct {
+	Country       string                 `json:"country"`
+	DateLoaded    string                 `json:"date_loaded"`
+	EffectiveDate string                 `json:"effective_date,omitempty"`
+	PublishedAt   *time.Time             `json:"published_at,omitempty"`
+	Reference     string                 `json:"reference,omitempty"`
+	TimeZone      string                 `json:"timezone"`
+	Rates         map[string]entity.Rate `json:"rates"`
+}
+
+func toSnapshot(r *entity.ExchangeRates) snapshot {
+	tz := ""
+	if r.TimeZone != nil {
+		tz = r.TimeZone.String()
+	}
+	var publishedAt *time.Time
+	if !r.PublishedAt.IsZero() {
+		t := r.PublishedAt
+		publishedAt = &t
+	}
+	return snapshot{
+		Country:       r.Country,
+		DateLoaded:    r.DateLoaded,
+		EffectiveDate: r.EffectiveDate,
+		PublishedAt:   publishedAt,
+		Reference:     r.Reference,
+		TimeZone:      tz,
+		Rates:         r.Rates,
+	}
+}
+
+func fromSnapshot(s snapshot) (*entity.ExchangeRates, error) {
+	tz, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("bad tz %s stored, err %s", s.TimeZone, err)
+	}
+	var publishedAt time.Time
+	if s.PublishedAt != nil {
+		publishedAt = *s.PublishedAt
+	}
+	return &entity.ExchangeRates{
+		Country:       s.Country,
+		DateLoaded:    s.DateLoaded,
+		EffectiveDate: s.EffectiveDate,
+		PublishedAt:   publishedAt,
+		Reference:     s.Reference,
+		TimeZone:      tz,
+		Rates:         s.Rates,
+	}, nil
+}
+
+// snapshotDate returns the date the snapshot of rates is stored by
+func snapshotDate(r *entity.ExchangeRates) string {
+	if r.EffectiveDate != "" {
+		return r.EffectiveDate
+	}
+	return r.DateLoaded
+}
+
+func validate(r *entity.ExchangeRates) error {
+	if r == nil {
+		return errors.New("nil ExchangeRates")
+	}
+	if r.Country == "" {
+		return errors.New("empty country")
+	}
+	if _, err := time.Parse(entity.DateLayout, r.DateLoaded); err != nil {
+		return fmt.Errorf("bad date %s, err %s", r.DateLoaded, err)
+	}
+	if _, err := time.Parse(entity.DateLayout, snapshotDate(r)); err != nil {
+		return fmt.Errorf("bad effective date %s, err %s", r.EffectiveDate, err)
+	}
+	return nil
+}
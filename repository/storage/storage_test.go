@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name      string
+		yaml      string
+		want      Storage
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Happy path, file backend",
+			yaml:      `{"storage":{"backend":"file","path":"` + filepath.Join(dir, "rates") + `"}}`,
+			want:      &file{Path: filepath.Join(dir, "rates")},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Happy path, memory backend",
+			yaml:      `{"storage":{"backend":"memory"}}`,
+			want:      NewMemory(),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "unsupported backend",
+			yaml:      `{"storage":{"backend":"postgres"}}`,
+			want:      nil,
+			assertion: assert.Error,
+		},
+		{
+			name:      "file backend, directory can't be created",
+			yaml:      `{"storage":{"backend":"file","path":"/dev/null/rates"}}`,
+			want:      nil,
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := config.NewYAML(config.Source(strings.NewReader(tt.yaml)))
+			got, err := New(provider)
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"math/rand"
+	internalconfig "my_go/config"
+	"my_go/gateway"
+	"my_go/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	configKey = "scheduler"
+
+	defaultRetryBackoff = time.Minute
+)
+
+// Scheduler proactively refreshes the rates of central banks according to the configured schedules,
+// so the rates are picked up as soon as they are published and requests don't wait for the central bank.
+type Scheduler interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Compile time check that scheduler implements Scheduler interface
+var _ Scheduler = (*scheduler)(nil)
+
+// Params is a container for all Scheduler dependencies
+type Params struct {
+	fx.In
+
+	Config     config.Provider
+	Logger     *zap.Logger
+	Repository repository.CBR
+	Registry   *gateway.Registry
+}
+
+type job struct {
+	country  string
+	schedule cron.Schedule
+}
+
+type scheduler struct {
+	Config     internalconfig.SchedulerConfig
+	Logger     *zap.Logger
+	Repository repository.CBR
+	TimeNow    func() time.Time
+	After      func(d time.Duration) <-chan time.Time
+	Jitter     func(max time.Duration) time.Duration
+
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New is a constructor for the Scheduler interface. Schedules are validated on construction against
+// the registered central banks, so misconfigured schedule fails the start of the service.
+func New(p Params) (Scheduler, error) {
+	var cfg internalconfig.SchedulerConfig
+	if err := p.Config.Get(configKey).Populate(&cfg); err != nil {
+		return nil, err // unreachable in tests, cause provider is populating from valid yaml.
+	}
+	s := &scheduler{
+		Config:     cfg,
+		Logger:     p.Logger,
+		Repository: p.Repository,
+		TimeNow:    time.Now,
+		After:      time.After,
+		Jitter:     randomJitter,
+	}
+	if !cfg.Enabled {
+		return s, nil
+	}
+	countries := make([]string, 0, len(cfg.Schedules))
+	for country := range cfg.Schedules {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	for _, country := range countries {
+		if _, ok := p.Registry.Gateway(country); !ok {
+			return nil, fmt.Errorf("schedule provided for unsupported country %s", country)
+		}
+		schedule, err := cron.ParseStandard(cfg.Schedules[country])
+		if err != nil {
+			return nil, fmt.Errorf("bad schedule %q for country %s: %s", cfg.Schedules[country], country, err)
+		}
+		s.jobs = append(s.jobs, job{
+			country:  country,
+			schedule: schedule,
+		})
+	}
+	return s, nil
+}
+
+// Register binds the scheduler to the application lifecycle
+func Register(lc fx.Lifecycle, s Scheduler) {
+	lc.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+}
+
+// Start runs every scheduled job in background until Stop is called
+func (s *scheduler) Start(_ context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j job) {
+			defer s.wg.Done()
+			s.run(ctx, j)
+		}(j)
+	}
+	return nil
+}
+
+// Stop cancels the running jobs and waits for them to finish
+func (s *scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *scheduler) run(ctx context.Context, j job) {
+	for ctx.Err() == nil {
+		now := s.TimeNow()
+		next := j.schedule.Next(now)
+		if s.Config.Jitter > 0 {
+			next = next.Add(s.Jitter(s.Config.Jitter))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.After(next.Sub(now)):
+		}
+		s.refresh(ctx, j.country)
+	}
+}
+
+// refresh refreshes the rates of the country, failed refresh is retried with exponential backoff
+// up to RetryAttempts times.
+func (s *scheduler) refresh(ctx context.Context, country string) {
+	log := s.logger("refresh")
+	backoff := s.Config.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err := s.Repository.RefreshCBRates(ctx, country)
+		if err == nil {
+			log.Infof("%s rates refreshed", country)
+			return
+		}
+		if attempt >= s.Config.RetryAttempts {
+			log.Errorf("failed to refresh %s rates, giving up after %d retries: %s", country, attempt, err)
+			return
+		}
+		log.Warnf("failed to refresh %s rates, retrying in %s: %s", country, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (s *scheduler) logger(function string) *zap.SugaredLogger {
+	return s.Logger.With(
+		zap.String("scope", "scheduler"),
+		zap.String("function", function),
+	).Sugar()
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
+}
@@ -0,0 +1,226 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/config"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	internalconfig "my_go/config"
+	"my_go/gateway"
+	russiagatewaymock "my_go/mocks/gateway/russia"
+	thailandgatewaymock "my_go/mocks/gateway/thailand"
+	repositorymock "my_go/mocks/repository"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tests := []struct {
+		name              string
+		yaml              string
+		expectedCountries []string
+		assertion         assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Happy path",
+			yaml: `{"scheduler":{"enabled":true,"schedules":{` +
+				`"thailand":"CRON_TZ=Asia/Bangkok 1 0,18 * * *","russia":"CRON_TZ=Europe/Moscow 1 0,16 * * *"}}}`,
+			expectedCountries: []string{"russia", "thailand"},
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "disabled",
+			yaml:              `{"scheduler":{"enabled":false,"schedules":{"russia":"1 0,16 * * *"}}}`,
+			expectedCountries: nil,
+			assertion:         assert.NoError,
+		},
+		{
+			name:              "no config",
+			yaml:              `{}`,
+			expectedCountries: nil,
+			assertion:         assert.NoError,
+		},
+		{
+			name:      "bad schedule",
+			yaml:      `{"scheduler":{"enabled":true,"schedules":{"russia":"every day"}}}`,
+			assertion: assert.Error,
+		},
+		{
+			name:      "unsupported country",
+			yaml:      `{"scheduler":{"enabled":true,"schedules":{"atlantis":"1 0,16 * * *"}}}`,
+			assertion: assert.Error,
+		},
+	}
+	registry, _ := gateway.NewRegistry(gateway.RegistryParams{
+		Registrations: []gateway.Registration{
+			{Bank: gateway.Bank{Country: "russia"}, Gateway: russiagatewaymock.NewMockGateway(ctrl)},
+			{Bank: gateway.Bank{Country: "thailand"}, Gateway: thailandgatewaymock.NewMockGateway(ctrl)},
+		},
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, _ := config.NewYAML(config.Source(strings.NewReader(tt.yaml)))
+			got, err := New(Params{
+				Config:     provider,
+				Logger:     zap.NewNop(),
+				Repository: repositorymock.NewMockCBR(ctrl),
+				Registry:   registry,
+			})
+			tt.assertion(t, err)
+			if err != nil {
+				assert.Nil(t, got)
+				return
+			}
+			var countries []string
+			for _, j := range got.(*scheduler).jobs {
+				countries = append(countries, j.country)
+			}
+			assert.Equal(t, tt.expectedCountries, countries)
+		})
+	}
+}
+
+func Test_scheduler_run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ruTZ, _ := time.LoadLocation("Europe/Moscow")
+	schedule, _ := cron.ParseStandard("CRON_TZ=Europe/Moscow 1 0,16 * * *")
+	now := time.Date(2023, 4, 19, 15, 0, 0, 0, ruTZ)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := repositorymock.NewMockCBR(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().RefreshCBRates(ctx, "russia").Times(2).Return(errors.New("some error")),
+		repo.EXPECT().RefreshCBRates(ctx, "russia").DoAndReturn(func(ctx context.Context, country string) error {
+			cancel()
+			return nil
+		}),
+	)
+	var waits []time.Duration
+	s := &scheduler{
+		Config: internalconfig.SchedulerConfig{
+			Jitter:        time.Minute,
+			RetryAttempts: 3,
+			RetryBackoff:  time.Second,
+		},
+		Logger:     zap.NewNop(),
+		Repository: repo,
+		TimeNow: func() time.Time {
+			return now
+		},
+		After: func(d time.Duration) <-chan time.Time {
+			waits = append(waits, d)
+			ch := make(chan time.Time, 1)
+			ch <- time.Time{}
+			return ch
+		},
+		Jitter: func(max time.Duration) time.Duration {
+			return max / 2
+		},
+	}
+	s.run(ctx, job{
+		country:  "russia",
+		schedule: schedule,
+	})
+	// wait till 16:01 plus jitter and then 2 retries with backoff
+	assert.Equal(t, []time.Duration{61*time.Minute + 30*time.Second, time.Second, 2 * time.Second}, waits)
+}
+
+func Test_scheduler_refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tests := []struct {
+		name          string
+		retryAttempts int
+		errs          []error
+		expectedWaits []time.Duration
+	}{
+		{
+			name:          "Happy path",
+			retryAttempts: 2,
+			errs:          []error{nil},
+			expectedWaits: nil,
+		},
+		{
+			name:          "succeeded after retry",
+			retryAttempts: 2,
+			errs:          []error{errors.New("some error"), nil},
+			expectedWaits: []time.Duration{time.Second},
+		},
+		{
+			name:          "retries exhausted",
+			retryAttempts: 2,
+			errs:          []error{errors.New("some error"), errors.New("some error"), errors.New("some error")},
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:          "no retries",
+			retryAttempts: 0,
+			errs:          []error{errors.New("some error")},
+			expectedWaits: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repositorymock.NewMockCBR(ctrl)
+			var calls []*gomock.Call
+			for _, err := range tt.errs {
+				calls = append(calls, repo.EXPECT().RefreshCBRates(ctx, "thailand").Return(err))
+			}
+			gomock.InOrder(calls...)
+			var waits []time.Duration
+			s := &scheduler{
+				Config: internalconfig.SchedulerConfig{
+					RetryAttempts: tt.retryAttempts,
+					RetryBackoff:  time.Second,
+				},
+				Logger:     zap.NewNop(),
+				Repository: repo,
+				After: func(d time.Duration) <-chan time.Time {
+					waits = append(waits, d)
+					ch := make(chan time.Time, 1)
+					ch <- time.Time{}
+					return ch
+				},
+			}
+			s.refresh(ctx, "thailand")
+			assert.Equal(t, tt.expectedWaits, waits)
+		})
+	}
+}
+
+func Test_scheduler_StartStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	schedule, _ := cron.ParseStandard("* * * * *")
+	waiting := &sync.WaitGroup{}
+	waiting.Add(2)
+	s := &scheduler{
+		Logger:     zap.NewNop(),
+		Repository: repositorymock.NewMockCBR(ctrl),
+		TimeNow:    time.Now,
+		After: func(d time.Duration) <-chan time.Time {
+			waiting.Done()
+			return nil // scheduled time never comes
+		},
+		jobs: []job{
+			{country: "russia", schedule: schedule},
+			{country: "thailand", schedule: schedule},
+		},
+	}
+	lc := fxtest.NewLifecycle(t)
+	Register(lc, s)
+	lc.RequireStart()
+	waiting.Wait()
+	lc.RequireStop()
+}